@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"sync"
@@ -14,7 +15,7 @@ import (
 	"github.com/sylvain/postgresql-mcp/internal/app"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 
-	_ "github.com/lib/pq"
+	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 // Integration tests use testcontainers to spin up PostgreSQL instances
@@ -50,7 +51,7 @@ func setupTestContainer(t *testing.T) (*postgres.PostgresContainer, string, func
 	require.NoError(t, err)
 
 	// Test that we can actually connect
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("pgx", connStr)
 	require.NoError(t, err)
 	defer db.Close()
 
@@ -80,7 +81,7 @@ func setupTestDatabase(t *testing.T) (*sql.DB, string, func()) {
 	_, connectionString, containerCleanup := setupTestContainer(t)
 
 	// Connect to PostgreSQL
-	db, err := sql.Open("postgres", connectionString)
+	db, err := sql.Open("pgx", connectionString)
 	require.NoError(t, err)
 
 	// Test connection
@@ -305,6 +306,472 @@ func TestIntegration_App_ListTablesWithSize(t *testing.T) {
 	}
 }
 
+func TestIntegration_App_ListTablesRelationKinds(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE TABLE test_mcp_schema.events (id INT, happened DATE) PARTITION BY RANGE (happened)`,
+		`CREATE TABLE test_mcp_schema.events_2024 PARTITION OF test_mcp_schema.events
+			FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')`,
+		`CREATE TABLE test_mcp_schema.events_2025 PARTITION OF test_mcp_schema.events
+			FOR VALUES FROM ('2025-01-01') TO ('2026-01-01')`,
+		`INSERT INTO test_mcp_schema.events VALUES (1, '2024-06-01'), (2, '2025-06-01'), (3, '2025-07-01')`,
+		`CREATE VIEW test_mcp_schema.active_users AS SELECT * FROM test_mcp_schema.test_users WHERE active`,
+		`CREATE MATERIALIZED VIEW test_mcp_schema.user_counts AS SELECT count(*) AS n FROM test_mcp_schema.test_users`,
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	for _, includeSize := range []bool{false, true} {
+		tables, err := appInstance.ListTables(ctx, &app.ListTablesOptions{
+			Schema:      "test_mcp_schema",
+			IncludeSize: includeSize,
+		})
+		require.NoError(t, err)
+
+		byName := make(map[string]*app.TableInfo, len(tables))
+		for _, table := range tables {
+			byName[table.Name] = table
+		}
+
+		require.Contains(t, byName, "events")
+		assert.Equal(t, app.TableTypePartitionedTable, byName["events"].Type)
+		assert.Equal(t, []string{"test_mcp_schema.events_2024", "test_mcp_schema.events_2025"}, byName["events"].Partitions)
+
+		require.Contains(t, byName, "events_2024")
+		assert.Equal(t, app.TableTypeTable, byName["events_2024"].Type)
+		assert.Equal(t, "test_mcp_schema.events", byName["events_2024"].PartitionOf)
+		assert.Equal(t, "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')", byName["events_2024"].PartitionBound)
+
+		assert.Equal(t, app.TableTypeView, byName["active_users"].Type)
+		assert.Equal(t, app.TableTypeMaterializedView, byName["user_counts"].Type)
+
+		if includeSize {
+			assert.Equal(t, int64(3), byName["events"].RowCount, "partitioned tables sum their partitions")
+			assert.NotEmpty(t, byName["user_counts"].Size)
+		}
+	}
+
+	for _, includeSize := range []bool{false, true} {
+		for pattern, want := range map[string][]string{
+			"events%":       {"events_2024", "events_2025"},
+			`EVENTS\_2024`:  {"events_2024"},
+			`events\_202_x`: nil,
+		} {
+			tables, err := appInstance.ListTables(ctx, &app.ListTablesOptions{
+				Schema:      "test_mcp_schema",
+				Types:       []string{app.TableTypeTable},
+				Pattern:     pattern,
+				IncludeSize: includeSize,
+			})
+			require.NoError(t, err)
+			var names []string
+			for _, table := range tables {
+				names = append(names, table.Name)
+			}
+			assert.Equal(t, want, names, "pattern %q, include_size=%v", pattern, includeSize)
+		}
+	}
+}
+
+func TestIntegration_App_DescribePartitions(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE TABLE test_mcp_schema.events (id INT, region TEXT, happened DATE) PARTITION BY RANGE (happened)`,
+		`CREATE TABLE test_mcp_schema.events_2024 PARTITION OF test_mcp_schema.events
+			FOR VALUES FROM ('2024-01-01') TO ('2025-01-01') PARTITION BY LIST (region)`,
+		`CREATE TABLE test_mcp_schema.events_2024_eu PARTITION OF test_mcp_schema.events_2024 FOR VALUES IN ('eu')`,
+		`CREATE TABLE test_mcp_schema.events_2024_us PARTITION OF test_mcp_schema.events_2024 FOR VALUES IN ('us')`,
+		`CREATE TABLE test_mcp_schema.events_default PARTITION OF test_mcp_schema.events DEFAULT`,
+		`INSERT INTO test_mcp_schema.events
+			SELECT g, CASE WHEN g % 2 = 0 THEN 'eu' ELSE 'us' END, DATE '2024-01-01' + (g % 300)
+			FROM generate_series(1, 1000) g`,
+		`ANALYZE test_mcp_schema.events`,
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	tree, err := appInstance.DescribePartitions(ctx, "test_mcp_schema", "events")
+	require.NoError(t, err)
+	assert.Equal(t, app.PartitionStrategyRange, tree.Strategy)
+	assert.Equal(t, "happened", tree.Key)
+	assert.Equal(t, "test_mcp_schema.events_default", tree.DefaultPartition)
+	assert.Equal(t, int64(1000), tree.RowEstimate)
+	require.Len(t, tree.Partitions, 4)
+
+	byName := make(map[string]*app.PartitionInfo, len(tree.Partitions))
+	for _, partition := range tree.Partitions {
+		byName[partition.Name] = partition
+	}
+
+	sub := byName["test_mcp_schema.events_2024"]
+	require.NotNil(t, sub)
+	assert.Equal(t, 1, sub.Level)
+	assert.False(t, sub.IsLeaf)
+	assert.Equal(t, app.PartitionStrategyList, sub.Strategy)
+	assert.Equal(t, "region", sub.Key)
+	assert.Equal(t, int64(1000), sub.RowEstimate)
+
+	eu := byName["test_mcp_schema.events_2024_eu"]
+	require.NotNil(t, eu)
+	assert.Equal(t, 2, eu.Level)
+	assert.True(t, eu.IsLeaf)
+	assert.Equal(t, "test_mcp_schema.events_2024", eu.Parent)
+	assert.Equal(t, "FOR VALUES IN ('eu')", eu.Bound)
+	assert.Equal(t, int64(500), eu.RowEstimate)
+
+	assert.True(t, byName["test_mcp_schema.events_default"].IsDefault)
+
+	_, err = appInstance.DescribePartitions(ctx, "test_mcp_schema", "test_users")
+	assert.ErrorIs(t, err, app.ErrNotPartitioned)
+
+	_, err = appInstance.DescribePartitions(ctx, "test_mcp_schema", "missing")
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+}
+
+func TestIntegration_App_GetViewDefinition(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE VIEW test_mcp_schema.active_users AS
+			SELECT id, name FROM test_mcp_schema.test_users WHERE active`,
+		`CREATE VIEW test_mcp_schema.user_summary AS
+			SELECT u.id, count(*) AS n
+			FROM test_mcp_schema.active_users u
+			JOIN test_mcp_schema.test_users t ON t.id = u.id
+			GROUP BY u.id`,
+		`CREATE MATERIALIZED VIEW test_mcp_schema.user_counts AS
+			SELECT count(*) AS n FROM test_mcp_schema.test_users WITH NO DATA`,
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	def, err := appInstance.GetViewDefinition(ctx, "test_mcp_schema", "active_users")
+	require.NoError(t, err)
+	assert.Equal(t, app.TableTypeView, def.Type)
+	assert.Contains(t, def.Definition, "WHERE active")
+	assert.True(t, def.IsUpdatable)
+	assert.True(t, def.IsInsertable)
+	assert.Nil(t, def.IsPopulated)
+	assert.Equal(t, []*app.RelationName{
+		{Schema: "test_mcp_schema", Name: "test_users", Type: app.TableTypeTable},
+	}, def.BaseRelations)
+
+	def, err = appInstance.GetViewDefinition(ctx, "test_mcp_schema", "user_summary")
+	require.NoError(t, err)
+	assert.False(t, def.IsUpdatable, "aggregating views are not automatically updatable")
+	assert.Equal(t, []*app.RelationName{
+		{Schema: "test_mcp_schema", Name: "active_users", Type: app.TableTypeView},
+		{Schema: "test_mcp_schema", Name: "test_users", Type: app.TableTypeTable},
+	}, def.BaseRelations)
+
+	def, err = appInstance.GetViewDefinition(ctx, "test_mcp_schema", "user_counts")
+	require.NoError(t, err)
+	assert.Equal(t, app.TableTypeMaterializedView, def.Type)
+	require.NotNil(t, def.IsPopulated)
+	assert.False(t, *def.IsPopulated)
+	assert.False(t, def.IsUpdatable)
+
+	_, err = appInstance.GetViewDefinition(ctx, "test_mcp_schema", "test_users")
+	assert.ErrorIs(t, err, app.ErrNotAView)
+
+	_, err = appInstance.GetViewDefinition(ctx, "test_mcp_schema", "missing")
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+}
+
+func TestIntegration_App_FunctionsAndTriggers(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE FUNCTION test_mcp_schema.touch() RETURNS trigger LANGUAGE plpgsql AS $$
+			BEGIN NEW.name := trim(NEW.name); RETURN NEW; END $$`,
+		`CREATE FUNCTION test_mcp_schema.add(a integer, b integer DEFAULT 1) RETURNS integer
+			LANGUAGE sql IMMUTABLE AS 'SELECT a + b'`,
+		`CREATE FUNCTION test_mcp_schema.add(a numeric, b numeric) RETURNS numeric
+			LANGUAGE sql IMMUTABLE SECURITY DEFINER AS 'SELECT a + b'`,
+		`CREATE PROCEDURE test_mcp_schema.purge() LANGUAGE sql AS 'SELECT 1'`,
+		`CREATE TRIGGER users_touch BEFORE INSERT OR UPDATE OF name ON test_mcp_schema.test_users
+			FOR EACH ROW WHEN (NEW.name IS NOT NULL) EXECUTE FUNCTION test_mcp_schema.touch()`,
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	functions, err := appInstance.ListFunctions(ctx, "test_mcp_schema")
+	require.NoError(t, err)
+	require.Len(t, functions, 4)
+	assert.Equal(t, "add", functions[0].Name)
+	assert.Equal(t, "a integer, b integer DEFAULT 1", functions[0].Arguments)
+	assert.Equal(t, "integer", functions[0].ReturnType)
+	assert.Equal(t, "immutable", functions[0].Volatility)
+	assert.True(t, functions[1].SecurityDefiner)
+	assert.Equal(t, app.FunctionKindProcedure, functions[2].Kind)
+	assert.Empty(t, functions[2].ReturnType)
+	assert.Equal(t, "plpgsql", functions[3].Language)
+
+	definitions, err := appInstance.GetFunctionDefinition(ctx, "test_mcp_schema", "add", "")
+	require.NoError(t, err)
+	assert.Len(t, definitions, 2)
+
+	definitions, err = appInstance.GetFunctionDefinition(ctx, "test_mcp_schema", "add", "int, int")
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+	assert.Contains(t, definitions[0].Definition, "CREATE OR REPLACE FUNCTION test_mcp_schema.add(a integer")
+
+	_, err = appInstance.GetFunctionDefinition(ctx, "test_mcp_schema", "add", "text")
+	assert.ErrorIs(t, err, app.ErrFunctionNotFound)
+
+	triggers, err := appInstance.ListTriggers(ctx, "test_mcp_schema", "test_users")
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+	assert.Equal(t, app.TriggerTimingBefore, triggers[0].Timing)
+	assert.Equal(t, []string{app.TriggerEventInsert, app.TriggerEventUpdate}, triggers[0].Events)
+	assert.Equal(t, "row", triggers[0].Level)
+	assert.Equal(t, []string{"name"}, triggers[0].UpdateColumns)
+	assert.Equal(t, "(new.name IS NOT NULL)", triggers[0].Condition)
+	assert.Equal(t, "test_mcp_schema.touch", triggers[0].Function)
+	assert.True(t, triggers[0].Enabled)
+}
+
+func TestIntegration_App_TypesAndSequences(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE TYPE test_mcp_schema.order_status AS ENUM ('pending', 'paid', 'shipped')`,
+		`ALTER TYPE test_mcp_schema.order_status ADD VALUE 'cancelled' BEFORE 'paid'`,
+		`CREATE DOMAIN test_mcp_schema.email AS varchar(320) NOT NULL CONSTRAINT email_at CHECK (VALUE LIKE '%@%')`,
+		`CREATE TYPE test_mcp_schema.money_amount AS (amount numeric(12,2), currency char(3))`,
+		`CREATE TYPE test_mcp_schema.float_range AS RANGE (subtype = float8)`,
+		`CREATE TABLE test_mcp_schema.orders (id integer GENERATED ALWAYS AS IDENTITY, ref serial)`,
+		`CREATE SEQUENCE test_mcp_schema.invoice_no INCREMENT BY 10 START WITH 1000`,
+		`SELECT nextval('test_mcp_schema.invoice_no')`,
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	types, err := appInstance.ListTypes(ctx, "test_mcp_schema")
+	require.NoError(t, err)
+	byName := map[string]*app.TypeInfo{}
+	for _, info := range types {
+		byName[info.Name] = info
+	}
+	assert.NotContains(t, byName, "orders", "table row types are not listed")
+
+	require.Contains(t, byName, "order_status")
+	assert.Equal(t, app.TypeKindEnum, byName["order_status"].Kind)
+	assert.Equal(t, []string{"pending", "cancelled", "paid", "shipped"}, byName["order_status"].Labels)
+
+	require.Contains(t, byName, "email")
+	assert.Equal(t, "character varying(320)", byName["email"].BaseType)
+	assert.True(t, byName["email"].NotNull)
+	require.Len(t, byName["email"].Constraints, 1)
+	assert.Equal(t, "email_at", byName["email"].Constraints[0].Name)
+
+	require.Contains(t, byName, "money_amount")
+	assert.Equal(t, []*app.CompositeAttribute{
+		{Name: "amount", DataType: "numeric(12,2)"},
+		{Name: "currency", DataType: "character(3)"},
+	}, byName["money_amount"].Attributes)
+
+	require.Contains(t, byName, "float_range")
+	assert.Equal(t, "double precision", byName["float_range"].Subtype)
+
+	sequences, err := appInstance.ListSequences(ctx, "test_mcp_schema")
+	require.NoError(t, err)
+	require.Len(t, sequences, 4)
+
+	invoice := sequences[0]
+	assert.Equal(t, "invoice_no", invoice.Name)
+	assert.Equal(t, int64(10), invoice.Increment)
+	require.NotNil(t, invoice.LastValue)
+	assert.Equal(t, int64(1000), *invoice.LastValue)
+	assert.Empty(t, invoice.OwnedBy)
+
+	assert.Equal(t, "test_mcp_schema.orders.id", sequences[1].OwnedBy)
+	assert.True(t, sequences[1].Identity)
+	assert.Nil(t, sequences[1].LastValue, "unused sequences have no last value")
+	assert.Equal(t, "test_mcp_schema.orders.ref", sequences[2].OwnedBy)
+	assert.False(t, sequences[2].Identity)
+	assert.Equal(t, "test_mcp_schema.test_users.id", sequences[3].OwnedBy)
+}
+
+func TestIntegration_App_ExtensionsAndSettings(t *testing.T) {
+	_, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	ctx := context.Background()
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	extensions, err := appInstance.ListExtensions(ctx, false)
+	require.NoError(t, err)
+	byName := map[string]*app.ExtensionInfo{}
+	for _, extension := range extensions {
+		byName[extension.Name] = extension
+	}
+	require.Contains(t, byName, "plpgsql")
+	assert.True(t, byName["plpgsql"].Installed)
+	assert.Equal(t, "pg_catalog", byName["plpgsql"].Schema)
+	require.Contains(t, byName, "pg_trgm", "contrib extensions ship with the postgres image")
+	assert.False(t, byName["pg_trgm"].Installed)
+	assert.NotEmpty(t, byName["pg_trgm"].DefaultVersion)
+
+	installed, err := appInstance.ListExtensions(ctx, true)
+	require.NoError(t, err)
+	for _, extension := range installed {
+		assert.True(t, extension.Installed, extension.Name)
+	}
+
+	settings, err := appInstance.GetSettings(ctx, app.SettingsOptions{Name: "WORK_MEM"})
+	require.NoError(t, err)
+	names := make([]string, len(settings))
+	for i, setting := range settings {
+		names[i] = setting.Name
+	}
+	assert.Contains(t, names, "work_mem")
+	assert.Contains(t, names, "maintenance_work_mem")
+
+	settings, err = appInstance.GetSettings(ctx, app.SettingsOptions{Name: "random_page_cost"})
+	require.NoError(t, err)
+	require.Len(t, settings, 1)
+	assert.Equal(t, "4", settings[0].Value)
+	assert.Contains(t, settings[0].Category, "Query Tuning")
+
+	settings, err = appInstance.GetSettings(ctx, app.SettingsOptions{NonDefault: true})
+	require.NoError(t, err)
+	for _, setting := range settings {
+		assert.True(t, setting.NonDefault, setting.Name)
+	}
+}
+
+func TestIntegration_App_RolesPrivilegesAndPolicies(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`DROP ROLE IF EXISTS mcp_readers`,
+		`DROP ROLE IF EXISTS mcp_app`,
+		`CREATE ROLE mcp_readers NOLOGIN`,
+		`CREATE ROLE mcp_app LOGIN IN ROLE mcp_readers`,
+		`GRANT USAGE ON SCHEMA test_mcp_schema TO mcp_readers`,
+		`GRANT SELECT ON test_mcp_schema.test_users TO mcp_readers`,
+		`GRANT UPDATE (name) ON test_mcp_schema.test_users TO mcp_app`,
+		`ALTER TABLE test_mcp_schema.test_users ENABLE ROW LEVEL SECURITY`,
+		`CREATE POLICY active_only ON test_mcp_schema.test_users FOR SELECT TO mcp_readers USING (active)`,
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+	defer func() {
+		for _, stmt := range []string{
+			`DROP OWNED BY mcp_app, mcp_readers`,
+			`DROP ROLE mcp_app`,
+			`DROP ROLE mcp_readers`,
+		} {
+			_, _ = db.ExecContext(context.Background(), stmt)
+		}
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	roles, err := appInstance.ListRoles(ctx)
+	require.NoError(t, err)
+	byName := map[string]*app.RoleInfo{}
+	for _, role := range roles {
+		byName[role.Name] = role
+		assert.NotRegexp(t, "^pg_", role.Name)
+	}
+	require.Contains(t, byName, "mcp_app")
+	assert.True(t, byName["mcp_app"].CanLogin)
+	assert.Equal(t, []string{"mcp_readers"}, byName["mcp_app"].MemberOf)
+
+	privileges, err := appInstance.GetTablePrivileges(ctx, "mcp_app", "test_mcp_schema", "test_users")
+	require.NoError(t, err)
+	require.Len(t, privileges, 1)
+	assert.Equal(t, []string{"SELECT"}, privileges[0].Privileges, "inherited from mcp_readers")
+	assert.Equal(t, []*app.ColumnPrivilege{{Column: "name", Privileges: []string{"UPDATE"}}},
+		privileges[0].ColumnPrivileges)
+	assert.Contains(t, privileges[0].Grants,
+		&app.Grant{Grantee: "mcp_readers", Grantor: "testuser", Privilege: "SELECT"})
+
+	_, err = appInstance.GetTablePrivileges(ctx, "nobody_here", "test_mcp_schema", "")
+	assert.ErrorIs(t, err, app.ErrRoleNotFound)
+
+	_, err = appInstance.GetTablePrivileges(ctx, "", "test_mcp_schema", "missing")
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+
+	tables, err := appInstance.ListPolicies(ctx, "test_mcp_schema", "")
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	assert.Equal(t, "test_users", tables[0].Table)
+	assert.True(t, tables[0].RLSEnabled)
+	assert.False(t, tables[0].RLSForced)
+	require.Len(t, tables[0].Policies, 1)
+	assert.Equal(t, "active_only", tables[0].Policies[0].Name)
+	assert.Equal(t, "SELECT", tables[0].Policies[0].Command)
+	assert.Equal(t, []string{"mcp_readers"}, tables[0].Policies[0].Roles)
+	assert.Equal(t, "active", tables[0].Policies[0].Using)
+}
+
 func TestIntegration_App_DescribeTable(t *testing.T) {
 	_, connectionString, cleanup := setupTestDatabase(t)
 	defer cleanup()
@@ -318,8 +785,9 @@ func TestIntegration_App_DescribeTable(t *testing.T) {
 	err = appInstance.Connect(ctx, connectionString)
 	require.NoError(t, err)
 
-	columns, err := appInstance.DescribeTable(ctx, "test_mcp_schema", "test_users")
-	assert.NoError(t, err)
+	description, err := appInstance.DescribeTable(ctx, "test_mcp_schema", "test_users")
+	require.NoError(t, err)
+	columns := description.Columns
 	assert.NotEmpty(t, columns)
 
 	// Verify expected columns
@@ -352,6 +820,209 @@ func TestIntegration_App_DescribeTable(t *testing.T) {
 	}
 }
 
+func TestIntegration_App_DescribeTableConstraints(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS btree_gist")
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE test_mcp_schema.test_orders (
+			order_id INTEGER,
+			line_no INTEGER,
+			user_id INTEGER,
+			quantity INTEGER CHECK (quantity > 0),
+			during TSRANGE,
+			PRIMARY KEY (order_id, line_no),
+			CONSTRAINT test_orders_user_fk FOREIGN KEY (user_id)
+				REFERENCES test_mcp_schema.test_users (id)
+				ON DELETE CASCADE ON UPDATE SET NULL
+				DEFERRABLE INITIALLY DEFERRED,
+			CONSTRAINT test_orders_no_overlap EXCLUDE USING gist (user_id WITH =, during WITH &&)
+		)`)
+	require.NoError(t, err)
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	description, err := appInstance.DescribeTable(ctx, "test_mcp_schema", "test_orders")
+	require.NoError(t, err)
+	require.Len(t, description.Constraints, 4)
+
+	byType := make(map[string]*app.ConstraintInfo, len(description.Constraints))
+	for _, c := range description.Constraints {
+		byType[c.Type] = c
+	}
+
+	assert.Equal(t, app.ConstraintPrimaryKey, description.Constraints[0].Type, "primary key is listed first")
+	assert.Equal(t, []string{"order_id", "line_no"}, byType[app.ConstraintPrimaryKey].Columns)
+
+	fk := byType[app.ConstraintForeignKey]
+	require.NotNil(t, fk)
+	assert.Equal(t, "test_orders_user_fk", fk.Name)
+	assert.Equal(t, []string{"user_id"}, fk.Columns)
+	assert.Equal(t, "test_mcp_schema", fk.ReferencedSchema)
+	assert.Equal(t, "test_users", fk.ReferencedTable)
+	assert.Equal(t, []string{"id"}, fk.ReferencedColumns)
+	assert.Equal(t, "cascade", fk.OnDelete)
+	assert.Equal(t, "set null", fk.OnUpdate)
+	assert.True(t, fk.Deferrable)
+	assert.True(t, fk.InitiallyDeferred)
+
+	check := byType[app.ConstraintCheck]
+	require.NotNil(t, check)
+	assert.Equal(t, []string{"quantity"}, check.Columns)
+	assert.Contains(t, check.Definition, "quantity > 0")
+	assert.Empty(t, check.OnDelete)
+
+	exclusion := byType[app.ConstraintExclusion]
+	require.NotNil(t, exclusion)
+	assert.Equal(t, []string{"user_id", "during"}, exclusion.Columns)
+
+	users, err := appInstance.DescribeTable(ctx, "test_mcp_schema", "test_users")
+	require.NoError(t, err)
+	types := make([]string, len(users.Constraints))
+	for i, c := range users.Constraints {
+		types[i] = c.Type
+	}
+	assert.Equal(t, []string{app.ConstraintPrimaryKey, app.ConstraintUnique}, types)
+}
+
+func TestIntegration_App_Comments(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		"COMMENT ON DATABASE test_db IS 'Integration test database'",
+		"COMMENT ON SCHEMA test_mcp_schema IS 'Schema used by the MCP tests'",
+		"COMMENT ON TABLE test_mcp_schema.test_users IS 'Registered accounts, one row per person'",
+		"COMMENT ON COLUMN test_mcp_schema.test_users.email IS 'Primary contact address'",
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	databases, err := appInstance.ListDatabases(ctx)
+	require.NoError(t, err)
+	for _, database := range databases {
+		if database.Name == "test_db" {
+			assert.Equal(t, "Integration test database", database.Description)
+		}
+	}
+
+	schemas, err := appInstance.ListSchemas(ctx)
+	require.NoError(t, err)
+	for _, schema := range schemas {
+		if schema.Name == "test_mcp_schema" {
+			assert.Equal(t, "Schema used by the MCP tests", schema.Description)
+		}
+	}
+
+	for _, includeSize := range []bool{false, true} {
+		tables, err := appInstance.ListTables(ctx, &app.ListTablesOptions{
+			Schema:      "test_mcp_schema",
+			IncludeSize: includeSize,
+			Search:      "ACCOUNTS",
+		})
+		require.NoError(t, err)
+		require.Len(t, tables, 1, "include_size=%v", includeSize)
+		assert.Equal(t, "Registered accounts, one row per person", tables[0].Description)
+	}
+
+	description, err := appInstance.DescribeTable(ctx, "test_mcp_schema", "test_users")
+	require.NoError(t, err)
+	for _, col := range description.Columns {
+		if col.Name == "email" {
+			assert.Equal(t, "Primary contact address", col.Description)
+		} else {
+			assert.Empty(t, col.Description, col.Name)
+		}
+	}
+}
+
+func TestIntegration_App_DescribeTableCatalogTypes(t *testing.T) {
+	db, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		"CREATE TYPE test_mcp_schema.mood AS ENUM ('sad', 'ok', 'happy')",
+		"CREATE DOMAIN test_mcp_schema.positive_int AS INTEGER CHECK (VALUE > 0)",
+		`CREATE TABLE test_mcp_schema.test_types (
+			id BIGINT GENERATED ALWAYS AS IDENTITY,
+			code VARCHAR(12) COLLATE "C",
+			price NUMERIC(10, 2),
+			tags TEXT[],
+			moods test_mcp_schema.mood[],
+			current_mood test_mcp_schema.mood,
+			quantity test_mcp_schema.positive_int,
+			total NUMERIC GENERATED ALWAYS AS (price * 2) STORED
+		)`,
+		"ALTER TABLE test_mcp_schema.test_types ALTER COLUMN code SET STATISTICS 500",
+	} {
+		_, err := db.ExecContext(ctx, stmt)
+		require.NoError(t, err, stmt)
+	}
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	description, err := appInstance.DescribeTable(ctx, "test_mcp_schema", "test_types")
+	require.NoError(t, err)
+
+	columns := make(map[string]*app.ColumnInfo, len(description.Columns))
+	for _, col := range description.Columns {
+		columns[col.Name] = col
+		assert.True(t, col.Selectable, col.Name)
+	}
+	require.Len(t, columns, 8)
+
+	assert.Equal(t, "bigint", columns["id"].DataType)
+	assert.Equal(t, "always", columns["id"].Identity)
+	assert.Empty(t, columns["id"].DefaultValue)
+
+	assert.Equal(t, "character varying(12)", columns["code"].DataType)
+	assert.Equal(t, "C", columns["code"].Collation)
+	require.NotNil(t, columns["code"].StatisticsTarget)
+	assert.Equal(t, int64(500), *columns["code"].StatisticsTarget)
+	assert.Nil(t, columns["price"].StatisticsTarget)
+
+	assert.Equal(t, "numeric(10,2)", columns["price"].DataType)
+	assert.Empty(t, columns["price"].Collation)
+
+	assert.Equal(t, "text[]", columns["tags"].DataType)
+	assert.Equal(t, "text", columns["tags"].ElementType)
+
+	assert.Equal(t, "test_mcp_schema.mood[]", columns["moods"].DataType)
+	assert.Equal(t, []string{"sad", "ok", "happy"}, columns["moods"].EnumLabels)
+	assert.Equal(t, []string{"sad", "ok", "happy"}, columns["current_mood"].EnumLabels)
+
+	assert.Equal(t, "test_mcp_schema.positive_int", columns["quantity"].DataType)
+	assert.Equal(t, "integer", columns["quantity"].DomainBaseType)
+
+	assert.Equal(t, "stored", columns["total"].Generated)
+	assert.Equal(t, "(price * (2)::numeric)", columns["total"].GenerationExpression)
+	assert.Empty(t, columns["total"].DefaultValue)
+}
+
 func TestIntegration_App_ExecuteQuery(t *testing.T) {
 	_, connectionString, cleanup := setupTestDatabase(t)
 	defer cleanup()
@@ -387,6 +1058,127 @@ func TestIntegration_App_ExecuteQuery(t *testing.T) {
 	assert.Equal(t, "john@example.com", firstRow[2])
 }
 
+func TestIntegration_App_ExecuteQueryColumnTypes(t *testing.T) {
+	_, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	ctx := context.Background()
+
+	err = appInstance.Connect(ctx, connectionString)
+	require.NoError(t, err)
+
+	result, err := appInstance.ExecuteQuery(ctx, &app.ExecuteQueryOptions{
+		Query: "SELECT id, name, 1.50::numeric(6,2) AS price, ARRAY['a']::text[] AS tags " +
+			"FROM test_mcp_schema.test_users ORDER BY id LIMIT 1",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.ColumnTypes, 4)
+
+	assert.Equal(t, "id", result.ColumnTypes[0].Name)
+	assert.Equal(t, "int4", result.ColumnTypes[0].Type)
+	assert.Equal(t, uint32(23), result.ColumnTypes[0].OID)
+
+	assert.Equal(t, "varchar", result.ColumnTypes[1].Type)
+	require.NotNil(t, result.ColumnTypes[1].Length)
+	assert.Equal(t, int64(255), *result.ColumnTypes[1].Length)
+
+	assert.Equal(t, "numeric", result.ColumnTypes[2].Type)
+	require.NotNil(t, result.ColumnTypes[2].Precision)
+	assert.Equal(t, int64(6), *result.ColumnTypes[2].Precision)
+	assert.Equal(t, int64(2), *result.ColumnTypes[2].Scale)
+
+	assert.Equal(t, "_text", result.ColumnTypes[3].Type)
+
+	// Only table columns that allow NULL are reported as nullable; NOT NULL
+	// ones may still be NULL after an outer join.
+	assert.Nil(t, result.ColumnTypes[0].Nullable)
+	assert.Nil(t, result.ColumnTypes[1].Nullable)
+	assert.Nil(t, result.ColumnTypes[2].Nullable)
+
+	result, err = appInstance.ExecuteQuery(ctx, &app.ExecuteQueryOptions{
+		Query: "SELECT email FROM test_mcp_schema.test_users LIMIT 1",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.ColumnTypes[0].Nullable)
+	assert.True(t, *result.ColumnTypes[0].Nullable)
+}
+
+func TestIntegration_App_ExecuteQueryTypedValues(t *testing.T) {
+	_, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	ctx := context.Background()
+
+	err = appInstance.Connect(ctx, connectionString)
+	require.NoError(t, err)
+
+	result, err := appInstance.ExecuteQuery(ctx, &app.ExecuteQueryOptions{
+		Query: "SELECT '\\x0102'::bytea AS blob, " +
+			"123456789012345678901234567890.5::numeric AS big, " +
+			"'{\"a\": 1}'::jsonb AS doc, " +
+			"ARRAY[[1,2],[3,NULL]]::int4[] AS grid, " +
+			"int4range(1, 10) AS span, " +
+			"'1 mon 2 days 00:00:03'::interval AS gap, " +
+			"'2024-01-02 03:04:05+00'::timestamptz AS at, " +
+			"'NaN'::float8 AS nan",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	row := result.Rows[0]
+
+	assert.Equal(t, "AQI=", row[0])
+	assert.Equal(t, app.EncodingBase64, result.ColumnTypes[0].Encoding)
+	assert.Equal(t, "123456789012345678901234567890.5", row[1])
+	assert.Equal(t, app.EncodingDecimal, result.ColumnTypes[1].Encoding)
+	assert.JSONEq(t, `{"a": 1}`, string(row[2].(json.RawMessage)))
+	assert.Equal(t, []any{[]any{int64(1), int64(2)}, []any{int64(3), nil}}, row[3])
+	assert.Equal(t, &app.Range{Lower: int64(1), Upper: int64(10), LowerInclusive: true}, row[4])
+	assert.Equal(t, &app.Interval{Months: 1, Days: 2, Microseconds: 3000000}, row[5])
+	assert.Regexp(t, `^2024-01-02T03:04:05(Z|\+00:00)$`, row[6])
+	assert.Equal(t, "NaN", row[7])
+
+	_, err = json.Marshal(result)
+	require.NoError(t, err, "every decoded value must be JSON-serializable")
+}
+
+func TestIntegration_App_ExecuteQueryWithParams(t *testing.T) {
+	_, connectionString, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	ctx := context.Background()
+
+	err = appInstance.Connect(ctx, connectionString)
+	require.NoError(t, err)
+
+	query, args, err := app.BindParams(
+		"SELECT :n::int + 1 AS next, :tags::text[] AS tags, (:doc::jsonb)->>'k' AS k",
+		map[string]any{"n": float64(41), "tags": []any{"a", "b c"}, "doc": map[string]any{"k": "v"}})
+	require.NoError(t, err)
+
+	result, err := appInstance.ExecuteQuery(ctx, &app.ExecuteQueryOptions{Query: query, Args: args})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, int64(42), result.Rows[0][0])
+	assert.Equal(t, []any{"a", "b c"}, result.Rows[0][1])
+	assert.Equal(t, "v", result.Rows[0][2])
+
+	plan, err := appInstance.ExplainQuery(ctx, "SELECT * FROM test_mcp_schema.test_users WHERE id = $1", false, int64(1))
+	require.NoError(t, err)
+	assert.NotEmpty(t, plan.Rows)
+}
+
 func TestIntegration_App_ExecuteQueryWithLimit(t *testing.T) {
 	_, connectionString, cleanup := setupTestDatabase(t)
 	defer cleanup()
@@ -461,7 +1253,7 @@ func TestIntegration_App_ListIndexes_SpecialCharacters(t *testing.T) {
 	_, connectionString, cleanup := setupTestContainer(t)
 	defer cleanup()
 
-	db, err := sql.Open("postgres", connectionString)
+	db, err := sql.Open("pgx", connectionString)
 	require.NoError(t, err)
 	defer db.Close()
 
@@ -503,7 +1295,84 @@ func TestIntegration_App_ListIndexes_SpecialCharacters(t *testing.T) {
 	`, testSchema))
 	require.NoError(t, err)
 
-	// Test with app
+	// Test with app
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	err = appInstance.Connect(ctx, connectionString)
+	require.NoError(t, err)
+
+	indexes, err := appInstance.ListIndexes(ctx, testSchema, "test_table")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, indexes)
+
+	// Verify each index is parsed correctly
+	indexMap := make(map[string]*app.IndexInfo)
+	for _, idx := range indexes {
+		indexMap[idx.Name] = idx
+	}
+
+	// Check idx_quoted_name
+	if idx, ok := indexMap["idx_quoted_name"]; ok {
+		assert.Len(t, idx.Columns, 1, "idx_quoted_name should have 1 column")
+		assert.Equal(t, "Column Name", idx.Columns[0], "Column name should be 'Column Name'")
+	} else {
+		t.Error("idx_quoted_name not found")
+	}
+
+	// Check idx_with_commas - should have 2 columns, not split by commas inside quotes
+	if idx, ok := indexMap["idx_with_commas"]; ok {
+		assert.Len(t, idx.Columns, 2, "idx_with_commas should have exactly 2 columns")
+		assert.Contains(t, idx.Columns, "col,with,commas", "Should contain column with commas")
+		assert.Contains(t, idx.Columns, "normal_col", "Should contain normal_col")
+	} else {
+		t.Error("idx_with_commas not found")
+	}
+
+	// Check idx_braces
+	if idx, ok := indexMap["idx_braces"]; ok {
+		assert.Len(t, idx.Columns, 2, "idx_braces should have 2 columns")
+		assert.Contains(t, idx.Columns, "col{value}", "Should contain 'col{value}'")
+		assert.Contains(t, idx.Columns, "col}data", "Should contain 'col}data'")
+	} else {
+		t.Error("idx_braces not found")
+	}
+
+	// Cleanup
+	_, _ = db.ExecContext(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", testSchema))
+}
+
+func TestIntegration_App_ListIndexes_ExpressionsIncludeAndPredicate(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	testSchema := "test_index_details"
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", testSchema))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE SCHEMA %[1]s;
+		CREATE TABLE %[1]s.accounts (
+			id SERIAL PRIMARY KEY,
+			email TEXT NOT NULL,
+			tenant_id INT NOT NULL,
+			name TEXT,
+			deleted_at TIMESTAMP
+		);
+		CREATE INDEX idx_accounts_lower_email ON %[1]s.accounts (lower(email), tenant_id) INCLUDE (name);
+		CREATE UNIQUE INDEX idx_accounts_active_email ON %[1]s.accounts (email) WHERE deleted_at IS NULL;
+		INSERT INTO %[1]s.accounts (email, tenant_id) VALUES ('a@example.com', 1);
+		SELECT * FROM %[1]s.accounts WHERE id = 1;
+	`, testSchema))
+	require.NoError(t, err)
+	defer func() { _, _ = db.ExecContext(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", testSchema)) }()
+
 	appInstance, err := app.NewDefault()
 	require.NoError(t, err)
 	defer appInstance.Disconnect()
@@ -511,44 +1380,174 @@ func TestIntegration_App_ListIndexes_SpecialCharacters(t *testing.T) {
 	err = appInstance.Connect(ctx, connectionString)
 	require.NoError(t, err)
 
-	indexes, err := appInstance.ListIndexes(ctx, testSchema, "test_table")
-	assert.NoError(t, err)
-	assert.NotEmpty(t, indexes)
+	indexes, err := appInstance.ListIndexes(ctx, testSchema, "accounts")
+	require.NoError(t, err)
+	require.Len(t, indexes, 3)
 
-	// Verify each index is parsed correctly
 	indexMap := make(map[string]*app.IndexInfo)
 	for _, idx := range indexes {
 		indexMap[idx.Name] = idx
+		assert.True(t, idx.IsValid, idx.Name)
+		assert.True(t, idx.IsReady, idx.Name)
+		assert.NotEmpty(t, idx.Size, idx.Name)
+		assert.Contains(t, idx.Definition, "CREATE", idx.Name)
 	}
 
-	// Check idx_quoted_name
-	if idx, ok := indexMap["idx_quoted_name"]; ok {
-		assert.Len(t, idx.Columns, 1, "idx_quoted_name should have 1 column")
-		assert.Equal(t, "Column Name", idx.Columns[0], "Column name should be 'Column Name'")
-	} else {
-		t.Error("idx_quoted_name not found")
-	}
+	expression := indexMap["idx_accounts_lower_email"]
+	require.NotNil(t, expression)
+	assert.Equal(t, []string{"lower(email)", "tenant_id"}, expression.Columns)
+	assert.Equal(t, []string{"lower(email)"}, expression.Expressions)
+	assert.Equal(t, []string{"name"}, expression.IncludeColumns)
+	assert.Empty(t, expression.Predicate)
+	assert.Contains(t, expression.Definition, "INCLUDE (name)")
+
+	partial := indexMap["idx_accounts_active_email"]
+	require.NotNil(t, partial)
+	assert.Equal(t, []string{"email"}, partial.Columns)
+	assert.Nil(t, partial.Expressions)
+	assert.Nil(t, partial.IncludeColumns)
+	assert.Equal(t, "deleted_at IS NULL", partial.Predicate)
+	assert.True(t, partial.IsUnique)
+
+	pkey := indexMap["accounts_pkey"]
+	require.NotNil(t, pkey)
+	assert.True(t, pkey.IsPrimary)
+	assert.Equal(t, []string{"id"}, pkey.Columns)
+}
 
-	// Check idx_with_commas - should have 2 columns, not split by commas inside quotes
-	if idx, ok := indexMap["idx_with_commas"]; ok {
-		assert.Len(t, idx.Columns, 2, "idx_with_commas should have exactly 2 columns")
-		assert.Contains(t, idx.Columns, "col,with,commas", "Should contain column with commas")
-		assert.Contains(t, idx.Columns, "normal_col", "Should contain normal_col")
-	} else {
-		t.Error("idx_with_commas not found")
-	}
+func TestIntegration_App_GetDDL(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
 
-	// Check idx_braces
-	if idx, ok := indexMap["idx_braces"]; ok {
-		assert.Len(t, idx.Columns, 2, "idx_braces should have 2 columns")
-		assert.Contains(t, idx.Columns, "col{value}", "Should contain 'col{value}'")
-		assert.Contains(t, idx.Columns, "col}data", "Should contain 'col}data'")
-	} else {
-		t.Error("idx_braces not found")
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE ROLE ddl_reader;
+		CREATE SCHEMA test_ddl;
+		CREATE TYPE test_ddl.status AS ENUM ('active', 'closed');
+		CREATE DOMAIN test_ddl.code AS text CONSTRAINT code_format CHECK (VALUE ~ '^[A-Z]+$');
+		CREATE FUNCTION test_ddl.default_code() RETURNS test_ddl.code LANGUAGE sql AS $$ SELECT 'NEW'::test_ddl.code $$;
+		CREATE TABLE test_ddl.accounts (
+			id serial PRIMARY KEY,
+			code test_ddl.code DEFAULT test_ddl.default_code(),
+			status test_ddl.status NOT NULL DEFAULT 'active',
+			name text,
+			balance numeric(12,2) CONSTRAINT balance_positive CHECK (balance >= 0)
+		);
+		COMMENT ON TABLE test_ddl.accounts IS 'Customer accounts';
+		COMMENT ON COLUMN test_ddl.accounts.name IS 'Display name';
+		CREATE INDEX accounts_lower_name ON test_ddl.accounts (lower(name)) WHERE status = 'active';
+		CREATE TABLE test_ddl.ledger (
+			id bigint GENERATED ALWAYS AS IDENTITY,
+			account_id integer NOT NULL REFERENCES test_ddl.accounts (id),
+			day date NOT NULL,
+			PRIMARY KEY (id, day)
+		) PARTITION BY RANGE (day);
+		CREATE TABLE test_ddl.ledger_2024 PARTITION OF test_ddl.ledger FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+		CREATE FUNCTION test_ddl.active_accounts() RETURNS SETOF test_ddl.accounts LANGUAGE sql
+			BEGIN ATOMIC SELECT * FROM test_ddl.accounts WHERE status = 'active'; END;
+		CREATE FUNCTION test_ddl.touch() RETURNS trigger LANGUAGE plpgsql AS $$ BEGIN RETURN NEW; END $$;
+		CREATE TRIGGER accounts_touch BEFORE UPDATE ON test_ddl.accounts FOR EACH ROW EXECUTE FUNCTION test_ddl.touch();
+		ALTER TABLE test_ddl.accounts DISABLE TRIGGER accounts_touch;
+		CREATE VIEW test_ddl.active AS SELECT id, name FROM test_ddl.accounts WHERE status = 'active';
+		CREATE VIEW test_ddl.active_names AS SELECT name FROM test_ddl.active;
+		CREATE MATERIALIZED VIEW test_ddl.totals AS SELECT count(*) AS total FROM test_ddl.accounts;
+		GRANT SELECT ON test_ddl.accounts TO ddl_reader;
+		GRANT UPDATE (name) ON test_ddl.accounts TO ddl_reader;
+		REVOKE EXECUTE ON FUNCTION test_ddl.touch() FROM PUBLIC;
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_ddl CASCADE; DROP ROLE IF EXISTS ddl_reader")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	script, err := appInstance.GetDDL(ctx, "test_ddl", "")
+	require.NoError(t, err)
+
+	position := map[string]int{}
+	byName := map[string]*app.DDLObject{}
+	for i, object := range script.Objects {
+		position[object.Name] = i
+		byName[object.Name] = object
+	}
+	require.Contains(t, byName, "test_ddl")
+	assert.Equal(t, 0, position["test_ddl"])
+	for _, name := range []string{
+		"test_ddl.status", "test_ddl.code", "test_ddl.default_code()", "test_ddl.accounts_id_seq",
+		"test_ddl.accounts", "test_ddl.ledger", "test_ddl.ledger_2024", "test_ddl.active_accounts()",
+		"test_ddl.active", "test_ddl.active_names", "test_ddl.totals", "test_ddl.accounts_lower_name",
+		"accounts_pkey ON test_ddl.accounts", "ledger_account_id_fkey ON test_ddl.ledger",
+		"accounts_touch ON test_ddl.accounts",
+	} {
+		assert.Contains(t, byName, name)
+	}
+	assert.NotContains(t, byName, "test_ddl.ledger_id_seq", "identity sequences are created by their column")
+	assert.NotContains(t, byName, "ledger_2024_pkey ON test_ddl.ledger_2024", "partition constraints come from the parent")
+
+	assert.Less(t, position["test_ddl.default_code()"], position["test_ddl.accounts"])
+	assert.Less(t, position["test_ddl.accounts"], position["test_ddl.active_accounts()"])
+	assert.Less(t, position["test_ddl.ledger"], position["test_ddl.ledger_2024"])
+	assert.Less(t, position["test_ddl.active"], position["test_ddl.active_names"])
+	assert.Less(t, position["accounts_pkey ON test_ddl.accounts"], position["ledger_account_id_fkey ON test_ddl.ledger"])
+
+	accounts := byName["test_ddl.accounts"].SQL
+	assert.Contains(t, accounts, "CREATE TABLE test_ddl.accounts (")
+	assert.Contains(t, accounts, "id integer DEFAULT nextval('test_ddl.accounts_id_seq'::regclass) NOT NULL")
+	assert.Contains(t, accounts, "CONSTRAINT balance_positive CHECK ((balance >= (0)::numeric))")
+	assert.Contains(t, accounts, "COMMENT ON TABLE test_ddl.accounts IS 'Customer accounts';")
+	assert.Contains(t, accounts, "COMMENT ON COLUMN test_ddl.accounts.name IS 'Display name';")
+	assert.Contains(t, accounts, "GRANT SELECT ON TABLE test_ddl.accounts TO ddl_reader;")
+	assert.Contains(t, accounts, "GRANT UPDATE (name) ON TABLE test_ddl.accounts TO ddl_reader;")
+	assert.Contains(t, byName["test_ddl.ledger"].SQL, "id bigint GENERATED ALWAYS AS IDENTITY NOT NULL")
+	assert.Contains(t, byName["test_ddl.ledger"].SQL, "PARTITION BY RANGE (day)")
+	assert.Contains(t, byName["test_ddl.ledger_2024"].SQL,
+		"CREATE TABLE test_ddl.ledger_2024 PARTITION OF test_ddl.ledger\nFOR VALUES FROM ('2024-01-01') TO ('2025-01-01');")
+	assert.Contains(t, byName["test_ddl.touch()"].SQL, "REVOKE EXECUTE ON FUNCTION test_ddl.touch() FROM PUBLIC;")
+	assert.Contains(t, byName["accounts_touch ON test_ddl.accounts"].SQL,
+		"ALTER TABLE test_ddl.accounts DISABLE TRIGGER accounts_touch;")
+	assert.Contains(t, byName["test_ddl.totals"].SQL, "WITH NO DATA;")
+	assert.Contains(t, byName["test_ddl.code"].SQL, "CONSTRAINT code_format CHECK")
+
+	table, err := appInstance.GetDDL(ctx, "test_ddl", "accounts")
+	require.NoError(t, err)
+	var tableObjects []string
+	for _, object := range table.Objects {
+		tableObjects = append(tableObjects, object.Name)
 	}
+	assert.Equal(t, []string{
+		"test_ddl.accounts_id_seq",
+		"test_ddl.accounts",
+		"accounts_pkey ON test_ddl.accounts",
+		"test_ddl.accounts_lower_name",
+		"accounts_touch ON test_ddl.accounts",
+		"test_ddl.accounts_id_seq",
+	}, tableObjects)
+	assert.Equal(t, app.DDLObjectSequenceOwnership, table.Objects[len(table.Objects)-1].Type)
+
+	// Replaying the script into an empty schema reproduces the same DDL.
+	_, err = db.ExecContext(ctx, "DROP SCHEMA test_ddl CASCADE")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, script.Script())
+	require.NoError(t, err)
 
-	// Cleanup
-	_, _ = db.ExecContext(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", testSchema))
+	replayed, err := appInstance.GetDDL(ctx, "test_ddl", "")
+	require.NoError(t, err)
+	assert.Equal(t, script.Script(), replayed.Script())
+
+	_, err = appInstance.GetDDL(ctx, "test_ddl", "missing")
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+	_, err = appInstance.GetDDL(ctx, "missing_schema", "")
+	assert.ErrorIs(t, err, app.ErrSchemaNotFound)
 }
 
 func TestIntegration_App_ExplainQuery(t *testing.T) {
@@ -831,3 +1830,670 @@ func TestIntegration_ConcurrentQueriesAndReconnect_NoRace_Issue83(t *testing.T)
 	close(stop)
 	wg.Wait()
 }
+
+func TestIntegration_App_GetRelationships(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_rel;
+		CREATE SCHEMA test_rel_geo;
+		CREATE TABLE test_rel_geo.regions (id serial PRIMARY KEY, name text);
+		CREATE TABLE test_rel.customers (
+			id serial PRIMARY KEY,
+			region_id integer REFERENCES test_rel_geo.regions (id)
+		);
+		CREATE TABLE test_rel.orders (
+			id serial PRIMARY KEY,
+			customer_id integer NOT NULL REFERENCES test_rel.customers (id) ON DELETE CASCADE
+		);
+		CREATE TABLE test_rel.order_items (
+			order_id integer REFERENCES test_rel.orders (id),
+			line integer,
+			PRIMARY KEY (order_id, line)
+		);
+		CREATE TABLE test_rel.customer_profiles (
+			customer_id integer PRIMARY KEY REFERENCES test_rel.customers (id)
+		);
+		CREATE TABLE test_rel.notes (body text);
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_rel, test_rel_geo CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	tableNames := func(graph *app.RelationshipGraph) []string {
+		names := make([]string, len(graph.Tables))
+		for i, table := range graph.Tables {
+			names[i] = table.Schema + "." + table.Name
+		}
+		return names
+	}
+
+	graph, err := appInstance.GetRelationships(ctx, "test_rel", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"test_rel.customer_profiles", "test_rel.customers", "test_rel.notes",
+		"test_rel.order_items", "test_rel.orders", "test_rel_geo.regions",
+	}, tableNames(graph))
+	require.Len(t, graph.Relationships, 4)
+
+	byTable := map[string]*app.Relationship{}
+	for _, relationship := range graph.Relationships {
+		byTable[relationship.Table] = relationship
+	}
+	assert.Equal(t, []string{"region_id"}, byTable["customers"].Columns)
+	assert.Equal(t, "test_rel_geo", byTable["customers"].ReferencedSchema)
+	assert.True(t, byTable["customers"].Optional)
+	assert.False(t, byTable["orders"].Optional)
+	assert.Equal(t, "cascade", byTable["orders"].OnDelete)
+	assert.True(t, byTable["customer_profiles"].OneToOne)
+	assert.False(t, byTable["order_items"].OneToOne)
+
+	graph, err = appInstance.GetRelationships(ctx, "test_rel", "orders", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test_rel.customers", "test_rel.order_items", "test_rel.orders"}, tableNames(graph))
+	assert.Len(t, graph.Relationships, 2)
+
+	graph, err = appInstance.GetRelationships(ctx, "test_rel", "orders", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"test_rel.customer_profiles", "test_rel.customers", "test_rel.order_items",
+		"test_rel.orders", "test_rel_geo.regions",
+	}, tableNames(graph))
+
+	diagram, err := graph.Diagram(app.DiagramMermaid)
+	require.NoError(t, err)
+	assert.Contains(t, diagram, "    customers ||..o{ orders : \"orders_customer_id_fkey\"\n")
+	assert.Contains(t, diagram, "    customers ||--o| customer_profiles : \"customer_profiles_customer_id_fkey\"\n")
+
+	_, err = appInstance.GetRelationships(ctx, "test_rel", "missing", 1)
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+}
+
+func TestIntegration_App_FindJoinPath(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_join;
+		CREATE TABLE test_join.regions (id serial PRIMARY KEY, name text);
+		CREATE TABLE test_join.customers (
+			id serial PRIMARY KEY,
+			region_id integer REFERENCES test_join.regions (id)
+		);
+		CREATE TABLE test_join.orders (
+			id serial PRIMARY KEY,
+			customer_id integer NOT NULL REFERENCES test_join.customers (id)
+		);
+		CREATE TABLE test_join.shipments (id serial PRIMARY KEY, order_id integer, carrier_id integer);
+		INSERT INTO test_join.regions (name) VALUES ('north');
+		INSERT INTO test_join.customers (region_id) VALUES (1);
+		INSERT INTO test_join.orders (customer_id) VALUES (1);
+		INSERT INTO test_join.shipments (order_id) VALUES (1);
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_join CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	path, err := appInstance.FindJoinPath(ctx, &app.JoinPathOptions{
+		FromSchema: "test_join", FromTable: "orders", ToSchema: "test_join", ToTable: "regions",
+	})
+	require.NoError(t, err)
+	require.Len(t, path.Joins, 2)
+	assert.Equal(t, "customers", path.Joins[0].Table)
+	assert.Equal(t, []string{"id"}, path.Joins[0].Columns)
+	assert.Equal(t, []string{"customer_id"}, path.Joins[0].FromColumns)
+	assert.Equal(t, "orders_customer_id_fkey", path.Joins[0].Constraint)
+	assert.Equal(t, "regions", path.Joins[1].Table)
+
+	_, err = appInstance.FindJoinPath(ctx, &app.JoinPathOptions{
+		FromSchema: "test_join", FromTable: "shipments", ToSchema: "test_join", ToTable: "regions",
+	})
+	assert.ErrorIs(t, err, app.ErrNoJoinPath)
+
+	path, err = appInstance.FindJoinPath(ctx, &app.JoinPathOptions{
+		FromSchema: "test_join", FromTable: "shipments", ToSchema: "test_join", ToTable: "regions",
+		IncludeInferred: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, path.Joins, 3)
+	assert.True(t, path.Joins[0].Inferred)
+	assert.Equal(t, []string{"order_id"}, path.Joins[0].FromColumns)
+
+	// The generated clause is valid SQL that reaches the target.
+	var name string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT regions.name "+path.SQL).Scan(&name))
+	assert.Equal(t, "north", name)
+}
+
+func TestIntegration_App_SearchSchema(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_search;
+		CREATE TABLE test_search.zebra (id serial PRIMARY KEY, stripes integer);
+		CREATE TABLE test_search.zebra_sightings (
+			id serial PRIMARY KEY,
+			zebra_id integer REFERENCES test_search.zebra (id),
+			seen_at timestamptz
+		);
+		CREATE TABLE test_search.safari_log (entry text);
+		COMMENT ON TABLE test_search.safari_log IS 'Notes about every zebra herd';
+		CREATE VIEW test_search.recent_zebras AS SELECT * FROM test_search.zebra_sightings;
+		CREATE FUNCTION test_search.count_zebras(since timestamptz) RETURNS bigint LANGUAGE sql
+			AS $$ SELECT count(*) FROM test_search.zebra_sightings WHERE seen_at >= since $$;
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_search CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	results, err := appInstance.SearchSchema(ctx, app.SearchOptions{Pattern: "zebra"})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+
+	// The exact name ranks first, comment matches last.
+	assert.Equal(t, "zebra", results[0].Name)
+	assert.Equal(t, app.TableTypeTable, results[0].Type)
+	assert.Equal(t, 1.0, results[0].Score)
+	last := results[len(results)-1]
+	assert.Equal(t, "safari_log", last.Name)
+	assert.Equal(t, "description", last.MatchedOn)
+
+	found := map[string]*app.SearchResult{}
+	for _, result := range results {
+		found[result.Type+":"+result.Name] = result
+	}
+	require.Contains(t, found, "column:zebra_id")
+	assert.Equal(t, "zebra_sightings", found["column:zebra_id"].Table)
+	assert.Equal(t, "integer", found["column:zebra_id"].DataType)
+	assert.Contains(t, found, "view:recent_zebras")
+	require.Contains(t, found, "function:count_zebras")
+	assert.Equal(t, "since timestamp with time zone", found["function:count_zebras"].Arguments)
+
+	results, err = appInstance.SearchSchema(ctx, app.SearchOptions{
+		Pattern: "^zebra_", Mode: app.SearchModeRegex, Types: []string{app.SearchObjectColumn},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, "zebra_id", result.Name)
+	}
+	assert.ElementsMatch(t, []string{"recent_zebras", "zebra_sightings"}, []string{results[0].Table, results[1].Table})
+
+	_, err = db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS pg_trgm")
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP EXTENSION IF EXISTS pg_trgm")
+	}()
+
+	// Trigram similarity tolerates typos.
+	results, err = appInstance.SearchSchema(ctx, app.SearchOptions{
+		Pattern: "zebra_sitings", Mode: app.SearchModeSimilarity, Types: []string{app.TableTypeTable},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "zebra_sightings", results[0].Name)
+}
+
+func TestIntegration_App_FindValue(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_find;
+		CREATE TABLE test_find.customers (id integer PRIMARY KEY, email text, joined date);
+		CREATE TABLE test_find.orders (id bigint PRIMARY KEY, customer_id integer, ref varchar(20));
+		CREATE TABLE test_find.flags (id smallint, token uuid);
+		CREATE TABLE test_find.codes (code char(3));
+		CREATE MATERIALIZED VIEW test_find.totals AS SELECT count(*) AS n FROM test_find.orders WITH NO DATA;
+		INSERT INTO test_find.customers VALUES (48213, 'a@example.com', '2024-01-01'), (7, 'b@example.com', NULL);
+		INSERT INTO test_find.orders VALUES (1, 48213, 'X1'), (2, 48213, '48213'), (3, 7, NULL);
+		INSERT INTO test_find.flags VALUES (1, gen_random_uuid());
+		INSERT INTO test_find.codes VALUES ('ABC'), ('A');
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_find CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	search, err := appInstance.FindValue(ctx, app.FindValueOptions{Value: "48213", Schema: "test_find"})
+	require.NoError(t, err)
+
+	hits := map[string]int64{}
+	for _, match := range search.Matches {
+		hits[match.Table+"."+match.Column] = match.Rows
+	}
+	assert.Equal(t, map[string]int64{
+		"customers.id":       1,
+		"orders.customer_id": 2,
+		"orders.ref":         1,
+	}, hits)
+	// flags.id is a smallint too small for the value, and totals.n has not
+	// been populated.
+	assert.Equal(t, 3, search.TablesSearched)
+	require.Len(t, search.Skipped, 1)
+	assert.Equal(t, "totals", search.Skipped[0].Table)
+	assert.Contains(t, search.Skipped[0].Reason, "has not been populated")
+	assert.Zero(t, search.TablesNotSearched)
+
+	search, err = appInstance.FindValue(ctx, app.FindValueOptions{Value: "7", Schema: "test_find", MaxTables: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, search.TablesSearched)
+	assert.Equal(t, 3, search.TablesNotSearched)
+
+	// A character(n) column is compared with the whole value, not its first
+	// character.
+	search, err = appInstance.FindValue(ctx, app.FindValueOptions{Value: "ABC", Schema: "test_find", Table: "codes"})
+	require.NoError(t, err)
+	require.Len(t, search.Matches, 1)
+	assert.Equal(t, "code", search.Matches[0].Column)
+	assert.Equal(t, int64(1), search.Matches[0].Rows)
+
+	_, err = appInstance.FindValue(ctx, app.FindValueOptions{Value: "7", Schema: "test_find", Table: "missing"})
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+}
+
+func TestIntegration_App_DiffSchema(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_diff_a;
+		CREATE TABLE test_diff_a.customers (id integer PRIMARY KEY, email text, name varchar(50));
+		CREATE INDEX customers_email_idx ON test_diff_a.customers (email);
+		CREATE TABLE test_diff_a.legacy (id integer);
+		CREATE VIEW test_diff_a.active_customers AS SELECT id FROM test_diff_a.customers;
+		CREATE VIEW test_diff_a.customer_emails AS SELECT email FROM test_diff_a.customers;
+		CREATE FUNCTION test_diff_a.old_total() RETURNS integer LANGUAGE sql AS 'SELECT 1';
+		CREATE FUNCTION test_diff_a.shared(x integer) RETURNS integer LANGUAGE sql AS 'SELECT x + 1';
+
+		CREATE SCHEMA test_diff_b;
+		CREATE TABLE test_diff_b.customers (id bigint PRIMARY KEY, email text NOT NULL UNIQUE, created_at timestamptz DEFAULT now());
+		CREATE INDEX customers_email_idx ON test_diff_b.customers (lower(email));
+		CREATE TABLE test_diff_b.orders (
+			id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			customer_id bigint REFERENCES test_diff_b.customers
+		);
+		CREATE TABLE test_diff_b.notes (id serial PRIMARY KEY, body text);
+		CREATE VIEW test_diff_b.active_customers AS SELECT id, email FROM test_diff_b.customers;
+		CREATE VIEW test_diff_b.customer_emails AS SELECT email FROM test_diff_b.customers;
+		CREATE FUNCTION test_diff_b.shared(x integer) RETURNS integer LANGUAGE sql AS 'SELECT x + 1';
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_diff_a, test_diff_b CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	diff, err := appInstance.DiffSchema(ctx, app.DiffSchemaOptions{
+		Schema: "test_diff_a", TargetSchema: "test_diff_b", Migration: true,
+	})
+	require.NoError(t, err)
+
+	changes := make([]string, len(diff.Changes))
+	for i, change := range diff.Changes {
+		changes[i] = change.Change + " " + change.Type + " " + change.Table + "." + change.Name
+	}
+	// The shared function and the customer_emails view are the same in both
+	// schemas once the schema name is left out.
+	assert.Equal(t, []string{
+		"added column customers.created_at",
+		"changed column customers.email",
+		"changed column customers.id",
+		"removed column customers.name",
+		"added constraint customers.customers_email_key",
+		"changed index customers.customers_email_idx",
+		"removed table .legacy",
+		"added table .notes",
+		"added table .orders",
+		"changed view .active_customers",
+		"removed function .old_total()",
+	}, changes)
+	assert.Contains(t, diff.Migration, `ALTER TABLE "orders" ADD CONSTRAINT "orders_customer_id_fkey" FOREIGN KEY (customer_id) REFERENCES customers(id);`)
+	assert.Contains(t, diff.Migration, "CREATE TABLE \"notes\" (\n\t\"id\" serial NOT NULL,")
+
+	// The same comparison through a second connection to the target.
+	diff, err = appInstance.DiffSchema(ctx, app.DiffSchemaOptions{
+		Schema: "test_diff_a", TargetSchema: "test_diff_b", TargetConnection: connectionString, Migration: true,
+	})
+	require.NoError(t, err)
+	assert.Len(t, diff.Changes, len(changes))
+
+	// Running the migration on the source leaves nothing to report.
+	_, err = db.ExecContext(ctx, diff.Migration)
+	require.NoError(t, err)
+
+	diff, err = appInstance.DiffSchema(ctx, app.DiffSchemaOptions{Schema: "test_diff_a", TargetSchema: "test_diff_b"})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Changes)
+
+	_, err = appInstance.DiffSchema(ctx, app.DiffSchemaOptions{Schema: "test_diff_a", TargetSchema: "missing"})
+	assert.ErrorIs(t, err, app.ErrSchemaNotFound)
+}
+
+func TestIntegration_App_SnapshotSchema(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_snap;
+		CREATE TABLE test_snap.accounts (
+			id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			email text NOT NULL,
+			balance numeric(12, 2) DEFAULT 0 CHECK (balance >= 0)
+		);
+		CREATE TABLE test_snap.events (at date NOT NULL, account_id integer REFERENCES test_snap.accounts)
+			PARTITION BY RANGE (at);
+		CREATE TABLE test_snap.events_2026 PARTITION OF test_snap.events FOR VALUES FROM ('2026-01-01') TO ('2027-01-01');
+		CREATE INDEX events_at_idx ON test_snap.events (at);
+		CREATE VIEW test_snap.rich AS SELECT id FROM test_snap.accounts WHERE balance > 1000;
+		CREATE FUNCTION test_snap.touch(a test_snap.accounts) RETURNS integer LANGUAGE sql AS 'SELECT a.id';
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_snap CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	snapshot, err := appInstance.SnapshotSchema(ctx, "test_snap")
+	require.NoError(t, err)
+	assert.Equal(t, app.SnapshotVersion, snapshot.Version)
+	assert.NotEmpty(t, snapshot.Database)
+
+	// Partitions are left out, and definitions do not name the schema.
+	require.Len(t, snapshot.Tables, 2)
+	accounts, events := snapshot.Tables[0], snapshot.Tables[1]
+	assert.Equal(t, "accounts", accounts.Name)
+	assert.Equal(t, &app.SnapshotColumn{Name: "id", DataType: "integer", Identity: "by default"}, accounts.Columns[0])
+	assert.Equal(t, &app.SnapshotColumn{Name: "balance", DataType: "numeric(12,2)", Nullable: true, Default: "0"}, accounts.Columns[2])
+	assert.Equal(t, "RANGE (at)", events.PartitionBy)
+	assert.Equal(t, []*app.SnapshotIndex{
+		{Name: "events_at_idx", Definition: "CREATE INDEX events_at_idx ON events USING btree (at)"},
+	}, events.Indexes)
+	require.Len(t, events.Constraints, 1)
+	assert.Equal(t, "FOREIGN KEY (account_id) REFERENCES accounts(id)", events.Constraints[0].Definition)
+	require.Len(t, snapshot.Views, 1)
+	assert.NotContains(t, snapshot.Views[0].Definition, "test_snap")
+	require.Len(t, snapshot.Functions, 1)
+	assert.Equal(t, "touch(a accounts)", snapshot.Functions[0].Name)
+	assert.Contains(t, snapshot.Functions[0].Definition, "CREATE OR REPLACE FUNCTION touch(a accounts)")
+
+	// The stored snapshot is compared with the schema after it changed.
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	stored, err := app.ParseSchemaSnapshot(data)
+	require.NoError(t, err)
+
+	diff, err := appInstance.DiffSchema(ctx, app.DiffSchemaOptions{Snapshot: stored})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Changes)
+
+	_, err = db.ExecContext(ctx, `
+		ALTER TABLE test_snap.accounts ADD COLUMN closed_at timestamptz;
+		DROP VIEW test_snap.rich;
+	`)
+	require.NoError(t, err)
+
+	diff, err = appInstance.DiffSchema(ctx, app.DiffSchemaOptions{Snapshot: stored})
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 2)
+	assert.Equal(t, "closed_at", diff.Changes[0].Name)
+	assert.Equal(t, app.SchemaChangeAdded, diff.Changes[0].Change)
+	assert.Equal(t, "rich", diff.Changes[1].Name)
+	assert.Equal(t, app.SchemaChangeRemoved, diff.Changes[1].Change)
+
+	_, err = appInstance.SnapshotSchema(ctx, "missing")
+	assert.ErrorIs(t, err, app.ErrSchemaNotFound)
+}
+
+func TestIntegration_App_GenerateTypes(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_types;
+		CREATE TYPE test_types.order_status AS ENUM ('new', 'paid', 'shipped');
+		CREATE DOMAIN test_types.sku AS varchar(20) NOT NULL;
+		CREATE TABLE test_types.orders (
+			id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			status test_types.order_status NOT NULL DEFAULT 'new',
+			sku test_types.sku,
+			total numeric(10, 2),
+			scores integer[][],
+			placed_at timestamptz NOT NULL,
+			metadata jsonb
+		);
+		COMMENT ON COLUMN test_types.orders.total IS 'Order total';
+		CREATE VIEW test_types.open_orders AS SELECT id, status FROM test_types.orders WHERE status <> 'shipped';
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_types CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	types, err := appInstance.GenerateTypes(ctx, app.GenerateTypesOptions{Schema: "test_types", Language: app.TypeLanguageGo})
+	require.NoError(t, err)
+	require.Len(t, types.Enums, 1)
+	assert.Equal(t, []string{"new", "paid", "shipped"}, types.Enums[0].Values)
+	require.Len(t, types.Types, 2)
+	assert.Equal(t, "open_orders", types.Types[0].Table.Name)
+	assert.Equal(t, app.TableTypeView, types.Types[0].Table.Type)
+
+	fields := make(map[string]*app.TypeField)
+	for _, field := range types.Types[1].Fields {
+		fields[field.Column.Name] = field
+	}
+	assert.Equal(t, "int64", fields["id"].Type)
+	assert.True(t, fields["id"].Column.HasDefault)
+	assert.Equal(t, "OrderStatus", fields["status"].Type)
+	assert.Equal(t, "string", fields["sku"].Type, "the NOT NULL domain makes the column required")
+	assert.Equal(t, 20, fields["sku"].Column.Length)
+	assert.Equal(t, "*string", fields["total"].Type)
+	assert.Equal(t, 10, fields["total"].Column.Precision)
+	assert.Equal(t, 2, fields["total"].Column.Scale)
+	assert.Equal(t, "Order total", fields["total"].Column.Description)
+	assert.Equal(t, "[][]int32", fields["scores"].Type)
+	assert.Equal(t, "time.Time", fields["placed_at"].Type)
+	assert.Equal(t, "json.RawMessage", fields["metadata"].Type)
+	assert.Contains(t, types.Code, "type Orders struct {")
+
+	types, err = appInstance.GenerateTypes(ctx, app.GenerateTypesOptions{
+		Schema: "test_types", Table: "orders", Language: app.TypeLanguagePydantic,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, types.Code, "total: Annotated[Decimal, Field(max_digits=10, decimal_places=2)] | None = None")
+	assert.Contains(t, types.Code, "sku: Annotated[str, Field(max_length=20)]\n")
+
+	_, err = appInstance.GenerateTypes(ctx, app.GenerateTypesOptions{Schema: "test_types", Table: "missing", Language: app.TypeLanguageGo})
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+
+	_, err = appInstance.GenerateTypes(ctx, app.GenerateTypesOptions{Schema: "missing", Language: app.TypeLanguageGo})
+	assert.ErrorIs(t, err, app.ErrSchemaNotFound)
+}
+
+func TestIntegration_App_GetDependencies(t *testing.T) {
+	_, connectionString, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	db, err := sql.Open("pgx", connectionString)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE SCHEMA test_deps;
+		CREATE TABLE test_deps.customers (id integer PRIMARY KEY, email text NOT NULL, region text);
+		CREATE TABLE test_deps.orders (
+			id integer PRIMARY KEY,
+			customer_id integer REFERENCES test_deps.customers,
+			total numeric
+		);
+		CREATE INDEX customers_email_idx ON test_deps.customers (email);
+		CREATE VIEW test_deps.v_customers AS SELECT id, email FROM test_deps.customers;
+		CREATE VIEW test_deps.v_top AS SELECT email FROM test_deps.v_customers WHERE id < 10;
+		CREATE MATERIALIZED VIEW test_deps.m_regions AS SELECT region, count(*) FROM test_deps.customers GROUP BY region;
+		ALTER TABLE test_deps.customers ENABLE ROW LEVEL SECURITY;
+		CREATE POLICY own_region ON test_deps.customers USING (region = current_user);
+		CREATE FUNCTION test_deps.normalize() RETURNS trigger LANGUAGE plpgsql AS
+			'BEGIN NEW.email := lower(NEW.email); RETURN NEW; END';
+		CREATE TRIGGER normalize_email BEFORE INSERT ON test_deps.customers
+			FOR EACH ROW EXECUTE FUNCTION test_deps.normalize();
+		CREATE FUNCTION test_deps.customer_count() RETURNS bigint LANGUAGE plpgsql AS
+			'BEGIN RETURN (SELECT count(*) FROM test_deps.customers); END';
+	`)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DROP SCHEMA IF EXISTS test_deps CASCADE")
+	}()
+
+	appInstance, err := app.NewDefault()
+	require.NoError(t, err)
+	defer appInstance.Disconnect()
+
+	require.NoError(t, appInstance.Connect(ctx, connectionString))
+
+	byName := func(report *app.DependencyReport) map[string]*app.Dependency {
+		dependencies := make(map[string]*app.Dependency, len(report.Dependencies))
+		for _, dependency := range report.Dependencies {
+			dependencies[dependency.Name] = dependency
+		}
+		return dependencies
+	}
+
+	report, err := appInstance.GetDependencies(ctx, "test_deps", "customers", "")
+	require.NoError(t, err)
+	dependencies := byName(report)
+
+	require.Contains(t, dependencies, "orders_customer_id_fkey")
+	assert.Equal(t, app.DDLObjectForeignKey, dependencies["orders_customer_id_fkey"].Type)
+	assert.Equal(t, "orders", dependencies["orders_customer_id_fkey"].Table)
+	assert.Equal(t, app.DependencyNormal, dependencies["orders_customer_id_fkey"].Dependency)
+	require.Contains(t, dependencies, "customers_email_idx")
+	assert.Equal(t, app.DDLObjectIndex, dependencies["customers_email_idx"].Type)
+	assert.Equal(t, app.DependencyAutomatic, dependencies["customers_email_idx"].Dependency)
+	require.Contains(t, dependencies, "own_region")
+	assert.Equal(t, app.DependencyObjectPolicy, dependencies["own_region"].Type)
+	require.Contains(t, dependencies, "normalize_email")
+	assert.Equal(t, app.DDLObjectTrigger, dependencies["normalize_email"].Type)
+	require.Contains(t, dependencies, "m_regions")
+	assert.Equal(t, app.DDLObjectMaterializedView, dependencies["m_regions"].Type)
+
+	// Views are followed to the views built on them.
+	require.Contains(t, dependencies, "v_customers")
+	assert.Equal(t, app.DDLObjectView, dependencies["v_customers"].Type)
+	assert.Equal(t, 1, dependencies["v_customers"].Depth)
+	require.Contains(t, dependencies, "v_top")
+	assert.Equal(t, 2, dependencies["v_top"].Depth)
+	assert.Contains(t, dependencies["v_top"].Via, "test_deps.v_customers")
+
+	// PL/pgSQL bodies are only found through their source.
+	require.Contains(t, dependencies, "customer_count()")
+	assert.Equal(t, app.DDLObjectFunction, dependencies["customer_count()"].Type)
+	assert.Equal(t, app.DependencySource, dependencies["customer_count()"].Dependency)
+	assert.NotContains(t, dependencies, "normalize()")
+
+	// A column only has the dependents that use it.
+	report, err = appInstance.GetDependencies(ctx, "test_deps", "customers", "email")
+	require.NoError(t, err)
+	assert.Equal(t, "email", report.Column)
+	dependencies = byName(report)
+	assert.Contains(t, dependencies, "customers_email_idx")
+	assert.Contains(t, dependencies, "v_customers")
+	assert.Contains(t, dependencies, "v_top")
+	assert.NotContains(t, dependencies, "orders_customer_id_fkey")
+	assert.NotContains(t, dependencies, "m_regions")
+	assert.NotContains(t, dependencies, "own_region")
+
+	_, err = appInstance.GetDependencies(ctx, "test_deps", "customers", "missing")
+	assert.ErrorIs(t, err, app.ErrColumnNotFound)
+
+	_, err = appInstance.GetDependencies(ctx, "test_deps", "missing", "")
+	assert.ErrorIs(t, err, app.ErrTableNotFound)
+}
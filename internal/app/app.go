@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -56,9 +57,17 @@ func applyLimit(query string, limit int) string {
 }
 
 // ListTablesOptions represents options for listing tables.
+// Types, when set, keeps only relations of those TableType* kinds. Pattern
+// is an ILIKE pattern ("%" matches any run of characters, "_" a single
+// one, and a backslash escapes them) matched against the relation name. Search, when
+// set, keeps only tables whose name or comment contains it
+// (case-insensitive).
 type ListTablesOptions struct {
-	Schema      string `json:"schema,omitempty"`
-	IncludeSize bool   `json:"include_size,omitempty"`
+	Schema      string   `json:"schema,omitempty"`
+	IncludeSize bool     `json:"include_size,omitempty"`
+	Types       []string `json:"types,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Search      string   `json:"search,omitempty"`
 }
 
 // ExecuteQueryOptions represents options for executing queries.
@@ -78,8 +87,12 @@ type ExecuteQueryOptions struct {
 // Connect call. ensureConnection uses it for reconnects so that an
 // explicit connect_database session is not silently overridden by
 // POSTGRES_URL / DATABASE_URL on the next ping failure (issue #87).
+//
+// newClient creates the short-lived client DiffSchema connects to a second
+// database with.
 type App struct {
 	client         PostgreSQLClient
+	newClient      func() PostgreSQLClient
 	logger         *slog.Logger
 	reconnectGroup singleflight.Group
 
@@ -92,8 +105,9 @@ type App struct {
 // making it easy to inject mocks or alternative implementations for testing.
 func New(client PostgreSQLClient) *App {
 	return &App{
-		client: client,
-		logger: logger.NewLogger("info"),
+		client:    client,
+		newClient: newPostgreSQLClient,
+		logger:    logger.NewLogger("info"),
 	}
 }
 
@@ -103,8 +117,9 @@ func New(client PostgreSQLClient) *App {
 func NewDefault() (*App, error) {
 	client := NewPostgreSQLClient()
 	app := &App{
-		client: client,
-		logger: logger.NewLogger("info"),
+		client:    client,
+		newClient: newPostgreSQLClient,
+		logger:    logger.NewLogger("info"),
 	}
 
 	// Note: Connection is now explicit via Connect() or connect_database tool
@@ -204,9 +219,19 @@ func (a *App) ListTables(ctx context.Context, opts *ListTablesOptions) ([]*Table
 		return nil, fmt.Errorf("failed to list tables: %w", err)
 	}
 
-	schema := DefaultSchema
-	if opts != nil && opts.Schema != "" {
-		schema = opts.Schema
+	listOpts := ListTablesOptions{}
+	if opts != nil {
+		listOpts = *opts
+	}
+	if listOpts.Schema == "" {
+		listOpts.Schema = DefaultSchema
+	}
+	schema := listOpts.Schema
+
+	for _, t := range listOpts.Types {
+		if !slices.Contains(tableTypes, t) {
+			return nil, fmt.Errorf("%w: %q (supported: %s)", ErrInvalidTableType, t, strings.Join(tableTypes, ", "))
+		}
 	}
 
 	a.logger.Debug("Listing tables", "schema", schema)
@@ -214,15 +239,17 @@ func (a *App) ListTables(ctx context.Context, opts *ListTablesOptions) ([]*Table
 	var tables []*TableInfo
 	var err error
 
-	// Use optimized query when stats are requested to avoid N+1 query pattern
-	if opts != nil && opts.IncludeSize {
-		tables, err = a.client.ListTablesWithStats(ctx, schema)
+	// Use optimized query when stats are requested to avoid N+1 query pattern.
+	// Both queries apply the filters, so rows are only counted for the tables
+	// returned.
+	if listOpts.IncludeSize {
+		tables, err = a.client.ListTablesWithStats(ctx, &listOpts)
 		if err != nil {
 			a.logger.Error("Failed to list tables with stats", "error", err, "schema", schema)
 			return nil, fmt.Errorf("failed to list tables with stats: %w", err)
 		}
 	} else {
-		tables, err = a.client.ListTables(ctx, schema)
+		tables, err = a.client.ListTables(ctx, &listOpts)
 		if err != nil {
 			a.logger.Error("Failed to list tables", "error", err, "schema", schema)
 			return nil, fmt.Errorf("failed to list tables: %w", err)
@@ -233,8 +260,23 @@ func (a *App) ListTables(ctx context.Context, opts *ListTablesOptions) ([]*Table
 	return tables, nil
 }
 
-// DescribeTable returns detailed information about a table's structure.
-func (a *App) DescribeTable(ctx context.Context, schema, table string) ([]*ColumnInfo, error) {
+// tableTypes lists the values accepted in ListTablesOptions.Types.
+var tableTypes = []string{
+	TableTypeTable,
+	TableTypePartitionedTable,
+	TableTypeView,
+	TableTypeMaterializedView,
+	TableTypeForeignTable,
+}
+
+// TableTypes returns the relation types accepted in ListTablesOptions.Types.
+func TableTypes() []string {
+	return slices.Clone(tableTypes)
+}
+
+// DescribeTable returns detailed information about a table's structure: its
+// columns and its constraints, including foreign key references.
+func (a *App) DescribeTable(ctx context.Context, schema, table string) (*TableDescription, error) {
 	if err := a.ensureConnection(ctx); err != nil {
 		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
@@ -255,8 +297,18 @@ func (a *App) DescribeTable(ctx context.Context, schema, table string) ([]*Colum
 		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
 
-	a.logger.Debug("Successfully described table", "column_count", len(columns), "schema", schema, "table", table)
-	return columns, nil
+	constraints, err := a.client.ListConstraints(ctx, schema, table)
+	if err != nil {
+		a.logger.Error("Failed to list constraints", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to describe table: %w", err)
+	}
+	if constraints == nil {
+		constraints = []*ConstraintInfo{}
+	}
+
+	a.logger.Debug("Successfully described table", "column_count", len(columns),
+		"constraint_count", len(constraints), "schema", schema, "table", table)
+	return &TableDescription{Schema: schema, Name: table, Columns: columns, Constraints: constraints}, nil
 }
 
 // GetTableStats returns statistics for a specific table.
@@ -53,16 +53,16 @@ func (m *MockPostgreSQLClient) ListSchemas(ctx context.Context) ([]*SchemaInfo,
 	return args.Get(0).([]*SchemaInfo), args.Error(1)
 }
 
-func (m *MockPostgreSQLClient) ListTables(ctx context.Context, schema string) ([]*TableInfo, error) {
-	args := m.Called(ctx, schema)
+func (m *MockPostgreSQLClient) ListTables(ctx context.Context, opts *ListTablesOptions) ([]*TableInfo, error) {
+	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*TableInfo), args.Error(1)
 }
 
-func (m *MockPostgreSQLClient) ListTablesWithStats(ctx context.Context, schema string) ([]*TableInfo, error) {
-	args := m.Called(ctx, schema)
+func (m *MockPostgreSQLClient) ListTablesWithStats(ctx context.Context, opts *ListTablesOptions) ([]*TableInfo, error) {
+	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -77,6 +77,14 @@ func (m *MockPostgreSQLClient) DescribeTable(ctx context.Context, schema, table
 	return args.Get(0).([]*ColumnInfo), args.Error(1)
 }
 
+func (m *MockPostgreSQLClient) ListConstraints(ctx context.Context, schema, table string) ([]*ConstraintInfo, error) {
+	args := m.Called(ctx, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ConstraintInfo), args.Error(1)
+}
+
 func (m *MockPostgreSQLClient) GetTableStats(ctx context.Context, schema, table string) (*TableInfo, error) {
 	args := m.Called(ctx, schema, table)
 	if args.Get(0) == nil {
@@ -93,6 +101,170 @@ func (m *MockPostgreSQLClient) ListIndexes(ctx context.Context, schema, table st
 	return args.Get(0).([]*IndexInfo), args.Error(1)
 }
 
+func (m *MockPostgreSQLClient) DescribePartitions(ctx context.Context, schema, table string) (*PartitionTree, error) {
+	args := m.Called(ctx, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PartitionTree), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) GetViewDefinition(ctx context.Context, schema, view string) (*ViewDefinition, error) {
+	args := m.Called(ctx, schema, view)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ViewDefinition), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListTriggers(ctx context.Context, schema, table string) ([]*TriggerInfo, error) {
+	args := m.Called(ctx, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*TriggerInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListFunctions(ctx context.Context, schema string) ([]*FunctionInfo, error) {
+	args := m.Called(ctx, schema)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*FunctionInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) GetFunctionDefinition(
+	ctx context.Context, schema, name, argTypes string,
+) ([]*FunctionDefinition, error) {
+	args := m.Called(ctx, schema, name, argTypes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*FunctionDefinition), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListTypes(ctx context.Context, schema string) ([]*TypeInfo, error) {
+	args := m.Called(ctx, schema)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*TypeInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListSequences(ctx context.Context, schema string) ([]*SequenceInfo, error) {
+	args := m.Called(ctx, schema)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*SequenceInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListExtensions(ctx context.Context) ([]*ExtensionInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ExtensionInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) GetSettings(ctx context.Context, opts SettingsOptions) ([]*SettingInfo, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*SettingInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListRoles(ctx context.Context) ([]*RoleInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*RoleInfo), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) GetTablePrivileges(
+	ctx context.Context, role, schema, table string,
+) ([]*TablePrivileges, error) {
+	args := m.Called(ctx, role, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*TablePrivileges), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListPolicies(ctx context.Context, schema, table string) ([]*TableSecurity, error) {
+	args := m.Called(ctx, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*TableSecurity), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) GetDDL(ctx context.Context, schema, table string) (*DDLScript, error) {
+	args := m.Called(ctx, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DDLScript), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListRelationships(ctx context.Context) (*RelationshipGraph, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*RelationshipGraph), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListIDColumns(ctx context.Context) ([]*IDColumn, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*IDColumn), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) SearchSchema(ctx context.Context, opts SearchOptions) ([]*SearchResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*SearchResult), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) FindValue(ctx context.Context, opts FindValueOptions) (*ValueSearch, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ValueSearch), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) SnapshotSchema(ctx context.Context, schema string) (*SchemaSnapshot, error) {
+	args := m.Called(ctx, schema)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SchemaSnapshot), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) ListCodegenTables(ctx context.Context, schema, table string) ([]*CodegenTable, error) {
+	args := m.Called(ctx, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*CodegenTable), args.Error(1)
+}
+
+func (m *MockPostgreSQLClient) GetDependencies(ctx context.Context, schema, table, column string) (*DependencyReport, error) {
+	args := m.Called(ctx, schema, table, column)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DependencyReport), args.Error(1)
+}
+
 func (m *MockPostgreSQLClient) ExecuteQuery(ctx context.Context, query string, queryArgs ...any) (*QueryResult, error) {
 	mockArgs := m.Called(ctx, query, queryArgs)
 	if mockArgs.Get(0) == nil {
@@ -277,7 +449,7 @@ func TestApp_ListTables(t *testing.T) {
 	}
 
 	mockClient.On("Ping", mock.Anything).Return(nil)
-	mockClient.On("ListTables", mock.Anything, "public").Return(expectedTables, nil)
+	mockClient.On("ListTables", mock.Anything, opts).Return(expectedTables, nil)
 
 	tables, err := app.ListTables(context.Background(), opts)
 	assert.NoError(t, err)
@@ -296,7 +468,7 @@ func TestApp_ListTablesWithDefaultSchema(t *testing.T) {
 	opts := &ListTablesOptions{}
 
 	mockClient.On("Ping", mock.Anything).Return(nil)
-	mockClient.On("ListTables", mock.Anything, DefaultSchema).Return(expectedTables, nil)
+	mockClient.On("ListTables", mock.Anything, &ListTablesOptions{Schema: DefaultSchema}).Return(expectedTables, nil)
 
 	tables, err := app.ListTables(context.Background(), opts)
 	assert.NoError(t, err)
@@ -313,7 +485,7 @@ func TestApp_ListTablesWithNilOptions(t *testing.T) {
 	}
 
 	mockClient.On("Ping", mock.Anything).Return(nil)
-	mockClient.On("ListTables", mock.Anything, DefaultSchema).Return(expectedTables, nil)
+	mockClient.On("ListTables", mock.Anything, &ListTablesOptions{Schema: DefaultSchema}).Return(expectedTables, nil)
 
 	tables, err := app.ListTables(context.Background(), nil)
 	assert.NoError(t, err)
@@ -321,6 +493,44 @@ func TestApp_ListTablesWithNilOptions(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestApp_ListTablesPassesFiltersToQuery(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	opts := &ListTablesOptions{
+		Types:   []string{TableTypeTable, TableTypeMaterializedView},
+		Pattern: "order%",
+		Search:  "invoice",
+	}
+	orders2024 := &TableInfo{Schema: "public", Name: "orders_2024", Type: TableTypeTable, PartitionOf: "public.orders"}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListTables", mock.Anything, &ListTablesOptions{
+		Schema:  DefaultSchema,
+		Types:   []string{TableTypeTable, TableTypeMaterializedView},
+		Pattern: "order%",
+		Search:  "invoice",
+	}).Return([]*TableInfo{orders2024}, nil)
+
+	tables, err := app.ListTables(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []*TableInfo{orders2024}, tables)
+	assert.Empty(t, opts.Schema, "caller options must not be modified")
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListTablesRejectsUnknownType(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+
+	tables, err := app.ListTables(context.Background(), &ListTablesOptions{Types: []string{"sequence"}})
+	assert.ErrorIs(t, err, ErrInvalidTableType)
+	assert.Nil(t, tables)
+	mockClient.AssertNotCalled(t, "ListTables", mock.Anything, mock.Anything)
+}
+
 func TestApp_ListTablesWithSize(t *testing.T) {
 	mockClient := &MockPostgreSQLClient{}
 	app := New(mockClient)
@@ -342,7 +552,7 @@ func TestApp_ListTablesWithSize(t *testing.T) {
 	}
 
 	mockClient.On("Ping", mock.Anything).Return(nil)
-	mockClient.On("ListTablesWithStats", mock.Anything, "public").Return(tablesWithStats, nil)
+	mockClient.On("ListTablesWithStats", mock.Anything, opts).Return(tablesWithStats, nil)
 
 	tables, err := app.ListTables(context.Background(), opts)
 	assert.NoError(t, err)
@@ -352,6 +562,32 @@ func TestApp_ListTablesWithSize(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestApp_ListTablesWithSizePassesFiltersToQuery(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	opts := &ListTablesOptions{
+		Types:       []string{TableTypeView},
+		Pattern:     "order%",
+		IncludeSize: true,
+	}
+	tablesWithStats := []*TableInfo{{Schema: "public", Name: "orders_summary", Type: TableTypeView, RowCount: 10}}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListTablesWithStats", mock.Anything, &ListTablesOptions{
+		Schema:      DefaultSchema,
+		Types:       []string{TableTypeView},
+		Pattern:     "order%",
+		IncludeSize: true,
+	}).Return(tablesWithStats, nil)
+
+	tables, err := app.ListTables(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, tablesWithStats, tables)
+	assert.Empty(t, opts.Schema, "caller options must not be modified")
+	mockClient.AssertExpectations(t)
+}
+
 func TestApp_DescribeTable(t *testing.T) {
 	mockClient := &MockPostgreSQLClient{}
 	app := New(mockClient)
@@ -360,22 +596,31 @@ func TestApp_DescribeTable(t *testing.T) {
 		{Name: "id", DataType: "integer", IsNullable: false},
 		{Name: "name", DataType: "varchar(255)", IsNullable: true},
 	}
+	expectedConstraints := []*ConstraintInfo{
+		{Name: "users_pkey", Type: ConstraintPrimaryKey, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+	}
 
 	mockClient.On("Ping", mock.Anything).Return(nil)
 	mockClient.On("DescribeTable", mock.Anything, "public", "users").Return(expectedColumns, nil)
+	mockClient.On("ListConstraints", mock.Anything, "public", "users").Return(expectedConstraints, nil)
 
-	columns, err := app.DescribeTable(context.Background(), "public", "users")
+	description, err := app.DescribeTable(context.Background(), "public", "users")
 	assert.NoError(t, err)
-	assert.Equal(t, expectedColumns, columns)
+	assert.Equal(t, &TableDescription{
+		Schema:      "public",
+		Name:        "users",
+		Columns:     expectedColumns,
+		Constraints: expectedConstraints,
+	}, description)
 	mockClient.AssertExpectations(t)
 }
 
 func TestApp_DescribeTableEmptyTableName(t *testing.T) {
 	app, _ := NewDefault()
 
-	columns, err := app.DescribeTable(context.Background(), "public", "")
+	description, err := app.DescribeTable(context.Background(), "public", "")
 	assert.Error(t, err)
-	assert.Nil(t, columns)
+	assert.Nil(t, description)
 	assert.Contains(t, err.Error(), "database connection failed")
 }
 
@@ -389,10 +634,28 @@ func TestApp_DescribeTableDefaultSchema(t *testing.T) {
 
 	mockClient.On("Ping", mock.Anything).Return(nil)
 	mockClient.On("DescribeTable", mock.Anything, DefaultSchema, "users").Return(expectedColumns, nil)
+	mockClient.On("ListConstraints", mock.Anything, DefaultSchema, "users").Return(nil, nil)
 
-	columns, err := app.DescribeTable(context.Background(), "", "users")
+	description, err := app.DescribeTable(context.Background(), "", "users")
 	assert.NoError(t, err)
-	assert.Equal(t, expectedColumns, columns)
+	assert.Equal(t, DefaultSchema, description.Schema)
+	assert.Equal(t, expectedColumns, description.Columns)
+	assert.Equal(t, []*ConstraintInfo{}, description.Constraints, "no constraints is an empty list, not null")
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_DescribeTableConstraintsError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("DescribeTable", mock.Anything, "public", "users").Return([]*ColumnInfo{{Name: "id"}}, nil)
+	mockClient.On("ListConstraints", mock.Anything, "public", "users").Return(nil, errors.New("permission denied"))
+
+	description, err := app.DescribeTable(context.Background(), "public", "users")
+	assert.Error(t, err)
+	assert.Nil(t, description)
+	assert.Contains(t, err.Error(), "failed to describe table")
 	mockClient.AssertExpectations(t)
 }
 
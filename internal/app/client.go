@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	"strconv"
@@ -12,7 +13,11 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 const (
@@ -31,6 +36,9 @@ const (
 	defaultMaxIdleConns    = 5
 	defaultConnMaxLifetime = time.Hour
 	defaultConnMaxIdleTime = 10 * time.Minute
+	// defaultHealthCheckPeriod is how often the pool checks idle connections
+	// and replaces broken or expired ones.
+	defaultHealthCheckPeriod = time.Minute
 
 	// defaultMaxResultRows is the maximum number of rows returned by a query.
 	// Configurable via POSTGRES_MCP_MAX_RESULT_ROWS environment variable.
@@ -45,6 +53,15 @@ const (
 	// table cannot tie up a pool connection for minutes (issue #90).
 	countFallbackTimeout = 5 * time.Second
 
+	// maxNumericPrecision is PostgreSQL's upper bound for numeric(p, s);
+	// anything outside 1..maxNumericPrecision decoded from a typmod is
+	// discarded.
+	maxNumericPrecision = 1000
+
+	// varHeaderSize is the header length PostgreSQL folds into the typmod of
+	// varchar, bpchar, and numeric columns.
+	varHeaderSize = 4
+
 	// defaultQueryTimeout bounds every tool handler's context and is also
 	// pushed into the connection options as statement_timeout so PostgreSQL
 	// cancels a runaway query even if the client context is unbounded
@@ -149,14 +166,45 @@ func injectStatementTimeout(connStr string, d time.Duration) string {
 
 // PostgreSQLClientImpl implements the PostgreSQLClient interface.
 //
-// db is held as atomic.Pointer so that concurrent handler goroutines can
-// load the current *sql.DB without locking, while Connect can atomically
-// swap in a freshly opened pool during reconnection (issue #83).
+// conn is held as atomic.Pointer so that concurrent handler goroutines can
+// load the current pool without locking, while Connect can atomically swap
+// in a freshly opened pool during reconnection (issue #83).
 type PostgreSQLClientImpl struct {
-	db               atomic.Pointer[sql.DB]
+	conn             atomic.Pointer[connection]
 	connectionString string
 }
 
+// connection is a pgx pool together with a database/sql view of it. User
+// queries run on the pool directly so results are decoded from the binary
+// protocol with full type information; catalog queries and GetDB use the
+// *sql.DB, which borrows connections from the same pool.
+type connection struct {
+	pool *pgxpool.Pool
+	db   *sql.DB
+}
+
+func (c *connection) close() {
+	_ = c.db.Close()
+	c.pool.Close()
+}
+
+// sqlDB returns the database/sql view of the current pool, or nil when not
+// connected.
+func (c *PostgreSQLClientImpl) sqlDB() *sql.DB {
+	if conn := c.conn.Load(); conn != nil {
+		return conn.db
+	}
+	return nil
+}
+
+// pgxPool returns the current pool, or nil when not connected.
+func (c *PostgreSQLClientImpl) pgxPool() *pgxpool.Pool {
+	if conn := c.conn.Load(); conn != nil {
+		return conn.pool
+	}
+	return nil
+}
+
 // NewPostgreSQLClient creates a new PostgreSQL client.
 func NewPostgreSQLClient() *PostgreSQLClientImpl {
 	return &PostgreSQLClientImpl{}
@@ -173,6 +221,13 @@ func envIntOrDefault(key string, defaultVal int) int {
 	return defaultVal
 }
 
+// healthCheckPeriod returns the pool health-check interval from
+// POSTGRES_MCP_HEALTH_CHECK_PERIOD (seconds).
+func healthCheckPeriod() time.Duration {
+	return time.Duration(envIntOrDefault("POSTGRES_MCP_HEALTH_CHECK_PERIOD",
+		int(defaultHealthCheckPeriod.Seconds()))) * time.Second
+}
+
 // poolConfig returns connection pool settings from environment variables,
 // falling back to sensible defaults for the MCP server use case.
 func poolConfig() (int, int, time.Duration, time.Duration) {
@@ -197,29 +252,46 @@ func maxResultRows() int {
 //   - POSTGRES_MCP_MAX_IDLE_CONNS (default: 5)
 //   - POSTGRES_MCP_CONN_MAX_LIFETIME (seconds, default: 3600)
 //   - POSTGRES_MCP_CONN_MAX_IDLE_TIME (seconds, default: 600)
+//   - POSTGRES_MCP_HEALTH_CHECK_PERIOD (seconds, default: 60)
 func (c *PostgreSQLClientImpl) Connect(ctx context.Context, connectionString string) error {
 	hardenedConnStr := injectStatementTimeout(injectReadOnlyOption(connectionString), QueryTimeout())
-	db, err := sql.Open("postgres", hardenedConnStr)
+	config, err := pgxpool.ParseConfig(hardenedConnStr)
 	if err != nil {
 		return fmt.Errorf("failed to open database connection: %w", err)
 	}
 
 	maxOpen, maxIdle, maxLifetime, maxIdleTime := poolConfig()
-	db.SetMaxOpenConns(maxOpen)
-	db.SetMaxIdleConns(maxIdle)
-	db.SetConnMaxLifetime(maxLifetime)
-	db.SetConnMaxIdleTime(maxIdleTime)
+	config.MaxConns = int32(min(maxOpen, math.MaxInt32)) //nolint:gosec // bounded above
+	config.MaxConnLifetime = maxLifetime
+	config.MaxConnIdleTime = maxIdleTime
+	config.HealthCheckPeriod = healthCheckPeriod()
 
-	if err := db.PingContext(ctx); err != nil {
-		_ = db.Close()
+	// pgxpool has no idle-connection cap of its own: destroy a released
+	// connection instead of returning it when maxIdle are already idle.
+	var pool *pgxpool.Pool
+	config.AfterRelease = func(*pgx.Conn) bool {
+		return pool.Stat().IdleConns() < int32(min(maxIdle, math.MaxInt32)) //nolint:gosec // bounded above
+	}
+
+	pool, err = pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	db := stdlib.OpenDBFromPool(pool)
+	db.SetMaxOpenConns(maxOpen)
+	db.SetConnMaxLifetime(maxLifetime)
+	db.SetConnMaxIdleTime(maxIdleTime)
+
 	// Atomic swap; close any previous pool that handler goroutines may still
-	// hold a reference to. sql.DB.Close waits for in-flight queries on that
-	// handle to drain, so concurrent readers degrade gracefully.
-	if old := c.db.Swap(db); old != nil {
-		_ = old.Close()
+	// hold a reference to. pgxpool.Close waits for acquired connections to be
+	// released, so concurrent readers degrade gracefully.
+	if old := c.conn.Swap(&connection{pool: pool, db: db}); old != nil {
+		old.close()
 	}
 	c.connectionString = connectionString
 	return nil
@@ -227,42 +299,44 @@ func (c *PostgreSQLClientImpl) Connect(ctx context.Context, connectionString str
 
 // Close closes the database connection.
 func (c *PostgreSQLClientImpl) Close() error {
-	db := c.db.Swap(nil)
-	if db == nil {
+	conn := c.conn.Swap(nil)
+	if conn == nil {
 		return nil
 	}
-	if err := db.Close(); err != nil {
-		return fmt.Errorf("failed to close database: %w", err)
-	}
+	conn.close()
 	return nil
 }
 
 // Ping checks if the database connection is alive.
 func (c *PostgreSQLClientImpl) Ping(ctx context.Context) error {
-	db := c.db.Load()
-	if db == nil {
+	pool := c.pgxPool()
+	if pool == nil {
 		return ErrNoDatabaseConnection
 	}
-	if err := db.PingContext(ctx); err != nil {
+	if err := pool.Ping(ctx); err != nil {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 	return nil
 }
 
-// GetDB returns the underlying sql.DB connection.
+// GetDB returns a database/sql handle backed by the connection pool.
 func (c *PostgreSQLClientImpl) GetDB() *sql.DB {
-	return c.db.Load()
+	return c.sqlDB()
 }
 
 // ListDatabases returns a list of all databases on the server.
 func (c *PostgreSQLClientImpl) ListDatabases(ctx context.Context) ([]*DatabaseInfo, error) {
-	db := c.db.Load()
+	db := c.sqlDB()
 	if db == nil {
 		return nil, ErrNoDatabaseConnection
 	}
 
 	query := `
-		SELECT datname, pg_catalog.pg_get_userbyid(datdba) as owner, pg_encoding_to_char(encoding) as encoding
+		SELECT
+			datname,
+			pg_catalog.pg_get_userbyid(datdba) as owner,
+			pg_encoding_to_char(encoding) as encoding,
+			COALESCE(shobj_description(oid, 'pg_database'), '') as description
 		FROM pg_database
 		WHERE datistemplate = false
 		ORDER BY datname`
@@ -276,7 +350,7 @@ func (c *PostgreSQLClientImpl) ListDatabases(ctx context.Context) ([]*DatabaseIn
 	var databases []*DatabaseInfo
 	for rows.Next() {
 		var info DatabaseInfo
-		if err := rows.Scan(&info.Name, &info.Owner, &info.Encoding); err != nil {
+		if err := rows.Scan(&info.Name, &info.Owner, &info.Encoding, &info.Description); err != nil {
 			return nil, fmt.Errorf("failed to scan database row: %w", err)
 		}
 		databases = append(databases, &info)
@@ -290,7 +364,7 @@ func (c *PostgreSQLClientImpl) ListDatabases(ctx context.Context) ([]*DatabaseIn
 
 // GetCurrentDatabase returns the name of the current database.
 func (c *PostgreSQLClientImpl) GetCurrentDatabase(ctx context.Context) (string, error) {
-	db := c.db.Load()
+	db := c.sqlDB()
 	if db == nil {
 		return "", ErrNoDatabaseConnection
 	}
@@ -306,13 +380,16 @@ func (c *PostgreSQLClientImpl) GetCurrentDatabase(ctx context.Context) (string,
 
 // ListSchemas returns a list of schemas in the current database.
 func (c *PostgreSQLClientImpl) ListSchemas(ctx context.Context) ([]*SchemaInfo, error) {
-	db := c.db.Load()
+	db := c.sqlDB()
 	if db == nil {
 		return nil, ErrNoDatabaseConnection
 	}
 
 	query := `
-		SELECT schema_name, schema_owner
+		SELECT
+			schema_name,
+			schema_owner,
+			COALESCE(obj_description(quote_ident(schema_name)::regnamespace, 'pg_namespace'), '') as description
 		FROM information_schema.schemata
 		WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
 		ORDER BY schema_name`
@@ -326,7 +403,7 @@ func (c *PostgreSQLClientImpl) ListSchemas(ctx context.Context) ([]*SchemaInfo,
 	var schemas []*SchemaInfo
 	for rows.Next() {
 		var schema SchemaInfo
-		if err := rows.Scan(&schema.Name, &schema.Owner); err != nil {
+		if err := rows.Scan(&schema.Name, &schema.Owner, &schema.Description); err != nil {
 			return nil, fmt.Errorf("failed to scan schema row: %w", err)
 		}
 		schemas = append(schemas, &schema)
@@ -338,45 +415,122 @@ func (c *PostgreSQLClientImpl) ListSchemas(ctx context.Context) ([]*SchemaInfo,
 	return schemas, nil
 }
 
-// ListTables returns a list of tables in the specified schema.
-func (c *PostgreSQLClientImpl) ListTables(ctx context.Context, schema string) ([]*TableInfo, error) {
-	db := c.db.Load()
-	if db == nil {
-		return nil, ErrNoDatabaseConnection
-	}
+// relationColumns selects the TableInfo fields shared by ListTables and
+// ListTablesWithStats from pg_class c joined to pg_namespace n. Partition
+// names are rendered with format('%I.%I') so they can be pasted into SQL.
+const relationColumns = `
+		n.nspname,
+		c.relname,
+		CASE c.relkind
+			WHEN 'r' THEN 'table'
+			WHEN 'p' THEN 'partitioned table'
+			WHEN 'v' THEN 'view'
+			WHEN 'm' THEN 'materialized view'
+			WHEN 'f' THEN 'foreign table'
+		END AS type,
+		pg_get_userbyid(c.relowner) AS owner,
+		COALESCE(obj_description(c.oid, 'pg_class'), '') AS description,
+		COALESCE((
+			SELECT format('%I.%I', pn.nspname, pc.relname)
+			FROM pg_inherits i
+			JOIN pg_class pc ON pc.oid = i.inhparent
+			JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+			WHERE i.inhrelid = c.oid AND c.relispartition
+		), '') AS partition_of,
+		CASE WHEN c.relispartition THEN COALESCE(pg_get_expr(c.relpartbound, c.oid), '') ELSE '' END AS partition_bound,
+		ARRAY(
+			SELECT format('%I.%I', cn.nspname, ch.relname)
+			FROM pg_inherits i
+			JOIN pg_class ch ON ch.oid = i.inhrelid
+			JOIN pg_namespace cn ON cn.oid = ch.relnamespace
+			WHERE i.inhparent = c.oid AND ch.relispartition
+			ORDER BY ch.relname
+		) AS partitions`
+
+// relationKinds maps the TableInfo types to pg_class.relkind.
+var relationKinds = map[string]string{
+	TableTypeTable:            "r",
+	TableTypePartitionedTable: "p",
+	TableTypeView:             "v",
+	TableTypeMaterializedView: "m",
+	TableTypeForeignTable:     "f",
+}
 
+// relationFilter restricts the relations of the schema $1 to the Types
+// ($2, as relkinds), Pattern ($3, a case-insensitive LIKE pattern), and
+// Search ($4, a case-insensitive substring of the name or comment) of
+// ListTablesOptions. relationFilterArgs binds them.
+const relationFilter = `
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+			AND (cardinality($2::text[]) = 0 OR c.relkind::text = ANY($2::text[]))
+			AND ($3 = '' OR c.relname ILIKE $3)
+			AND ($4 = '' OR strpos(lower(c.relname), lower($4)) > 0
+				OR strpos(lower(COALESCE(obj_description(c.oid, 'pg_class'), '')), lower($4)) > 0)`
+
+// relationFilterArgs returns the relationFilter arguments for opts, with
+// the schema defaulting to DefaultSchema.
+func relationFilterArgs(opts *ListTablesOptions) []any {
+	if opts == nil {
+		opts = &ListTablesOptions{}
+	}
+	schema := opts.Schema
 	if schema == "" {
 		schema = DefaultSchema
 	}
+	relkinds := make([]string, 0, len(opts.Types))
+	for _, t := range opts.Types {
+		relkinds = append(relkinds, relationKinds[t])
+	}
+	return []any{schema, relkinds, opts.Pattern, opts.Search}
+}
+
+// scanRelation scans the relationColumns prefix of a row, followed by extra.
+func scanRelation(rows *sql.Rows, typeMap *pgtype.Map, table *TableInfo, extra ...any) error {
+	dest := append([]any{
+		&table.Schema,
+		&table.Name,
+		&table.Type,
+		&table.Owner,
+		&table.Description,
+		&table.PartitionOf,
+		&table.PartitionBound,
+		typeMap.SQLScanner(&table.Partitions),
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return err //nolint:wrapcheck // wrapped by the caller with its own context
+	}
+	if len(table.Partitions) == 0 {
+		table.Partitions = nil
+	}
+	return nil
+}
+
+// ListTables returns the relations in opts.Schema matching its Types,
+// Pattern, and Search filters: tables, partitioned tables, views,
+// materialized views, and foreign tables.
+func (c *PostgreSQLClientImpl) ListTables(ctx context.Context, opts *ListTablesOptions) ([]*TableInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
 
 	query := `
-		SELECT
-			schemaname,
-			tablename,
-			'table' as type,
-			tableowner as owner
-		FROM pg_tables
-		WHERE schemaname = $1
-		UNION ALL
-		SELECT
-			schemaname,
-			viewname as tablename,
-			'view' as type,
-			viewowner as owner
-		FROM pg_views
-		WHERE schemaname = $1
-		ORDER BY tablename`
-
-	rows, err := db.QueryContext(ctx, query, schema)
+		SELECT` + relationColumns + `
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace` + relationFilter + `
+		ORDER BY c.relname`
+
+	rows, err := db.QueryContext(ctx, query, relationFilterArgs(opts)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tables: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	typeMap := pgtype.NewMap()
 	var tables []*TableInfo
 	for rows.Next() {
 		var table TableInfo
-		if err := rows.Scan(&table.Schema, &table.Name, &table.Type, &table.Owner); err != nil {
+		if err := scanRelation(rows, typeMap, &table); err != nil {
 			return nil, fmt.Errorf("failed to scan table row: %w", err)
 		}
 		tables = append(tables, &table)
@@ -391,73 +545,65 @@ func (c *PostgreSQLClientImpl) ListTables(ctx context.Context, schema string) ([
 // ListTablesWithStats returns a list of tables with size and row count statistics in a single query.
 // Row count prefers pg_stat_user_tables (n_tup_ins - n_tup_del); when that is 0 — e.g., fresh tables —
 // it falls back to pg_class.reltuples in the same SELECT, so the result remains O(1) round-trips
-// regardless of how many tables show empty statistics.
-func (c *PostgreSQLClientImpl) ListTablesWithStats(ctx context.Context, schema string) ([]*TableInfo, error) {
-	db := c.db.Load()
+// regardless of how many tables show empty statistics. The Types, Pattern, and Search filters of
+// opts are applied by the query, so the COUNT(*) fallback is only spent on the tables returned.
+func (c *PostgreSQLClientImpl) ListTablesWithStats(ctx context.Context, opts *ListTablesOptions) ([]*TableInfo, error) {
+	db := c.sqlDB()
 	if db == nil {
 		return nil, ErrNoDatabaseConnection
 	}
 
-	if schema == "" {
-		schema = DefaultSchema
-	}
-
-	// Single optimized query that joins tables with statistics. Row count uses
+	// Single optimized query that joins relations with statistics. Row count uses
 	// n_tup_ins - n_tup_del (more accurate than n_live_tup after recent writes);
 	// when that is 0 — pg_stat not yet populated for fresh tables — fall back to
 	// pg_class.reltuples in the same round-trip. Avoids per-table COUNT(*) which
 	// is O(rows) and previously fanned out as N+1 over the result set (issue #90).
 	// reltuples is -1 on PG14+ until first ANALYZE, so clamp with GREATEST.
+	// A partitioned table holds no rows or storage itself, so its row count and
+	// size are summed over the leaf partitions of its pg_partition_tree.
 	query := `
-		WITH table_list AS (
-			SELECT
-				schemaname,
-				tablename,
-				'table' as type,
-				tableowner as owner
-			FROM pg_tables
-			WHERE schemaname = $1
-			UNION ALL
-			SELECT
-				schemaname,
-				viewname as tablename,
-				'view' as type,
-				viewowner as owner
-			FROM pg_views
-			WHERE schemaname = $1
-		)
-		SELECT
-			t.schemaname,
-			t.tablename,
-			t.type,
-			t.owner,
+		SELECT` + relationColumns + `,
 			CASE
+				WHEN c.relkind = 'p' THEN (
+					SELECT COALESCE(SUM(CASE
+						WHEN COALESCE(ls.n_tup_ins - ls.n_tup_del, 0) > 0 THEN ls.n_tup_ins - ls.n_tup_del
+						ELSE GREATEST(lc.reltuples::bigint, 0)
+					END), 0)::bigint
+					FROM pg_partition_tree(c.oid) pt
+					JOIN pg_class lc ON lc.oid = pt.relid
+					LEFT JOIN pg_stat_user_tables ls ON ls.relid = lc.oid
+					WHERE pt.isleaf
+				)
 				WHEN COALESCE(s.n_tup_ins - s.n_tup_del, 0) > 0
 					THEN s.n_tup_ins - s.n_tup_del
-				WHEN t.type = 'table'
-					THEN GREATEST(COALESCE(c.reltuples, 0)::bigint, 0)
+				WHEN c.relkind IN ('r', 'm')
+					THEN GREATEST(c.reltuples::bigint, 0)
 				ELSE 0
-			END as row_count,
-			pg_size_pretty(COALESCE(pg_total_relation_size(quote_ident(t.schemaname) || '.' || quote_ident(t.tablename)), 0)) as size
-		FROM table_list t
-		LEFT JOIN pg_stat_user_tables s
-			ON t.schemaname = s.schemaname AND t.tablename = s.relname
-		LEFT JOIN pg_namespace n
-			ON n.nspname = t.schemaname
-		LEFT JOIN pg_class c
-			ON c.relname = t.tablename AND c.relnamespace = n.oid AND c.relkind IN ('r', 'p')
-		ORDER BY t.tablename`
-
-	rows, err := db.QueryContext(ctx, query, schema)
+			END AS row_count,
+			pg_size_pretty(CASE
+				WHEN c.relkind = 'p' THEN (
+					SELECT COALESCE(SUM(pg_total_relation_size(pt.relid)), 0)::bigint
+					FROM pg_partition_tree(c.oid) pt
+					WHERE pt.isleaf
+				)
+				ELSE COALESCE(pg_total_relation_size(c.oid), 0)
+			END) AS size
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid` + relationFilter + `
+		ORDER BY c.relname`
+
+	rows, err := db.QueryContext(ctx, query, relationFilterArgs(opts)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tables with stats: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	typeMap := pgtype.NewMap()
 	var tables []*TableInfo
 	for rows.Next() {
 		var table TableInfo
-		if err := rows.Scan(&table.Schema, &table.Name, &table.Type, &table.Owner, &table.RowCount, &table.Size); err != nil {
+		if err := scanRelation(rows, typeMap, &table, &table.RowCount, &table.Size); err != nil {
 			return nil, fmt.Errorf("failed to scan table row with stats: %w", err)
 		}
 		tables = append(tables, &table)
@@ -471,9 +617,84 @@ func (c *PostgreSQLClientImpl) ListTablesWithStats(ctx context.Context, schema s
 	return tables, nil
 }
 
-// DescribeTable returns detailed column information for a table.
+// The column expressions below are shared by describeColumnsQuery and
+// snapshotColumnsQuery, so that describe_table and diff_schema agree on a
+// column. They read the pg_attribute row a and its pg_attrdef row d.
+const (
+	// columnDataType renders the type with its modifiers.
+	columnDataType = `format_type(a.atttypid, a.atttypmod)`
+	// columnDefault is the DEFAULT expression; a generated column has none.
+	columnDefault = `CASE WHEN a.attgenerated = '' THEN COALESCE(pg_get_expr(d.adbin, d.adrelid), '') ELSE '' END`
+	// columnIdentity is "always", "by default", or empty.
+	columnIdentity = `CASE a.attidentity WHEN 'a' THEN 'always' WHEN 'd' THEN 'by default' ELSE '' END`
+	// columnGenerated is "stored", "virtual", or empty.
+	columnGenerated = `CASE a.attgenerated WHEN 's' THEN 'stored' WHEN 'v' THEN 'virtual' ELSE '' END`
+	// columnGenerationExpression is the expression of a generated column.
+	columnGenerationExpression = `CASE WHEN a.attgenerated <> '' THEN COALESCE(pg_get_expr(d.adbin, d.adrelid), '') ELSE '' END`
+)
+
+// describeColumnsQuery reads column metadata straight from pg_attribute so
+// types keep their modifiers (format_type renders "character varying(255)",
+// "integer[]", or the enum/domain name where information_schema says
+// "character varying", "ARRAY", or "USER-DEFINED"), and columns the caller
+// cannot read are still listed. attstattarget is -1 (NULL on PG17+) unless
+// set with ALTER COLUMN ... SET STATISTICS. Enum labels are resolved through
+// domains and arrays so an enum[] or a domain over an enum still lists them.
+const describeColumnsQuery = `
+	SELECT
+		a.attname,
+		` + columnDataType + ` AS data_type,
+		NOT a.attnotnull AS is_nullable,
+		` + columnDefault + ` AS default_value,
+		COALESCE(col_description(c.oid, a.attnum), '') AS description,
+		` + columnIdentity + ` AS identity,
+		` + columnGenerated + ` AS generated,
+		` + columnGenerationExpression + ` AS generation_expression,
+		CASE WHEN a.attcollation <> 0 AND a.attcollation <> t.typcollation THEN co.collname ELSE '' END AS collation,
+		CASE WHEN t.typcategory = 'A' THEN format_type(t.typelem, a.atttypmod) ELSE '' END AS element_type,
+		CASE WHEN t.typtype = 'd' THEN format_type(t.typbasetype, t.typtypmod) ELSE '' END AS domain_base_type,
+		ARRAY(
+			SELECT e.enumlabel
+			FROM pg_enum e
+			WHERE e.enumtypid = CASE
+				WHEN t.typtype = 'd' THEN t.typbasetype
+				WHEN t.typcategory = 'A' THEN t.typelem
+				ELSE t.oid
+			END
+			ORDER BY e.enumsortorder
+		) AS enum_labels,
+		NULLIF(a.attstattarget, -1) AS statistics_target,
+		has_column_privilege(c.oid, a.attnum, 'SELECT') AS selectable
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_type t ON t.oid = a.atttypid
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	LEFT JOIN pg_collation co ON co.oid = a.attcollation
+	WHERE n.nspname = $1 AND c.relname = $2
+		AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+		AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attnum`
+
+// describeColumnsFallbackQuery is used when the catalog query is refused
+// (some managed services restrict pg_catalog). information_schema only lists
+// columns the caller holds a privilege on and reports generic type names.
+const describeColumnsFallbackQuery = `
+	SELECT
+		column_name,
+		data_type,
+		is_nullable = 'YES' AS is_nullable,
+		COALESCE(column_default, '') AS default_value,
+		COALESCE(col_description(format('%I.%I', table_schema, table_name)::regclass, ordinal_position::int), '') AS description,
+		has_column_privilege(format('%I.%I', table_schema, table_name), column_name, 'SELECT') AS selectable
+	FROM information_schema.columns
+	WHERE table_schema = $1 AND table_name = $2
+	ORDER BY ordinal_position`
+
+// DescribeTable returns detailed column information for a table, view,
+// materialized view, or foreign table.
 func (c *PostgreSQLClientImpl) DescribeTable(ctx context.Context, schema, table string) ([]*ColumnInfo, error) {
-	db := c.db.Load()
+	db := c.sqlDB()
 	if db == nil {
 		return nil, ErrNoDatabaseConnection
 	}
@@ -482,46 +703,213 @@ func (c *PostgreSQLClientImpl) DescribeTable(ctx context.Context, schema, table
 		schema = DefaultSchema
 	}
 
-	query := `
-		SELECT
-			column_name,
-			data_type,
-			is_nullable = 'YES' as is_nullable,
-			COALESCE(column_default, '') as default_value
-		FROM information_schema.columns
-		WHERE table_schema = $1 AND table_name = $2
-		ORDER BY ordinal_position`
+	columns, err := describeColumns(ctx, db, schema, table)
+	if isInsufficientPrivilege(err) {
+		columns, err = describeColumnsFallback(ctx, db, schema, table)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := db.QueryContext(ctx, query, schema, table)
+	// Check if table exists (if no columns found, table doesn't exist)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+	}
+
+	return columns, nil
+}
+
+func describeColumns(ctx context.Context, db *sql.DB, schema, table string) ([]*ColumnInfo, error) {
+	rows, err := db.QueryContext(ctx, describeColumnsQuery, schema, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	typeMap := pgtype.NewMap()
 	var columns []*ColumnInfo
 	for rows.Next() {
 		var column ColumnInfo
-		if err := rows.Scan(&column.Name, &column.DataType, &column.IsNullable, &column.DefaultValue); err != nil {
+		var statisticsTarget sql.NullInt64
+		if err := rows.Scan(
+			&column.Name,
+			&column.DataType,
+			&column.IsNullable,
+			&column.DefaultValue,
+			&column.Description,
+			&column.Identity,
+			&column.Generated,
+			&column.GenerationExpression,
+			&column.Collation,
+			&column.ElementType,
+			&column.DomainBaseType,
+			typeMap.SQLScanner(&column.EnumLabels),
+			&statisticsTarget,
+			&column.Selectable,
+		); err != nil {
 			return nil, fmt.Errorf("failed to scan column row: %w", err)
 		}
+		if statisticsTarget.Valid {
+			column.StatisticsTarget = &statisticsTarget.Int64
+		}
 		columns = append(columns, &column)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("failed to iterate column rows: %w", err)
 	}
+	return columns, nil
+}
 
-	// Check if table exists (if no columns found, table doesn't exist)
-	if len(columns) == 0 {
-		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+func describeColumnsFallback(ctx context.Context, db *sql.DB, schema, table string) ([]*ColumnInfo, error) {
+	rows, err := db.QueryContext(ctx, describeColumnsFallbackQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
+	defer func() { _ = rows.Close() }()
 
+	var columns []*ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		if err := rows.Scan(
+			&column.Name, &column.DataType, &column.IsNullable, &column.DefaultValue, &column.Description,
+			&column.Selectable,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan column row: %w", err)
+		}
+		columns = append(columns, &column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate column rows: %w", err)
+	}
 	return columns, nil
 }
 
+// isInsufficientPrivilege reports whether err is a PostgreSQL
+// insufficient_privilege (42501) error.
+func isInsufficientPrivilege(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "42501"
+}
+
+// constraintTypes maps pg_constraint.contype to ConstraintInfo.Type.
+var constraintTypes = map[string]string{
+	"p": ConstraintPrimaryKey,
+	"u": ConstraintUnique,
+	"f": ConstraintForeignKey,
+	"c": ConstraintCheck,
+	"x": ConstraintExclusion,
+}
+
+// foreignKeyActions maps pg_constraint.confdeltype/confupdtype to the
+// referential action as written in SQL.
+var foreignKeyActions = map[string]string{
+	"a": "no action",
+	"r": "restrict",
+	"c": "cascade",
+	"n": "set null",
+	"d": "set default",
+}
+
+// ListConstraints returns the constraints of a specific table, primary key
+// first, then unique, foreign key, check, and exclusion constraints by name.
+func (c *PostgreSQLClientImpl) ListConstraints(ctx context.Context, schema, table string) ([]*ConstraintInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	// conkey/confkey are unnested WITH ORDINALITY so composite keys keep
+	// their declared column order and FK columns pair up with the
+	// referenced ones.
+	query := `
+		SELECT
+			con.conname,
+			con.contype::text,
+			ARRAY(
+				SELECT a.attname
+				FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			) AS columns,
+			pg_get_constraintdef(con.oid, true) AS definition,
+			COALESCE(fn.nspname, '') AS referenced_schema,
+			COALESCE(fc.relname, '') AS referenced_table,
+			ARRAY(
+				SELECT a.attname
+				FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			) AS referenced_columns,
+			con.confdeltype::text,
+			con.confupdtype::text,
+			con.condeferrable,
+			con.condeferred
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_class fc ON fc.oid = con.confrelid
+		LEFT JOIN pg_namespace fn ON fn.oid = fc.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2
+			AND con.contype IN ('p', 'u', 'f', 'c', 'x')
+		ORDER BY
+			CASE con.contype WHEN 'p' THEN 0 WHEN 'u' THEN 1 WHEN 'f' THEN 2 WHEN 'c' THEN 3 ELSE 4 END,
+			con.conname`
+
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list constraints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	var constraints []*ConstraintInfo
+	for rows.Next() {
+		var constraint ConstraintInfo
+		var contype, onDelete, onUpdate string
+		if err := rows.Scan(
+			&constraint.Name,
+			&contype,
+			typeMap.SQLScanner(&constraint.Columns),
+			&constraint.Definition,
+			&constraint.ReferencedSchema,
+			&constraint.ReferencedTable,
+			typeMap.SQLScanner(&constraint.ReferencedColumns),
+			&onDelete,
+			&onUpdate,
+			&constraint.Deferrable,
+			&constraint.InitiallyDeferred,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan constraint row: %w", err)
+		}
+		constraint.Type = constraintTypes[contype]
+		if constraint.Type == ConstraintForeignKey {
+			constraint.OnDelete = foreignKeyActions[onDelete]
+			constraint.OnUpdate = foreignKeyActions[onUpdate]
+		} else {
+			constraint.ReferencedColumns = nil
+		}
+		if constraint.Columns == nil {
+			constraint.Columns = []string{}
+		}
+		constraints = append(constraints, &constraint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate constraint rows: %w", err)
+	}
+
+	return constraints, nil
+}
+
 // GetTableStats returns statistics for a specific table.
 func (c *PostgreSQLClientImpl) GetTableStats(ctx context.Context, schema, table string) (*TableInfo, error) {
-	db := c.db.Load()
+	db := c.sqlDB()
 	if db == nil {
 		return nil, ErrNoDatabaseConnection
 	}
@@ -568,9 +956,7 @@ func (c *PostgreSQLClientImpl) GetTableStats(ctx context.Context, schema, table
 	// The fallback is bounded by countFallbackTimeout so a single billion-row
 	// table cannot tie up a pool connection for minutes (issue #90).
 	if tableInfo.RowCount == 0 {
-		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s",
-			pq.QuoteIdentifier(schema),
-			pq.QuoteIdentifier(table))
+		countQuery := "SELECT COUNT(*) FROM " + pgx.Identifier{schema, table}.Sanitize()
 		countCtx, cancel := context.WithTimeout(ctx, countFallbackTimeout)
 		defer cancel()
 		var actualCount int64
@@ -582,9 +968,72 @@ func (c *PostgreSQLClientImpl) GetTableStats(ctx context.Context, schema, table
 	return tableInfo, nil
 }
 
+// indexDefinition renders the CREATE INDEX statement of the index ic. It is
+// shared by listIndexesQuery, ddlIndexesQuery, and snapshotIndexesQuery, so
+// that list_indexes, get_ddl, and diff_schema agree on an index. Indexes of
+// partitioned tables are rendered without ONLY so that they cascade to the
+// partitions.
+const indexDefinition = `CASE WHEN ic.relkind = 'I' THEN regexp_replace(pg_get_indexdef(ic.oid), ' ON ONLY ', ' ON ')
+			ELSE pg_get_indexdef(ic.oid) END`
+
+// indexNotFromConstraint holds for the indexes of pg_index i that were not
+// created by a primary key, unique, or exclusion constraint.
+const indexNotFromConstraint = `NOT EXISTS (
+			SELECT 1 FROM pg_constraint con
+			WHERE con.conrelid = i.indrelid AND con.conindid = i.indexrelid AND con.contype IN ('p', 'u', 'x')
+		)`
+
+// listIndexesQuery lists the indexes of a table. indkey holds one entry per
+// index column, key columns first and INCLUDE columns after indnkeyatts;
+// an entry of 0 marks an expression, which only pg_get_indexdef can render.
+// Usage counters come from pg_stat_user_indexes and are 0 for indexes not
+// yet tracked there.
+const listIndexesQuery = `
+	SELECT
+		ic.relname,
+		t.relname,
+		ARRAY(
+			SELECT CASE WHEN k.attnum = 0 THEN pg_get_indexdef(i.indexrelid, k.ord::int, true) ELSE a.attname::text END
+			FROM unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			LEFT JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+			WHERE k.ord <= i.indnkeyatts
+			ORDER BY k.ord
+		),
+		ARRAY(
+			SELECT a.attname::text
+			FROM unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+			WHERE k.ord > i.indnkeyatts
+			ORDER BY k.ord
+		),
+		ARRAY(
+			SELECT pg_get_indexdef(i.indexrelid, k.ord::int, true)
+			FROM unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			WHERE k.attnum = 0
+			ORDER BY k.ord
+		),
+		COALESCE(pg_get_expr(i.indpred, i.indrelid, true), ''),
+		i.indisunique,
+		i.indisprimary,
+		i.indisvalid,
+		i.indisready,
+		am.amname,
+		pg_size_pretty(pg_relation_size(i.indexrelid)),
+		COALESCE(s.idx_scan, 0),
+		COALESCE(s.idx_tup_read, 0),
+		` + indexDefinition + `
+	FROM pg_index i
+	JOIN pg_class t ON t.oid = i.indrelid
+	JOIN pg_class ic ON ic.oid = i.indexrelid
+	JOIN pg_am am ON am.oid = ic.relam
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	LEFT JOIN pg_stat_user_indexes s ON s.indexrelid = i.indexrelid
+	WHERE n.nspname = $1 AND t.relname = $2
+	ORDER BY ic.relname`
+
 // ListIndexes returns a list of indexes for the specified table.
 func (c *PostgreSQLClientImpl) ListIndexes(ctx context.Context, schema, table string) ([]*IndexInfo, error) {
-	db := c.db.Load()
+	db := c.sqlDB()
 	if db == nil {
 		return nil, ErrNoDatabaseConnection
 	}
@@ -593,43 +1042,41 @@ func (c *PostgreSQLClientImpl) ListIndexes(ctx context.Context, schema, table st
 		schema = DefaultSchema
 	}
 
-	query := `
-		SELECT
-			i.relname as index_name,
-			t.relname as table_name,
-			array_agg(a.attname ORDER BY array_position(ix.indkey, a.attnum)) as columns,
-			ix.indisunique as is_unique,
-			ix.indisprimary as is_primary,
-			am.amname as index_type
-		FROM pg_class t
-		JOIN pg_index ix ON t.oid = ix.indrelid
-		JOIN pg_class i ON i.oid = ix.indexrelid
-		JOIN pg_am am ON i.relam = am.oid
-		JOIN pg_namespace n ON t.relnamespace = n.oid
-		JOIN pg_attribute a ON a.attrelid = t.oid
-		WHERE n.nspname = $1 AND t.relname = $2 AND a.attnum = ANY(ix.indkey)
-		GROUP BY i.relname, t.relname, ix.indisunique, ix.indisprimary, am.amname
-		ORDER BY i.relname`
-
-	rows, err := db.QueryContext(ctx, query, schema, table)
+	rows, err := db.QueryContext(ctx, listIndexesQuery, schema, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list indexes: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
+	typeMap := pgtype.NewMap()
 	var indexes []*IndexInfo
 	for rows.Next() {
 		var index IndexInfo
-		var columns pq.StringArray
 		if err := rows.Scan(
-			&index.Name, &index.Table, &columns,
-			&index.IsUnique, &index.IsPrimary, &index.IndexType,
+			&index.Name,
+			&index.Table,
+			typeMap.SQLScanner(&index.Columns),
+			typeMap.SQLScanner(&index.IncludeColumns),
+			typeMap.SQLScanner(&index.Expressions),
+			&index.Predicate,
+			&index.IsUnique,
+			&index.IsPrimary,
+			&index.IsValid,
+			&index.IsReady,
+			&index.IndexType,
+			&index.Size,
+			&index.Scans,
+			&index.TuplesRead,
+			&index.Definition,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan index row: %w", err)
 		}
-
-		// Convert pq.StringArray to []string
-		index.Columns = []string(columns)
+		if len(index.IncludeColumns) == 0 {
+			index.IncludeColumns = nil
+		}
+		if len(index.Expressions) == 0 {
+			index.Expressions = nil
+		}
 
 		indexes = append(indexes, &index)
 	}
@@ -642,12 +1089,18 @@ func (c *PostgreSQLClientImpl) ListIndexes(ctx context.Context, schema, table st
 
 // copySingleQuotedLiteral copies a single-quoted string literal from runes[start]
 // (which must be a single quote) into result, returning the new index after the closing quote.
-// Handles escaped quotes ('').
+// Handles quotes escaped by doubling them and, in E'...' escape strings, by a backslash.
 func copySingleQuotedLiteral(runes []rune, start int, result *strings.Builder) int {
+	escapes := isEscapeStringStart(runes, start)
 	result.WriteRune(runes[start])
 	i := start + 1
 	for i < len(runes) {
 		result.WriteRune(runes[i])
+		if escapes && runes[i] == '\\' && i+1 < len(runes) {
+			result.WriteRune(runes[i+1])
+			i += 2
+			continue
+		}
 		if runes[i] == '\'' {
 			if i+1 < len(runes) && runes[i+1] == '\'' {
 				result.WriteRune(runes[i+1])
@@ -661,6 +1114,15 @@ func copySingleQuotedLiteral(runes []rune, start int, result *strings.Builder) i
 	return i
 }
 
+// isEscapeStringStart reports whether the quote at runes[start] opens an
+// E'...' escape string: it follows an E that does not end a longer word.
+func isEscapeStringStart(runes []rune, start int) bool {
+	if start == 0 || (runes[start-1] != 'E' && runes[start-1] != 'e') {
+		return false
+	}
+	return start == 1 || !isIdentRune(runes[start-2], false)
+}
+
 // copyDoubleQuotedIdentifier copies a double-quoted identifier from runes[start]
 // (which must be a double quote) into result, returning the new index after the closing quote.
 func copyDoubleQuotedIdentifier(runes []rune, start int, result *strings.Builder) int {
@@ -798,13 +1260,14 @@ func validateQuery(query string) error {
 	return nil
 }
 
-// processRows processes query result rows and handles type conversion.
+// processRows drains query result rows, converting each value with the
+// decoder for its column (see decode.go). Values are first decoded by pgx;
+// arrays are re-read as pgtype.Array so multi-dimensional arrays keep their
+// shape, and json/jsonb are taken verbatim so documents are not re-encoded.
 // maxRows limits the number of rows returned to prevent memory exhaustion.
-func processRows(rows *sql.Rows, maxRows int) ([][]any, error) {
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get columns: %w", err)
-	}
+func processRows(rows pgx.Rows, types []*ColumnType, maxRows int) ([][]any, error) {
+	fields := rows.FieldDescriptions()
+	typeMap := rows.Conn().TypeMap()
 
 	var result [][]any
 	for rows.Next() {
@@ -812,65 +1275,243 @@ func processRows(rows *sql.Rows, maxRows int) ([][]any, error) {
 			return nil, fmt.Errorf("result set exceeded %d rows: %w", maxRows, ErrResultTooLarge)
 		}
 
-		values := make([]any, len(columns))
-		valuePtrs := make([]any, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
+		values, err := rows.Values()
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		// Convert []byte to string for easier JSON serialization
-		for i, v := range values {
-			if b, ok := v.([]byte); ok {
-				values[i] = string(b)
+		raw := rows.RawValues()
+		for i, fd := range fields {
+			if raw[i] == nil {
+				continue
+			}
+			switch types[i].Encoding {
+			case EncodingArray:
+				if _, known := typeMap.TypeForOID(fd.DataTypeOID); known {
+					var arr pgtype.Array[any]
+					if err := typeMap.Scan(fd.DataTypeOID, fd.Format, raw[i], &arr); err != nil {
+						return nil, fmt.Errorf("failed to scan row: %w", err)
+					}
+					values[i] = arr
+				}
+			case EncodingJSON:
+				values[i] = rawJSON(raw[i], fd)
 			}
 		}
-
 		result = append(result, values)
 	}
 	return result, nil
 }
 
-// ExecuteQuery executes a SELECT query and returns the results.
-func (c *PostgreSQLClientImpl) ExecuteQuery(ctx context.Context, query string, args ...any) (*QueryResult, error) {
-	if err := validateQuery(query); err != nil {
-		return nil, err
+// rawJSON returns the document text of a json/jsonb value, stripping the
+// version byte that prefixes jsonb in binary format.
+func rawJSON(raw []byte, fd pgconn.FieldDescription) string {
+	if fd.DataTypeOID == pgtype.JSONBOID && fd.Format == pgtype.BinaryFormatCode && len(raw) > 0 {
+		raw = raw[1:]
 	}
+	return string(raw)
+}
 
-	db := c.db.Load()
-	if db == nil {
-		return nil, ErrNoDatabaseConnection
+// newColumnType converts a result field description into a ColumnType.
+// Length, Precision, and Scale are decoded from the type modifier and left
+// unset when the column is unconstrained (typmod -1).
+func newColumnType(fd pgconn.FieldDescription, typeName string) *ColumnType {
+	ct := &ColumnType{
+		Name:     fd.Name,
+		Type:     typeName,
+		OID:      fd.DataTypeOID,
+		Encoding: encodingFor(typeName),
+	}
+	mod := int64(fd.TypeModifier)
+	switch typeName {
+	case "varchar", "bpchar":
+		if mod >= varHeaderSize {
+			length := mod - varHeaderSize
+			ct.Length = &length
+		}
+	case "bit", "varbit":
+		if mod > 0 {
+			ct.Length = &mod
+		}
+	case "numeric":
+		if mod >= varHeaderSize {
+			precision := ((mod - varHeaderSize) >> 16) & 0xffff //nolint:mnd // typmod layout
+			scale := (mod - varHeaderSize) & 0xffff             //nolint:mnd // typmod layout
+			if precision > 0 && precision <= maxNumericPrecision {
+				ct.Precision = &precision
+				ct.Scale = &scale
+			}
+		}
+	}
+	return ct
+}
+
+// columnSource identifies the table column a result column was taken from,
+// as reported in its field description.
+type columnSource struct {
+	table  uint32
+	attnum uint16
+}
+
+// columnMetadataQuery resolves, in one round trip, the names of the types
+// $1 (rows with attnum 0) and whether the table columns given as parallel
+// arrays $2 and $3 are NOT NULL.
+const columnMetadataQuery = `
+	SELECT oid, 0, typname, false FROM pg_catalog.pg_type WHERE oid = ANY($1)
+	UNION ALL
+	SELECT a.attrelid, a.attnum, '', a.attnotnull
+	FROM unnest($2::oid[], $3::int2[]) AS f(relid, attnum)
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = f.relid AND a.attnum = f.attnum`
+
+// columnMetadata resolves the pg_type.typname of every result column and
+// whether the columns taken straight from a table are declared NOT NULL.
+// Built-in types are answered from the pgx type map; user-defined types
+// (enums, domains, composites, and arrays of them) and table columns are
+// looked up in the catalog, which costs one extra round trip only when such
+// columns exist.
+func columnMetadata(ctx context.Context, pool *pgxpool.Pool, typeMap *pgtype.Map,
+	fields []pgconn.FieldDescription,
+) (map[uint32]string, map[columnSource]bool, error) {
+	names := make(map[uint32]string, len(fields))
+	notNull := make(map[columnSource]bool)
+	var unknown, tables []uint32
+	var attnums []int16
+	for _, fd := range fields {
+		if fd.TableOID != 0 && fd.TableAttributeNumber > 0 && fd.TableAttributeNumber <= math.MaxInt16 {
+			tables = append(tables, fd.TableOID)
+			attnums = append(attnums, int16(fd.TableAttributeNumber))
+		}
+		if _, seen := names[fd.DataTypeOID]; seen {
+			continue
+		}
+		if t, ok := typeMap.TypeForOID(fd.DataTypeOID); ok {
+			names[fd.DataTypeOID] = t.Name
+			continue
+		}
+		names[fd.DataTypeOID] = ""
+		unknown = append(unknown, fd.DataTypeOID)
+	}
+	if len(unknown) == 0 && len(tables) == 0 {
+		return names, notNull, nil
 	}
 
-	rows, err := db.QueryContext(ctx, query, args...)
+	rows, err := pool.Query(ctx, columnMetadataQuery, unknown, tables, attnums)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, nil, fmt.Errorf("failed to resolve column types: %w", err)
 	}
-	defer func() { _ = rows.Close() }()
+	defer rows.Close()
+	for rows.Next() {
+		var objectOID uint32
+		var attnum int16
+		var name string
+		var attNotNull bool
+		if err := rows.Scan(&objectOID, &attnum, &name, &attNotNull); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan type row: %w", err)
+		}
+		if attnum == 0 {
+			names[objectOID] = name
+			continue
+		}
+		notNull[columnSource{table: objectOID, attnum: uint16(attnum)}] = attNotNull
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate type rows: %w", err)
+	}
+	return names, notNull, nil
+}
 
-	columns, err := rows.Columns()
+// setNullable marks as nullable the result columns taken straight from a
+// table column that allows NULL. A NOT NULL source column proves nothing,
+// since the nullable side of an outer join still yields NULL for it, so
+// those columns are left unset.
+func setNullable(types []*ColumnType, fields []pgconn.FieldDescription, notNull map[columnSource]bool) {
+	for i, fd := range fields {
+		attNotNull, ok := notNull[columnSource{table: fd.TableOID, attnum: fd.TableAttributeNumber}]
+		if !ok || attNotNull {
+			continue
+		}
+		nullable := true
+		types[i].Nullable = &nullable
+	}
+}
+
+// runQuery executes query on the pool and drains the result into a
+// QueryResult with per-column type metadata. Rows are decoded only after the
+// result has been fully read, so the catalog lookup for user-defined types
+// never needs a second pool connection while the first is still busy.
+func runQuery(ctx context.Context, pool *pgxpool.Pool, query string, args ...any) (*QueryResult, error) {
+	rows, err := pool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get columns: %w", err)
+		return nil, err //nolint:wrapcheck // wrapped by the caller with its own context
+	}
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	typeMap := rows.Conn().TypeMap()
+	columns := make([]string, len(fields))
+	types := make([]*ColumnType, len(fields))
+	for i, fd := range fields {
+		columns[i] = fd.Name
+		name := ""
+		if t, ok := typeMap.TypeForOID(fd.DataTypeOID); ok {
+			name = t.Name
+		}
+		types[i] = newColumnType(fd, name)
 	}
 
-	result, err := processRows(rows, maxResultRows())
+	result, err := processRows(rows, types, maxResultRows())
 	if err != nil {
 		return nil, err
 	}
-
+	rows.Close()
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate query rows: %w", err)
+		return nil, err //nolint:wrapcheck // wrapped by the caller with its own context
 	}
+
+	names, notNull, err := columnMetadata(ctx, pool, typeMap, fields)
+	if err != nil {
+		return nil, err
+	}
+	decoders := make([]valueDecoder, len(fields))
+	for i, fd := range fields {
+		if types[i].Type == "" {
+			types[i] = newColumnType(fd, names[fd.DataTypeOID])
+		}
+		decoders[i] = decoderFor(types[i].Type)
+	}
+	setNullable(types, fields, notNull)
+	for _, row := range result {
+		decodeRow(row, decoders)
+	}
+
 	return &QueryResult{
-		Columns:  columns,
-		Rows:     result,
-		RowCount: len(result),
+		Columns:     columns,
+		ColumnTypes: types,
+		Rows:        result,
+		RowCount:    len(result),
 	}, nil
 }
 
+// ExecuteQuery executes a SELECT query and returns the results. The query
+// runs on the pgx pool; when ctx is done pgx sends a cancel request so
+// PostgreSQL stops the query instead of running it to completion.
+func (c *PostgreSQLClientImpl) ExecuteQuery(ctx context.Context, query string, args ...any) (*QueryResult, error) {
+	if err := validateQuery(query); err != nil {
+		return nil, err
+	}
+
+	pool := c.pgxPool()
+	if pool == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	result, err := runQuery(ctx, pool, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return result, nil
+}
+
 // ExplainQuery returns the execution plan for a query. When analyze is false
 // the plan is non-executing — EXPLAIN (FORMAT JSON) — which is the safe
 // default for an LLM-driven tool surface that may submit heavy queries
@@ -882,8 +1523,8 @@ func (c *PostgreSQLClientImpl) ExplainQuery(ctx context.Context, query string, a
 		return nil, err
 	}
 
-	db := c.db.Load()
-	if db == nil {
+	pool := c.pgxPool()
+	if pool == nil {
 		return nil, ErrNoDatabaseConnection
 	}
 
@@ -893,30 +1534,20 @@ func (c *PostgreSQLClientImpl) ExplainQuery(ctx context.Context, query string, a
 	}
 	explainQuery := prefix + query //nolint:gosec // query is validated by validateQuery above (SELECT/WITH only)
 
-	rows, err := db.QueryContext(ctx, explainQuery, args...)
+	result, err := runQuery(ctx, pool, explainQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute explain query: %w", err)
 	}
-	defer func() { _ = rows.Close() }()
-
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get columns: %w", err)
-	}
-
-	result, err := processRows(rows, maxResultRows())
-	if err != nil {
-		return nil, err
-	}
+	return result, nil
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate query rows: %w", err)
-	}
-	return &QueryResult{
-		Columns:  columns,
-		Rows:     result,
-		RowCount: len(result),
-	}, nil
+// countableTypes are the relation types whose rows refineZeroRowCounts may
+// count; views are skipped because counting them runs their query, and
+// foreign tables because it reaches out to the remote server.
+var countableTypes = map[string]bool{
+	TableTypeTable:            true,
+	TableTypePartitionedTable: true,
+	TableTypeMaterializedView: true,
 }
 
 // refineZeroRowCounts issues a bounded number of COUNT(*) probes — each with a
@@ -930,15 +1561,13 @@ func (c *PostgreSQLClientImpl) refineZeroRowCounts(ctx context.Context, db *sql.
 		if probed >= maxCountFallbackTables {
 			return
 		}
-		if table.RowCount != 0 || table.Type != "table" {
+		if table.RowCount != 0 || !countableTypes[table.Type] {
 			continue
 		}
 
-		// pq.QuoteIdentifier escapes both schema and table to defend against
-		// SQL injection via malicious identifiers.
-		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s",
-			pq.QuoteIdentifier(table.Schema),
-			pq.QuoteIdentifier(table.Name))
+		// pgx.Identifier.Sanitize quotes both schema and table to defend
+		// against SQL injection via malicious identifiers.
+		countQuery := "SELECT COUNT(*) FROM " + pgx.Identifier{table.Schema, table.Name}.Sanitize()
 
 		countCtx, cancel := context.WithTimeout(ctx, countFallbackTimeout)
 		var actualCount int64
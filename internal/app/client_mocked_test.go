@@ -88,7 +88,7 @@ func TestPostgreSQLClient_ErrorScenarios(t *testing.T) {
 	})
 
 	t.Run("ListTables", func(t *testing.T) {
-		_, err := client.ListTables(context.Background(), "public")
+		_, err := client.ListTables(context.Background(), &ListTablesOptions{Schema: "public"})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "no database connection")
 	})
@@ -111,6 +111,12 @@ func TestPostgreSQLClient_ErrorScenarios(t *testing.T) {
 		assert.Contains(t, err.Error(), "no database connection")
 	})
 
+	t.Run("ListConstraints", func(t *testing.T) {
+		_, err := client.ListConstraints(context.Background(), "public", "users")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no database connection")
+	})
+
 	t.Run("ExecuteQuery", func(t *testing.T) {
 		_, err := client.ExecuteQuery(context.Background(), "SELECT 1")
 		assert.Error(t, err)
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -102,7 +103,7 @@ func TestPostgreSQLClient_ListSchemasWithoutConnection(t *testing.T) {
 
 func TestPostgreSQLClient_ListTablesWithoutConnection(t *testing.T) {
 	client := NewPostgreSQLClient()
-	tables, err := client.ListTables(context.Background(), "public")
+	tables, err := client.ListTables(context.Background(), &ListTablesOptions{Schema: "public"})
 	assert.Error(t, err)
 	assert.Nil(t, tables)
 	assert.Contains(t, err.Error(), "no database connection")
@@ -110,7 +111,7 @@ func TestPostgreSQLClient_ListTablesWithoutConnection(t *testing.T) {
 
 func TestPostgreSQLClient_ListTablesWithEmptySchema(t *testing.T) {
 	client := NewPostgreSQLClient()
-	tables, err := client.ListTables(context.Background(), "")
+	tables, err := client.ListTables(context.Background(), &ListTablesOptions{})
 	assert.Error(t, err)
 	assert.Nil(t, tables)
 	assert.Contains(t, err.Error(), "no database connection")
@@ -164,6 +165,23 @@ func TestPostgreSQLClient_ListIndexesWithEmptySchema(t *testing.T) {
 	assert.Contains(t, err.Error(), "no database connection")
 }
 
+func TestPostgreSQLClient_ListConstraintsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	constraints, err := client.ListConstraints(context.Background(), "public", "users")
+	assert.Error(t, err)
+	assert.Nil(t, constraints)
+	assert.Contains(t, err.Error(), "no database connection")
+}
+
+func TestIsInsufficientPrivilege(t *testing.T) {
+	denied := &pgconn.PgError{Code: "42501", Message: "permission denied for table pg_attribute"}
+	assert.True(t, isInsufficientPrivilege(denied))
+	assert.True(t, isInsufficientPrivilege(fmt.Errorf("failed to describe table: %w", denied)))
+	assert.False(t, isInsufficientPrivilege(&pgconn.PgError{Code: "42P01"}))
+	assert.False(t, isInsufficientPrivilege(errors.New("42501")))
+	assert.False(t, isInsufficientPrivilege(nil))
+}
+
 func TestPostgreSQLClient_ExecuteQueryWithoutConnection(t *testing.T) {
 	client := NewPostgreSQLClient()
 	result, err := client.ExecuteQuery(context.Background(), "SELECT 1")
@@ -386,7 +404,7 @@ func TestDefaultSchemaHandling(t *testing.T) {
 		t.Run(fmt.Sprintf("schema_%s", tt.inputSchema), func(t *testing.T) {
 			// These will fail due to no connection, but we can verify
 			// that the schema parameter is properly processed
-			_, err := client.ListTables(context.Background(), tt.inputSchema)
+			_, err := client.ListTables(context.Background(), &ListTablesOptions{Schema: tt.inputSchema})
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "no database connection")
 
@@ -741,7 +759,7 @@ func TestInjectStatementTimeout(t *testing.T) {
 	t.Run("composes with read-only injection in a single options payload", func(t *testing.T) {
 		// Issue #89: Connect chains both injections. Assert that the resulting
 		// DSN carries BOTH options inside a single options= value rather than
-		// producing a malformed second options= key (which lib/pq would
+		// producing a malformed second options= key (which the driver would
 		// silently drop the earlier one of).
 		in := "host=localhost dbname=mydb"
 		stacked := injectStatementTimeout(injectReadOnlyOption(in), 30*time.Second)
@@ -857,3 +875,90 @@ func TestMaxResultRows(t *testing.T) {
 	defer os.Unsetenv("POSTGRES_MCP_MAX_RESULT_ROWS")
 	assert.Equal(t, 500, maxResultRows())
 }
+
+func TestRelationFilterArgs(t *testing.T) {
+	assert.Equal(t, []any{DefaultSchema, []string{}, "", ""}, relationFilterArgs(nil))
+	assert.Equal(t, []any{"sales", []string{"r", "m"}, `order\_%`, "invoice"}, relationFilterArgs(&ListTablesOptions{
+		Schema:  "sales",
+		Types:   []string{TableTypeTable, TableTypeMaterializedView},
+		Pattern: `order\_%`,
+		Search:  "invoice",
+	}))
+}
+
+func TestSetNullable(t *testing.T) {
+	fields := []pgconn.FieldDescription{
+		{Name: "id", TableOID: 16384, TableAttributeNumber: 1},
+		{Name: "email", TableOID: 16384, TableAttributeNumber: 2},
+		{Name: "total"},
+	}
+	types := []*ColumnType{{Name: "id"}, {Name: "email"}, {Name: "total"}}
+
+	setNullable(types, fields, map[columnSource]bool{
+		{table: 16384, attnum: 1}: true,
+		{table: 16384, attnum: 2}: false,
+	})
+	assert.Nil(t, types[0].Nullable, "a NOT NULL column can still be NULL after an outer join")
+	require.NotNil(t, types[1].Nullable)
+	assert.True(t, *types[1].Nullable)
+	assert.Nil(t, types[2].Nullable, "computed columns have no source column")
+}
+
+func TestNewColumnType(t *testing.T) {
+	t.Run("builtin type", func(t *testing.T) {
+		ct := newColumnType(pgconn.FieldDescription{Name: "id", DataTypeOID: 23, TypeModifier: -1}, "int4")
+		assert.Equal(t, "id", ct.Name)
+		assert.Equal(t, "int4", ct.Type)
+		assert.Equal(t, uint32(23), ct.OID)
+		assert.Nil(t, ct.Nullable)
+		assert.Nil(t, ct.Length)
+		assert.Nil(t, ct.Precision)
+		assert.Empty(t, ct.Encoding)
+	})
+
+	t.Run("array type", func(t *testing.T) {
+		ct := newColumnType(pgconn.FieldDescription{Name: "tags", DataTypeOID: 1009, TypeModifier: -1}, "_text")
+		assert.Equal(t, "_text", ct.Type)
+		assert.Equal(t, uint32(1009), ct.OID)
+		assert.Equal(t, EncodingArray, ct.Encoding)
+	})
+
+	t.Run("varchar length", func(t *testing.T) {
+		ct := newColumnType(pgconn.FieldDescription{Name: "email", DataTypeOID: 1043, TypeModifier: 259}, "varchar")
+		require.NotNil(t, ct.Length)
+		assert.Equal(t, int64(255), *ct.Length)
+	})
+
+	t.Run("unconstrained lengths are dropped", func(t *testing.T) {
+		ct := newColumnType(pgconn.FieldDescription{Name: "v", DataTypeOID: 1043, TypeModifier: -1}, "varchar")
+		assert.Nil(t, ct.Length, "varchar without typmod must not report a length")
+		ct = newColumnType(pgconn.FieldDescription{Name: "body", DataTypeOID: 25, TypeModifier: -1}, "text")
+		assert.Nil(t, ct.Length)
+	})
+
+	t.Run("numeric precision and scale", func(t *testing.T) {
+		// numeric(10,0): ((10 << 16) | 0) + 4
+		ct := newColumnType(pgconn.FieldDescription{Name: "price", DataTypeOID: 1700, TypeModifier: 10<<16 + 4}, "numeric")
+		require.NotNil(t, ct.Precision)
+		require.NotNil(t, ct.Scale)
+		assert.Equal(t, int64(10), *ct.Precision)
+		assert.Equal(t, int64(0), *ct.Scale, "scale 0 is meaningful and must be kept")
+		assert.Equal(t, EncodingDecimal, ct.Encoding)
+
+		ct = newColumnType(pgconn.FieldDescription{Name: "p", DataTypeOID: 1700, TypeModifier: 6<<16 | 2 + 4}, "numeric")
+		assert.Equal(t, int64(6), *ct.Precision)
+		assert.Equal(t, int64(2), *ct.Scale)
+	})
+
+	t.Run("unconstrained numeric", func(t *testing.T) {
+		ct := newColumnType(pgconn.FieldDescription{Name: "n", DataTypeOID: 1700, TypeModifier: -1}, "numeric")
+		assert.Nil(t, ct.Precision)
+		assert.Nil(t, ct.Scale)
+	})
+
+	t.Run("unresolved type keeps its OID", func(t *testing.T) {
+		ct := newColumnType(pgconn.FieldDescription{Name: "status", DataTypeOID: 16384, TypeModifier: -1}, "")
+		assert.Equal(t, "", ct.Type)
+		assert.Equal(t, uint32(16384), ct.OID)
+	})
+}
@@ -0,0 +1,1078 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Object types reported in DDLObject.Type.
+const (
+	DDLObjectSchema            = "schema"
+	DDLObjectType              = "type"
+	DDLObjectFunction          = "function"
+	DDLObjectSequence          = "sequence"
+	DDLObjectTable             = "table"
+	DDLObjectView              = "view"
+	DDLObjectMaterializedView  = "materialized_view"
+	DDLObjectConstraint        = "constraint"
+	DDLObjectIndex             = "index"
+	DDLObjectForeignKey        = "foreign_key"
+	DDLObjectTrigger           = "trigger"
+	DDLObjectSequenceOwnership = "sequence_ownership"
+)
+
+// DDLScript is the DDL of a schema, or of a single table when Table is set.
+// Objects are in an order that can be replayed into an empty database:
+// every object comes after the objects it depends on, and otherwise in the
+// order pg_dump uses (types, functions, sequences, tables, views,
+// constraints, indexes, foreign keys, triggers).
+type DDLScript struct {
+	Schema  string       `json:"schema"`
+	Table   string       `json:"table,omitempty"`
+	Objects []*DDLObject `json:"objects"`
+}
+
+// DDLObject holds the statements that create one object, followed by those
+// setting its owner, comments, and privileges. Name is schema-qualified and
+// quoted as needed; constraints and triggers read "name ON schema.table".
+type DDLObject struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	SQL  string `json:"sql"`
+}
+
+// Script joins the objects into a single SQL script. Function bodies are
+// not validated on replay, as with pg_dump, since they may reference
+// objects created later in the script.
+func (s *DDLScript) Script() string {
+	var b strings.Builder
+	b.WriteString("SET check_function_bodies = false;\n")
+	for _, object := range s.Objects {
+		fmt.Fprintf(&b, "\n-- %s: %s\n%s\n", object.Type, object.Name, object.SQL)
+	}
+	return b.String()
+}
+
+// ResultSections renders one row per object for the non-JSON result
+// formats.
+func (s *DDLScript) ResultSections() []ResultSection {
+	return []ResultSection{{Name: "objects", Records: s.Objects}}
+}
+
+// Replay phases, in pg_dump order. An object is emitted in its phase unless
+// a dependency forces it later (e.g. a function returning a table's row
+// type comes after the table).
+const (
+	ddlPhaseSchema = iota
+	ddlPhaseType
+	ddlPhaseFunction
+	ddlPhaseSequence
+	ddlPhaseTable
+	ddlPhaseView
+	ddlPhaseConstraint
+	ddlPhaseIndex
+	ddlPhaseForeignKey
+	ddlPhaseTrigger
+	ddlPhaseSequenceOwnership
+)
+
+// catalogKey identifies a catalog row, by catalog name and oid, as
+// pg_depend does.
+type catalogKey struct {
+	class string
+	oid   int64
+}
+
+// ddlItem is an object being assembled, with the items it depends on.
+type ddlItem struct {
+	object     *DDLObject
+	phase      int
+	statements []string
+	deps       map[*ddlItem]bool
+}
+
+// ddlBuilder collects the objects of a schema and the catalog rows each of
+// them accounts for, so that pg_depend entries (which reference column
+// defaults, rewrite rules, row types, and so on) can be resolved to the
+// object whose DDL creates them.
+type ddlBuilder struct {
+	items []*ddlItem
+	byKey map[catalogKey]*ddlItem
+}
+
+func newDDLBuilder() *ddlBuilder {
+	return &ddlBuilder{byKey: make(map[catalogKey]*ddlItem)}
+}
+
+// add registers an object created by statements and owning key.
+func (b *ddlBuilder) add(phase int, objectType, name string, key catalogKey, statements ...string) *ddlItem {
+	item := &ddlItem{
+		object:     &DDLObject{Type: objectType, Name: name},
+		phase:      phase,
+		statements: statements,
+		deps:       make(map[*ddlItem]bool),
+	}
+	b.items = append(b.items, item)
+	b.byKey[key] = item
+	return item
+}
+
+// alias records that the catalog rows in oids, of catalog class, are
+// created together with item.
+func (b *ddlBuilder) alias(item *ddlItem, class string, oids ...int64) {
+	for _, oid := range oids {
+		if oid != 0 {
+			b.byKey[catalogKey{class, oid}] = item
+		}
+	}
+}
+
+// lookup returns the item that creates a catalog row, or nil when the row
+// is outside the script.
+func (b *ddlBuilder) lookup(class string, oid int64) *ddlItem {
+	return b.byKey[catalogKey{class, oid}]
+}
+
+// depend records that the object creating from must follow the one
+// creating to. Dependencies on rows outside the script are ignored.
+func (b *ddlBuilder) depend(from, to catalogKey) {
+	fromItem, toItem := b.byKey[from], b.byKey[to]
+	if fromItem == nil || toItem == nil || fromItem == toItem {
+		return
+	}
+	fromItem.deps[toItem] = true
+}
+
+// oids returns the oids of the catalog rows the objects account for.
+func (b *ddlBuilder) oids() []uint32 {
+	oids := make([]uint32, 0, len(b.byKey))
+	for key := range b.byKey {
+		oids = append(oids, uint32(key.oid))
+	}
+	slices.Sort(oids)
+	return slices.Compact(oids)
+}
+
+// sorted returns the objects in replay order: repeatedly the first object,
+// by phase and then insertion order, whose dependencies have all been
+// emitted. Should the dependencies form a cycle, the first remaining object
+// is emitted regardless.
+func (b *ddlBuilder) sorted() []*DDLObject {
+	remaining := slices.Clone(b.items)
+	slices.SortStableFunc(remaining, func(x, y *ddlItem) int { return x.phase - y.phase })
+
+	emitted := make(map[*ddlItem]bool, len(remaining))
+	objects := make([]*DDLObject, 0, len(remaining))
+	for len(remaining) > 0 {
+		next := 0
+		for i, item := range remaining {
+			ready := true
+			for dep := range item.deps {
+				if !emitted[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+
+		item := remaining[next]
+		item.object.SQL = strings.Join(item.statements, "\n")
+		emitted[item] = true
+		objects = append(objects, item.object)
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+	return objects
+}
+
+// ddlSchemaQuery reads the schema itself. Names, roles, and comments come
+// back quoted for direct use in statements.
+const ddlSchemaQuery = `
+	SELECT
+		n.oid::bigint,
+		format('%I', n.nspname),
+		quote_ident(pg_get_userbyid(n.nspowner)),
+		COALESCE(quote_literal(obj_description(n.oid, 'pg_namespace')), '')
+	FROM pg_namespace n
+	WHERE n.nspname = $1`
+
+// ddlTypesQuery renders the CREATE statement of each enum, domain,
+// stand-alone composite type, and range type of a schema, skipping members
+// of extensions. The array type, the composite's pg_class row, and the
+// domain's constraints are returned so dependencies on them resolve to the
+// type.
+const ddlTypesQuery = `
+	SELECT
+		t.oid::bigint,
+		CASE WHEN t.typtype = 'd' THEN 'DOMAIN' ELSE 'TYPE' END,
+		format('%I.%I', n.nspname, t.typname),
+		CASE t.typtype
+			WHEN 'e' THEN format('CREATE TYPE %I.%I AS ENUM (%s);', n.nspname, t.typname,
+				(SELECT string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder)
+				FROM pg_enum e WHERE e.enumtypid = t.oid))
+			WHEN 'd' THEN format('CREATE DOMAIN %I.%I AS %s', n.nspname, t.typname, format_type(t.typbasetype, t.typtypmod))
+				|| CASE WHEN t.typcollation <> bt.typcollation THEN ' COLLATE ' || t.typcollation::regcollation::text ELSE '' END
+				|| CASE WHEN t.typdefault IS NOT NULL THEN ' DEFAULT ' || t.typdefault ELSE '' END
+				|| CASE WHEN t.typnotnull THEN ' NOT NULL' ELSE '' END
+				|| COALESCE((
+					SELECT string_agg(format(E'\n\tCONSTRAINT %I %s', c.conname, pg_get_constraintdef(c.oid)), '' ORDER BY c.conname)
+					FROM pg_constraint c WHERE c.contypid = t.oid AND c.contype = 'c'
+				), '')
+				|| ';'
+			WHEN 'c' THEN format(E'CREATE TYPE %I.%I AS (\n\t%s\n);', n.nspname, t.typname,
+				(SELECT string_agg(format('%I %s', a.attname, format_type(a.atttypid, a.atttypmod)), E',\n\t' ORDER BY a.attnum)
+				FROM pg_attribute a WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped))
+			ELSE format('CREATE TYPE %I.%I AS RANGE (subtype = %s);', n.nspname, t.typname,
+				(SELECT format_type(r.rngsubtype, NULL) FROM pg_range r WHERE r.rngtypid = t.oid))
+		END,
+		quote_ident(pg_get_userbyid(t.typowner)),
+		COALESCE(quote_literal(obj_description(t.oid, 'pg_type')), ''),
+		t.typarray::bigint,
+		t.typrelid::bigint,
+		ARRAY(SELECT c.oid::bigint FROM pg_constraint c WHERE c.contypid = t.oid)
+	FROM pg_type t
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	LEFT JOIN pg_type bt ON bt.oid = t.typbasetype
+	LEFT JOIN pg_class rel ON rel.oid = t.typrelid
+	WHERE n.nspname = $1
+		AND t.typtype IN ('e', 'd', 'c', 'r')
+		AND (t.typtype <> 'c' OR rel.relkind = 'c')
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_type'::regclass AND d.objid = t.oid AND d.deptype = 'e'
+		)
+	ORDER BY t.typname`
+
+// ddlFunctionsQuery reads the functions and procedures of a schema, skipping
+// aggregates (which pg_get_functiondef cannot render) and members of
+// extensions.
+const ddlFunctionsQuery = `
+	SELECT
+		p.oid::bigint,
+		CASE WHEN p.prokind = 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+		format('%I.%I(%s)', n.nspname, p.proname, pg_get_function_identity_arguments(p.oid)),
+		pg_get_functiondef(p.oid),
+		quote_ident(pg_get_userbyid(p.proowner)),
+		COALESCE(quote_literal(obj_description(p.oid, 'pg_proc')), '')
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE n.nspname = $1
+		AND p.prokind <> 'a'
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+		)
+	ORDER BY p.proname, p.oid`
+
+// ddlSequencesQuery reads the sequences of a schema, or those owned by one
+// of its tables, with the column they are owned by. Identity sequences are
+// created by their column and skipped.
+const ddlSequencesQuery = `
+	SELECT
+		c.oid::bigint,
+		format('%I.%I', n.nspname, c.relname),
+		format_type(s.seqtypid, NULL),
+		s.seqstart,
+		s.seqincrement,
+		s.seqmin,
+		s.seqmax,
+		s.seqcache,
+		s.seqcycle,
+		quote_ident(pg_get_userbyid(c.relowner)),
+		COALESCE(quote_literal(obj_description(c.oid, 'pg_class')), ''),
+		COALESCE(tc.oid::bigint, 0),
+		CASE WHEN a.attname IS NULL THEN '' ELSE format('%I.%I.%I', tn.nspname, tc.relname, a.attname) END
+	FROM pg_sequence s
+	JOIN pg_class c ON c.oid = s.seqrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_depend d
+		ON d.classid = 'pg_class'::regclass
+		AND d.objid = c.oid
+		AND d.refclassid = 'pg_class'::regclass
+		AND d.refobjsubid > 0
+		AND d.deptype = 'a'
+	LEFT JOIN pg_class tc ON tc.oid = d.refobjid
+	LEFT JOIN pg_namespace tn ON tn.oid = tc.relnamespace
+	LEFT JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+	WHERE n.nspname = $1
+		AND ($2 = '' OR (tc.relname = $2 AND tc.relnamespace = n.oid))
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend i
+			WHERE i.classid = 'pg_class'::regclass AND i.objid = c.oid AND i.deptype IN ('i', 'e')
+		)
+	ORDER BY c.relname`
+
+// ddlRelationsQuery reads the tables, views, and materialized views of a
+// schema, or one of them. Column definitions are rendered in full; columns
+// inherited from a parent or partitioned table are left to it. The last
+// four columns list the catalog rows created along with the relation:
+// column defaults, rewrite rules, row types, and internal relations
+// (identity sequences, TOAST tables).
+const ddlRelationsQuery = `
+	SELECT
+		c.oid::bigint,
+		c.relkind::text,
+		format('%I.%I', n.nspname, c.relname),
+		c.relpersistence = 'u',
+		ARRAY(
+			SELECT format('%I %s', a.attname, format_type(a.atttypid, a.atttypmod))
+				|| CASE WHEN a.attcollation <> t.typcollation THEN ' COLLATE ' || a.attcollation::regcollation::text ELSE '' END
+				|| CASE
+					WHEN a.attgenerated = 's' THEN format(' GENERATED ALWAYS AS (%s) STORED', pg_get_expr(ad.adbin, ad.adrelid))
+					WHEN a.attgenerated = 'v' THEN format(' GENERATED ALWAYS AS (%s) VIRTUAL', pg_get_expr(ad.adbin, ad.adrelid))
+					WHEN ad.adbin IS NOT NULL THEN ' DEFAULT ' || pg_get_expr(ad.adbin, ad.adrelid)
+					ELSE ''
+				END
+				|| CASE a.attidentity
+					WHEN 'a' THEN ' GENERATED ALWAYS AS IDENTITY'
+					WHEN 'd' THEN ' GENERATED BY DEFAULT AS IDENTITY'
+					ELSE ''
+				END
+				|| CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+			FROM pg_attribute a
+			JOIN pg_type t ON t.oid = a.atttypid
+			LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+			WHERE c.relkind IN ('r', 'p') AND a.attrelid = c.oid AND a.attnum > 0
+				AND NOT a.attisdropped AND a.attislocal
+			ORDER BY a.attnum
+		),
+		CASE WHEN c.relispartition THEN (
+			SELECT format('%I.%I', pn.nspname, pc.relname)
+			FROM pg_inherits i
+			JOIN pg_class pc ON pc.oid = i.inhparent
+			JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+			WHERE i.inhrelid = c.oid
+		) ELSE '' END,
+		CASE WHEN c.relispartition THEN pg_get_expr(c.relpartbound, c.oid) ELSE '' END,
+		CASE WHEN c.relispartition THEN '' ELSE COALESCE((
+			SELECT string_agg(format('%I.%I', pn.nspname, pc.relname), ', ' ORDER BY i.inhseqno)
+			FROM pg_inherits i
+			JOIN pg_class pc ON pc.oid = i.inhparent
+			JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+			WHERE i.inhrelid = c.oid
+		), '') END,
+		CASE WHEN c.relkind = 'p' THEN pg_get_partkeydef(c.oid) ELSE '' END,
+		COALESCE(array_to_string(c.reloptions, ', '), ''),
+		CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) ELSE '' END,
+		quote_ident(pg_get_userbyid(c.relowner)),
+		COALESCE(quote_literal(obj_description(c.oid, 'pg_class')), ''),
+		ARRAY(
+			SELECT format('COMMENT ON COLUMN %I.%I.%I IS %L;', n.nspname, c.relname, a.attname, d.description)
+			FROM pg_description d
+			JOIN pg_attribute a ON a.attrelid = d.objoid AND a.attnum = d.objsubid
+			WHERE d.classoid = 'pg_class'::regclass AND d.objoid = c.oid AND d.objsubid > 0
+			ORDER BY a.attnum
+		),
+		ARRAY(SELECT ad.oid::bigint FROM pg_attrdef ad WHERE ad.adrelid = c.oid),
+		ARRAY(SELECT r.oid::bigint FROM pg_rewrite r WHERE r.ev_class = c.oid),
+		ARRAY[c.reltype::bigint, COALESCE((SELECT t.typarray::bigint FROM pg_type t WHERE t.oid = c.reltype), 0)],
+		ARRAY(
+			SELECT d.objid::bigint FROM pg_depend d
+			WHERE d.classid = 'pg_class'::regclass AND d.refclassid = 'pg_class'::regclass
+				AND d.refobjid = c.oid AND d.deptype = 'i'
+		)
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2)
+		AND c.relkind IN ('r', 'p', 'v', 'm')
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+		)
+	ORDER BY c.relname`
+
+// ddlConstraintsQuery reads the table constraints of a schema, or of one
+// table, that are declared on the table itself; constraints inherited from
+// a parent or cloned into partitions are created by their parent. CHECK
+// constraints of ordinary tables are written inline in CREATE TABLE.
+const ddlConstraintsQuery = `
+	SELECT
+		con.oid::bigint,
+		con.conrelid::bigint,
+		con.contype::text,
+		con.conindid::bigint,
+		format('%I', con.conname),
+		format('%I.%I', n.nspname, c.relname),
+		pg_get_constraintdef(con.oid),
+		con.contype = 'c' AND NOT c.relispartition,
+		COALESCE(quote_literal(obj_description(con.oid, 'pg_constraint')), '')
+	FROM pg_constraint con
+	JOIN pg_class c ON c.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2)
+		AND con.contype IN ('c', 'p', 'u', 'x', 'f')
+		AND con.conislocal AND con.conparentid = 0
+	ORDER BY c.relname, con.conname`
+
+// ddlIndexesQuery reads the indexes of a schema, or of one table, that are
+// neither created by a constraint nor attached to an index of the
+// partitioned parent.
+const ddlIndexesQuery = `
+	SELECT
+		ic.oid::bigint,
+		i.indrelid::bigint,
+		format('%I.%I', n.nspname, ic.relname),
+		` + indexDefinition + `,
+		COALESCE(quote_literal(obj_description(ic.oid, 'pg_class')), '')
+	FROM pg_index i
+	JOIN pg_class ic ON ic.oid = i.indexrelid
+	JOIN pg_class c ON c.oid = i.indrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2)
+		AND NOT ic.relispartition
+		AND ` + indexNotFromConstraint + `
+	ORDER BY ic.relname`
+
+// ddlTriggersQuery reads the user triggers of a schema, or of one table,
+// skipping those cloned into partitions from a trigger on the parent.
+const ddlTriggersQuery = `
+	SELECT
+		t.oid::bigint,
+		t.tgrelid::bigint,
+		format('%I', t.tgname),
+		format('%I.%I', n.nspname, c.relname),
+		pg_get_triggerdef(t.oid, true),
+		t.tgenabled::text,
+		COALESCE(quote_literal(obj_description(t.oid, 'pg_trigger')), '')
+	FROM pg_trigger t
+	JOIN pg_class c ON c.oid = t.tgrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2)
+		AND NOT t.tgisinternal AND t.tgparentid = 0
+	ORDER BY c.relname, t.tgname`
+
+// ddlGrantsQuery renders the GRANT and REVOKE statements that turn the
+// default privileges of each object in a schema into its actual ACL, in the
+// manner of pg_dump: privileges in the default ACL but not the actual one
+// are revoked, and those only in the actual ACL are granted. Column
+// privileges have an empty default. For a table's script ($2 not empty) only
+// the relations of the script, whose oids are $3, are read.
+const ddlGrantsQuery = `
+	WITH objects AS (
+		SELECT 'pg_class' AS class, c.oid, CASE WHEN c.relkind = 'S' THEN 'SEQUENCE' ELSE 'TABLE' END AS kind,
+			format('%I.%I', n.nspname, c.relname) AS name, '' AS column_name,
+			c.relacl AS acl, acldefault(CASE WHEN c.relkind = 'S' THEN 's' ELSE 'r' END::"char", c.relowner) AS defaults
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'S') AND c.relacl IS NOT NULL
+			AND ($2 = '' OR c.oid = ANY($3::oid[]))
+		UNION ALL
+		SELECT 'pg_class', c.oid, 'TABLE', format('%I.%I', n.nspname, c.relname), format(' (%I)', a.attname),
+			a.attacl, '{}'::aclitem[]
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm') AND a.attnum > 0
+			AND NOT a.attisdropped AND a.attacl IS NOT NULL
+			AND ($2 = '' OR c.oid = ANY($3::oid[]))
+		UNION ALL
+		SELECT 'pg_proc', p.oid, CASE WHEN p.prokind = 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+			format('%I.%I(%s)', n.nspname, p.proname, pg_get_function_identity_arguments(p.oid)), '',
+			p.proacl, acldefault('f', p.proowner)
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = $1 AND p.proacl IS NOT NULL AND $2 = ''
+		UNION ALL
+		SELECT 'pg_type', t.oid, CASE WHEN t.typtype = 'd' THEN 'DOMAIN' ELSE 'TYPE' END,
+			format('%I.%I', n.nspname, t.typname), '', t.typacl, acldefault('T', t.typowner)
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = $1 AND t.typacl IS NOT NULL AND $2 = ''
+		UNION ALL
+		SELECT 'pg_namespace', n.oid, 'SCHEMA', format('%I', n.nspname), '', n.nspacl, acldefault('n', n.nspowner)
+		FROM pg_namespace n
+		WHERE n.nspname = $1 AND n.nspacl IS NOT NULL AND $2 = ''
+	),
+	changes AS (
+		SELECT o.class, o.oid, o.kind, o.name, o.column_name, true AS revoke, e.grantee, e.privilege_type, e.is_grantable
+		FROM objects o, aclexplode(o.defaults) e
+		WHERE (e.grantee, e.privilege_type, e.is_grantable) NOT IN (
+			SELECT x.grantee, x.privilege_type, x.is_grantable FROM aclexplode(o.acl) x
+		)
+		UNION ALL
+		SELECT o.class, o.oid, o.kind, o.name, o.column_name, false, e.grantee, e.privilege_type, e.is_grantable
+		FROM objects o, aclexplode(o.acl) e
+		WHERE (e.grantee, e.privilege_type, e.is_grantable) NOT IN (
+			SELECT x.grantee, x.privilege_type, x.is_grantable FROM aclexplode(o.defaults) x
+		)
+	),
+	grouped AS (
+		SELECT class, oid, kind, name, column_name, revoke, is_grantable,
+			CASE WHEN grantee = 0 THEN 'PUBLIC' ELSE quote_ident(pg_get_userbyid(grantee)) END AS grantee,
+			string_agg(privilege_type || column_name, ', ' ORDER BY privilege_type) AS privileges
+		FROM changes
+		GROUP BY class, oid, kind, name, column_name, revoke, is_grantable, grantee
+	)
+	SELECT
+		class,
+		oid::bigint,
+		CASE WHEN revoke THEN format('REVOKE %s ON %s %s FROM %s;', privileges, kind, name, grantee)
+			ELSE format('GRANT %s ON %s %s TO %s%s;', privileges, kind, name, grantee,
+				CASE WHEN is_grantable THEN ' WITH GRANT OPTION' ELSE '' END)
+		END
+	FROM grouped
+	ORDER BY class, oid, revoke DESC, column_name, grantee`
+
+// ddlDependenciesQuery reads the normal and automatic dependencies of the
+// catalog rows whose oids are $1, the objects of the script, in either
+// direction; the builder keeps those between objects of the script.
+const ddlDependenciesQuery = `
+	SELECT d.classid::regclass::text, d.objid::bigint, d.refclassid::regclass::text, d.refobjid::bigint
+	FROM pg_depend d
+	WHERE d.deptype IN ('n', 'a') AND (d.objid = ANY($1::oid[]) OR d.refobjid = ANY($1::oid[]))`
+
+// ownerStatement and commentStatement render the ALTER ... OWNER TO and
+// COMMENT ON statements of an object; comment is an already quoted literal
+// and yields no statement when empty.
+func ownerStatement(keyword, name, owner string) string {
+	return fmt.Sprintf("ALTER %s %s OWNER TO %s;", keyword, name, owner)
+}
+
+func commentStatement(keyword, name, comment string) []string {
+	if comment == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("COMMENT ON %s %s IS %s;", keyword, name, comment)}
+}
+
+// ddlRelation holds the parts of a table's CREATE statement until its
+// inline CHECK constraints are known.
+type ddlRelation struct {
+	item        *ddlItem
+	kind        string
+	name        string
+	unlogged    bool
+	elements    []string
+	partitionOf string
+	bound       string
+	inherits    string
+	partitionBy string
+	options     string
+	definition  string
+	trailer     []string
+}
+
+// createStatement renders the CREATE TABLE, VIEW, or MATERIALIZED VIEW
+// statement of the relation.
+func (r *ddlRelation) createStatement() string {
+	var b strings.Builder
+	switch r.kind {
+	case "v", "m":
+		if r.kind == "m" {
+			b.WriteString("CREATE MATERIALIZED VIEW " + r.name)
+		} else {
+			b.WriteString("CREATE VIEW " + r.name)
+		}
+		if r.options != "" {
+			b.WriteString(" WITH (" + r.options + ")")
+		}
+		b.WriteString(" AS\n" + strings.TrimSuffix(strings.TrimSpace(r.definition), ";"))
+		if r.kind == "m" {
+			b.WriteString("\nWITH NO DATA")
+		}
+		b.WriteString(";")
+		return b.String()
+	}
+
+	b.WriteString("CREATE ")
+	if r.unlogged {
+		b.WriteString("UNLOGGED ")
+	}
+	b.WriteString("TABLE " + r.name)
+	if r.partitionOf != "" {
+		b.WriteString(" PARTITION OF " + r.partitionOf)
+		if len(r.elements) > 0 {
+			b.WriteString(" (\n\t" + strings.Join(r.elements, ",\n\t") + "\n)")
+		}
+		b.WriteString("\n" + r.bound)
+	} else {
+		b.WriteString(" (\n\t" + strings.Join(r.elements, ",\n\t") + "\n)")
+	}
+	if r.inherits != "" {
+		b.WriteString("\nINHERITS (" + r.inherits + ")")
+	}
+	if r.partitionBy != "" {
+		b.WriteString("\nPARTITION BY " + r.partitionBy)
+	}
+	if r.options != "" {
+		b.WriteString("\nWITH (" + r.options + ")")
+	}
+	b.WriteString(";")
+	return b.String()
+}
+
+// ddlRelationKinds maps pg_class.relkind to the DDLObject type and the
+// keyword used in ALTER and COMMENT statements.
+var ddlRelationKinds = map[string]struct{ objectType, keyword string }{
+	"r": {DDLObjectTable, "TABLE"},
+	"p": {DDLObjectTable, "TABLE"},
+	"v": {DDLObjectView, "VIEW"},
+	"m": {DDLObjectMaterializedView, "MATERIALIZED VIEW"},
+}
+
+// triggerEnableStatements maps pg_trigger.tgenabled to the statement that
+// restores a non-default firing mode.
+var triggerEnableStatements = map[string]string{
+	"D": "DISABLE TRIGGER",
+	"R": "ENABLE REPLICA TRIGGER",
+	"A": "ENABLE ALWAYS TRIGGER",
+}
+
+// GetDDL reconstructs the DDL of a schema, or of one table, view, or
+// materialized view when table is not empty. A schema's script covers the
+// schema itself, its types, functions, sequences, relations, constraints,
+// indexes, and triggers, with their owners, comments, and privileges; a
+// table's covers the table, its constraints, indexes, triggers, and owned
+// sequences.
+func (c *PostgreSQLClientImpl) GetDDL(ctx context.Context, schema, table string) (*DDLScript, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	b := newDDLBuilder()
+	if table == "" {
+		if err := b.loadSchema(ctx, db, schema); err != nil {
+			return nil, err
+		}
+		if err := b.loadTypes(ctx, db, schema); err != nil {
+			return nil, err
+		}
+		if err := b.loadFunctions(ctx, db, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	relations, err := b.loadRelations(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if table != "" && len(relations) == 0 {
+		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+	}
+
+	ownerships, err := b.loadSequences(ctx, db, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.loadConstraints(ctx, db, schema, table, relations); err != nil {
+		return nil, err
+	}
+	for _, relation := range relations {
+		relation.item.statements = append([]string{relation.createStatement()}, relation.trailer...)
+	}
+	if err := b.loadIndexes(ctx, db, schema, table); err != nil {
+		return nil, err
+	}
+	if err := b.loadTriggers(ctx, db, schema, table); err != nil {
+		return nil, err
+	}
+	for _, ownership := range ownerships {
+		ownership()
+	}
+	if err := b.loadGrants(ctx, db, schema, table); err != nil {
+		return nil, err
+	}
+	if err := b.loadDependencies(ctx, db); err != nil {
+		return nil, err
+	}
+
+	return &DDLScript{Schema: schema, Table: table, Objects: b.sorted()}, nil
+}
+
+// loadSchema adds the CREATE SCHEMA statement. The public schema exists in
+// every database, so only its owner and comment are set.
+func (b *ddlBuilder) loadSchema(ctx context.Context, db *sql.DB, schema string) error {
+	var oid int64
+	var name, owner, comment string
+	err := db.QueryRowContext(ctx, ddlSchemaQuery, schema).Scan(&oid, &name, &owner, &comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("schema %s: %w", schema, ErrSchemaNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schema DDL: %w", err)
+	}
+
+	var statements []string
+	if schema != DefaultSchema {
+		statements = append(statements, fmt.Sprintf("CREATE SCHEMA %s;", name))
+	}
+	statements = append(statements, ownerStatement("SCHEMA", name, owner))
+	statements = append(statements, commentStatement("SCHEMA", name, comment)...)
+	b.add(ddlPhaseSchema, DDLObjectSchema, name, catalogKey{"pg_namespace", oid}, statements...)
+	return nil
+}
+
+func (b *ddlBuilder) loadTypes(ctx context.Context, db *sql.DB, schema string) error {
+	rows, err := db.QueryContext(ctx, ddlTypesQuery, schema)
+	if err != nil {
+		return fmt.Errorf("failed to get type DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	for rows.Next() {
+		var oid, arrayType, relation int64
+		var keyword, name, create, owner, comment string
+		var constraints []int64
+		if err := rows.Scan(
+			&oid, &keyword, &name, &create, &owner, &comment, &arrayType, &relation, typeMap.SQLScanner(&constraints),
+		); err != nil {
+			return fmt.Errorf("failed to scan type DDL row: %w", err)
+		}
+
+		statements := append([]string{create, ownerStatement(keyword, name, owner)}, commentStatement(keyword, name, comment)...)
+		item := b.add(ddlPhaseType, DDLObjectType, name, catalogKey{"pg_type", oid}, statements...)
+		b.alias(item, "pg_type", arrayType)
+		b.alias(item, "pg_class", relation)
+		b.alias(item, "pg_constraint", constraints...)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate type DDL rows: %w", err)
+	}
+	return nil
+}
+
+func (b *ddlBuilder) loadFunctions(ctx context.Context, db *sql.DB, schema string) error {
+	rows, err := db.QueryContext(ctx, ddlFunctionsQuery, schema)
+	if err != nil {
+		return fmt.Errorf("failed to get function DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var oid int64
+		var keyword, name, definition, owner, comment string
+		if err := rows.Scan(&oid, &keyword, &name, &definition, &owner, &comment); err != nil {
+			return fmt.Errorf("failed to scan function DDL row: %w", err)
+		}
+
+		statements := append(
+			[]string{strings.TrimRight(definition, "\n") + ";", ownerStatement(keyword, name, owner)},
+			commentStatement(keyword, name, comment)...,
+		)
+		b.add(ddlPhaseFunction, DDLObjectFunction, name, catalogKey{"pg_proc", oid}, statements...)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate function DDL rows: %w", err)
+	}
+	return nil
+}
+
+// loadSequences adds the sequences and returns, for those owned by a
+// column, a function adding the ALTER SEQUENCE ... OWNED BY statement once
+// the tables are known. It comes last, as in pg_dump, since the table's
+// column default in turn depends on the sequence.
+func (b *ddlBuilder) loadSequences(ctx context.Context, db *sql.DB, schema, table string) ([]func(), error) {
+	rows, err := db.QueryContext(ctx, ddlSequencesQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sequence DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ownerships []func()
+	for rows.Next() {
+		var oid, start, increment, minValue, maxValue, cache, ownerTable int64
+		var name, dataType, owner, comment, ownedBy string
+		var cycle bool
+		if err := rows.Scan(
+			&oid, &name, &dataType, &start, &increment, &minValue, &maxValue, &cache, &cycle,
+			&owner, &comment, &ownerTable, &ownedBy,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sequence DDL row: %w", err)
+		}
+
+		create := fmt.Sprintf(
+			"CREATE SEQUENCE %s\n\tAS %s\n\tSTART WITH %d\n\tINCREMENT BY %d\n\tMINVALUE %d\n\tMAXVALUE %d\n\tCACHE %d",
+			name, dataType, start, increment, minValue, maxValue, cache,
+		)
+		if cycle {
+			create += "\n\tCYCLE"
+		}
+		statements := append([]string{create + ";", ownerStatement("SEQUENCE", name, owner)}, commentStatement("SEQUENCE", name, comment)...)
+		sequence := b.add(ddlPhaseSequence, DDLObjectSequence, name, catalogKey{"pg_class", oid}, statements...)
+
+		if ownedBy != "" {
+			ownerships = append(ownerships, func() {
+				item := b.add(ddlPhaseSequenceOwnership, DDLObjectSequenceOwnership, name, catalogKey{"sequence_ownership", oid},
+					fmt.Sprintf("ALTER SEQUENCE %s OWNED BY %s;", name, ownedBy))
+				item.deps[sequence] = true
+				if tableItem := b.lookup("pg_class", ownerTable); tableItem != nil {
+					item.deps[tableItem] = true
+				}
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sequence DDL rows: %w", err)
+	}
+	return ownerships, nil
+}
+
+// loadRelations adds the tables, views, and materialized views, keyed by
+// oid. Their statements are set once the inline constraints are known.
+func (b *ddlBuilder) loadRelations(ctx context.Context, db *sql.DB, schema, table string) (map[int64]*ddlRelation, error) {
+	rows, err := db.QueryContext(ctx, ddlRelationsQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	relations := make(map[int64]*ddlRelation)
+	for rows.Next() {
+		var oid int64
+		var relation ddlRelation
+		var owner, comment string
+		var columnComments []string
+		var defaults, rules, rowTypes, internal []int64
+		if err := rows.Scan(
+			&oid,
+			&relation.kind,
+			&relation.name,
+			&relation.unlogged,
+			typeMap.SQLScanner(&relation.elements),
+			&relation.partitionOf,
+			&relation.bound,
+			&relation.inherits,
+			&relation.partitionBy,
+			&relation.options,
+			&relation.definition,
+			&owner,
+			&comment,
+			typeMap.SQLScanner(&columnComments),
+			typeMap.SQLScanner(&defaults),
+			typeMap.SQLScanner(&rules),
+			typeMap.SQLScanner(&rowTypes),
+			typeMap.SQLScanner(&internal),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan table DDL row: %w", err)
+		}
+
+		kind := ddlRelationKinds[relation.kind]
+		relation.trailer = append([]string{ownerStatement(kind.keyword, relation.name, owner)}, commentStatement(kind.keyword, relation.name, comment)...)
+		relation.trailer = append(relation.trailer, columnComments...)
+
+		phase := ddlPhaseTable
+		if kind.objectType != DDLObjectTable {
+			phase = ddlPhaseView
+		}
+		relation.item = b.add(phase, kind.objectType, relation.name, catalogKey{"pg_class", oid})
+		b.alias(relation.item, "pg_attrdef", defaults...)
+		b.alias(relation.item, "pg_rewrite", rules...)
+		b.alias(relation.item, "pg_type", rowTypes...)
+		b.alias(relation.item, "pg_class", internal...)
+		relations[oid] = &relation
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate table DDL rows: %w", err)
+	}
+	return relations, nil
+}
+
+// loadConstraints adds the primary key, unique, exclusion, and foreign key
+// constraints of the relations, and the CHECK constraints of partitions, as
+// ALTER TABLE statements; other CHECK constraints are appended to their
+// table's column list.
+func (b *ddlBuilder) loadConstraints(ctx context.Context, db *sql.DB, schema, table string, relations map[int64]*ddlRelation) error {
+	rows, err := db.QueryContext(ctx, ddlConstraintsQuery, schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to get constraint DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var oid, relid, index int64
+		var contype, name, tableName, definition, comment string
+		var inline bool
+		if err := rows.Scan(&oid, &relid, &contype, &index, &name, &tableName, &definition, &inline, &comment); err != nil {
+			return fmt.Errorf("failed to scan constraint DDL row: %w", err)
+		}
+
+		relation := relations[relid]
+		if relation == nil {
+			continue
+		}
+		target := name + " ON " + tableName
+		if inline {
+			relation.elements = append(relation.elements, fmt.Sprintf("CONSTRAINT %s %s", name, definition))
+			relation.trailer = append(relation.trailer, commentStatement("CONSTRAINT", target, comment)...)
+			b.alias(relation.item, "pg_constraint", oid)
+			continue
+		}
+
+		phase, objectType := ddlPhaseConstraint, DDLObjectConstraint
+		if contype == "f" {
+			phase, objectType = ddlPhaseForeignKey, DDLObjectForeignKey
+		}
+		statements := append(
+			[]string{fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", tableName, name, definition)},
+			commentStatement("CONSTRAINT", target, comment)...,
+		)
+		item := b.add(phase, objectType, target, catalogKey{"pg_constraint", oid}, statements...)
+		item.deps[relation.item] = true
+		if contype != "f" {
+			b.alias(item, "pg_class", index)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate constraint DDL rows: %w", err)
+	}
+	return nil
+}
+
+func (b *ddlBuilder) loadIndexes(ctx context.Context, db *sql.DB, schema, table string) error {
+	rows, err := db.QueryContext(ctx, ddlIndexesQuery, schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to get index DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var oid, relid int64
+		var name, definition, comment string
+		if err := rows.Scan(&oid, &relid, &name, &definition, &comment); err != nil {
+			return fmt.Errorf("failed to scan index DDL row: %w", err)
+		}
+
+		relation := b.lookup("pg_class", relid)
+		if relation == nil {
+			continue
+		}
+		statements := append([]string{definition + ";"}, commentStatement("INDEX", name, comment)...)
+		item := b.add(ddlPhaseIndex, DDLObjectIndex, name, catalogKey{"pg_class", oid}, statements...)
+		item.deps[relation] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate index DDL rows: %w", err)
+	}
+	return nil
+}
+
+func (b *ddlBuilder) loadTriggers(ctx context.Context, db *sql.DB, schema, table string) error {
+	rows, err := db.QueryContext(ctx, ddlTriggersQuery, schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to get trigger DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var oid, relid int64
+		var name, tableName, definition, enabled, comment string
+		if err := rows.Scan(&oid, &relid, &name, &tableName, &definition, &enabled, &comment); err != nil {
+			return fmt.Errorf("failed to scan trigger DDL row: %w", err)
+		}
+
+		relation := b.lookup("pg_class", relid)
+		if relation == nil {
+			continue
+		}
+		target := name + " ON " + tableName
+		statements := []string{definition + ";"}
+		if action, ok := triggerEnableStatements[enabled]; ok {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s %s %s;", tableName, action, name))
+		}
+		statements = append(statements, commentStatement("TRIGGER", target, comment)...)
+		item := b.add(ddlPhaseTrigger, DDLObjectTrigger, target, catalogKey{"pg_trigger", oid}, statements...)
+		item.deps[relation] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate trigger DDL rows: %w", err)
+	}
+	return nil
+}
+
+// loadGrants appends the privilege statements to the objects they apply to.
+func (b *ddlBuilder) loadGrants(ctx context.Context, db *sql.DB, schema, table string) error {
+	rows, err := db.QueryContext(ctx, ddlGrantsQuery, schema, table, b.oids())
+	if err != nil {
+		return fmt.Errorf("failed to get privilege DDL: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var class, statement string
+		var oid int64
+		if err := rows.Scan(&class, &oid, &statement); err != nil {
+			return fmt.Errorf("failed to scan privilege DDL row: %w", err)
+		}
+		if item := b.lookup(class, oid); item != nil {
+			item.statements = append(item.statements, statement)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate privilege DDL rows: %w", err)
+	}
+	return nil
+}
+
+// loadDependencies records the pg_depend edges between objects of the
+// script. A sequence's automatic dependency on the column owning it is left
+// out: OWNED BY is set separately, after the table.
+func (b *ddlBuilder) loadDependencies(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, ddlDependenciesQuery, b.oids())
+	if err != nil {
+		return fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var from, to catalogKey
+		if err := rows.Scan(&from.class, &from.oid, &to.class, &to.oid); err != nil {
+			return fmt.Errorf("failed to scan dependency row: %w", err)
+		}
+		if item := b.byKey[from]; item != nil && item.object.Type == DDLObjectSequence {
+			continue
+		}
+		b.depend(from, to)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate dependency rows: %w", err)
+	}
+	return nil
+}
+
+// GetDDL returns the DDL of a schema, or of one table when table is not
+// empty, in replay order.
+func (a *App) GetDDL(ctx context.Context, schema, table string) (*DDLScript, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get DDL: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Getting DDL", "schema", schema, "table", table)
+
+	script, err := a.client.GetDDL(ctx, schema, table)
+	if err != nil {
+		a.logger.Error("Failed to get DDL", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to get DDL: %w", err)
+	}
+
+	a.logger.Debug("Successfully generated DDL", "object_count", len(script.Objects), "schema", schema, "table", table)
+	return script, nil
+}
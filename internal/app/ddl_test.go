@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_GetDDLWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	script, err := client.GetDDL(context.Background(), "public", "")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, script)
+}
+
+func TestDDLBuilder_SortedFollowsDependencies(t *testing.T) {
+	b := newDDLBuilder()
+	b.add(ddlPhaseTable, DDLObjectTable, "public.users", catalogKey{"pg_class", 10}, "CREATE TABLE public.users ();")
+	b.add(ddlPhaseFunction, DDLObjectFunction, "public.active_users()", catalogKey{"pg_proc", 20}, "CREATE FUNCTION public.active_users();")
+	b.add(ddlPhaseType, DDLObjectType, "public.mood", catalogKey{"pg_type", 30}, "CREATE TYPE public.mood;")
+	item := b.add(ddlPhaseSchema, DDLObjectSchema, "app", catalogKey{"pg_namespace", 40}, "CREATE SCHEMA app;")
+	b.alias(b.lookup("pg_class", 10), "pg_type", 11)
+
+	// The function returns the table's row type, so it must follow the table.
+	b.depend(catalogKey{"pg_proc", 20}, catalogKey{"pg_type", 11})
+	// Dependencies on rows outside the script and on the object itself are ignored.
+	b.depend(catalogKey{"pg_class", 10}, catalogKey{"pg_class", 99})
+	b.depend(catalogKey{"pg_class", 10}, catalogKey{"pg_type", 11})
+
+	objects := b.sorted()
+	names := make([]string, len(objects))
+	for i, object := range objects {
+		names[i] = object.Name
+	}
+	assert.Equal(t, []string{"app", "public.mood", "public.users", "public.active_users()"}, names)
+	assert.Equal(t, "CREATE SCHEMA app;", item.object.SQL)
+}
+
+func TestDDLBuilder_SortedBreaksCycles(t *testing.T) {
+	b := newDDLBuilder()
+	b.add(ddlPhaseTable, DDLObjectTable, "public.a", catalogKey{"pg_class", 1}, "A")
+	b.add(ddlPhaseTable, DDLObjectTable, "public.b", catalogKey{"pg_class", 2}, "B")
+	b.depend(catalogKey{"pg_class", 1}, catalogKey{"pg_class", 2})
+	b.depend(catalogKey{"pg_class", 2}, catalogKey{"pg_class", 1})
+
+	objects := b.sorted()
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "public.a", objects[0].Name)
+	assert.Equal(t, "public.b", objects[1].Name)
+}
+
+func TestDDLBuilder_OIDs(t *testing.T) {
+	b := newDDLBuilder()
+	table := b.add(ddlPhaseTable, DDLObjectTable, "public.users", catalogKey{"pg_class", 30}, "CREATE TABLE public.users ();")
+	b.alias(table, "pg_type", 31, 0)
+	b.alias(table, "pg_attrdef", 12)
+	b.add(ddlPhaseSequenceOwnership, DDLObjectSequenceOwnership, "public.users_id_seq", catalogKey{"sequence_ownership", 20})
+	b.add(ddlPhaseSequence, DDLObjectSequence, "public.users_id_seq", catalogKey{"pg_class", 20})
+
+	assert.Equal(t, []uint32{12, 20, 30, 31}, b.oids())
+}
+
+func TestDDLRelation_CreateStatement(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation ddlRelation
+		expected string
+	}{
+		{
+			name: "table",
+			relation: ddlRelation{
+				kind:     "r",
+				name:     "public.users",
+				unlogged: true,
+				elements: []string{"id integer NOT NULL", "CONSTRAINT users_id_check CHECK ((id > 0))"},
+				inherits: "public.base",
+				options:  "fillfactor=70",
+			},
+			expected: "CREATE UNLOGGED TABLE public.users (\n\tid integer NOT NULL,\n\tCONSTRAINT users_id_check CHECK ((id > 0))\n)" +
+				"\nINHERITS (public.base)\nWITH (fillfactor=70);",
+		},
+		{
+			name:     "partitioned table",
+			relation: ddlRelation{kind: "p", name: "public.events", elements: []string{"day date NOT NULL"}, partitionBy: "RANGE (day)"},
+			expected: "CREATE TABLE public.events (\n\tday date NOT NULL\n)\nPARTITION BY RANGE (day);",
+		},
+		{
+			name: "partition",
+			relation: ddlRelation{
+				kind:        "r",
+				name:        "public.events_2024",
+				partitionOf: "public.events",
+				bound:       "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')",
+			},
+			expected: "CREATE TABLE public.events_2024 PARTITION OF public.events\nFOR VALUES FROM ('2024-01-01') TO ('2025-01-01');",
+		},
+		{
+			name:     "view",
+			relation: ddlRelation{kind: "v", name: "public.active", options: "security_barrier=true", definition: " SELECT id\n   FROM users;"},
+			expected: "CREATE VIEW public.active WITH (security_barrier=true) AS\nSELECT id\n   FROM users;",
+		},
+		{
+			name:     "materialized view",
+			relation: ddlRelation{kind: "m", name: "public.totals", definition: " SELECT count(*) AS count\n   FROM users;"},
+			expected: "CREATE MATERIALIZED VIEW public.totals AS\nSELECT count(*) AS count\n   FROM users\nWITH NO DATA;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.relation.createStatement())
+		})
+	}
+}
+
+func TestDDLScript_Script(t *testing.T) {
+	script := &DDLScript{
+		Schema: DefaultSchema,
+		Objects: []*DDLObject{
+			{Type: DDLObjectTable, Name: "public.users", SQL: "CREATE TABLE public.users (\n\tid integer\n);"},
+			{Type: DDLObjectIndex, Name: "public.users_id_idx", SQL: "CREATE INDEX users_id_idx ON public.users USING btree (id);"},
+		},
+	}
+
+	assert.Equal(t, "SET check_function_bodies = false;\n"+
+		"\n-- table: public.users\nCREATE TABLE public.users (\n\tid integer\n);\n"+
+		"\n-- index: public.users_id_idx\nCREATE INDEX users_id_idx ON public.users USING btree (id);\n",
+		script.Script())
+	assert.Equal(t, []ResultSection{{Name: "objects", Records: script.Objects}}, script.ResultSections())
+}
+
+func TestApp_GetDDL(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := &DDLScript{
+		Schema: DefaultSchema,
+		Table:  "users",
+		Objects: []*DDLObject{
+			{Type: DDLObjectTable, Name: "public.users", SQL: "CREATE TABLE public.users (\n\tid integer\n);"},
+		},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetDDL", mock.Anything, DefaultSchema, "users").Return(expected, nil)
+
+	script, err := app.GetDDL(context.Background(), "", "users")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, script)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetDDLError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetDDL", mock.Anything, "public", "missing").
+		Return(nil, fmt.Errorf("table public.missing: %w", ErrTableNotFound))
+
+	script, err := app.GetDDL(context.Background(), "public", "missing")
+	assert.ErrorIs(t, err, ErrTableNotFound)
+	assert.Contains(t, err.Error(), "failed to get DDL")
+	assert.Nil(t, script)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,770 @@
+package app
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Value encodings advertised in ColumnType.Encoding. They tell the client
+// how to interpret a JSON value whose native JSON type would otherwise be
+// ambiguous (a numeric string, a base64 blob, an embedded document, …).
+const (
+	// EncodingBase64 marks bytea values, emitted as standard base64.
+	EncodingBase64 = "base64"
+	// EncodingDecimal marks numeric values, emitted as exact decimal strings
+	// (NaN and ±Infinity are emitted verbatim).
+	EncodingDecimal = "decimal"
+	// EncodingJSON marks json/jsonb values, embedded as JSON documents.
+	EncodingJSON = "json"
+	// EncodingArray marks array values, emitted as (nested) JSON arrays whose
+	// elements follow the element type's encoding.
+	EncodingArray = "array"
+	// EncodingRange marks range values, emitted as Range objects.
+	EncodingRange = "range"
+	// EncodingInterval marks interval values, emitted as Interval objects.
+	EncodingInterval = "interval"
+	// EncodingRFC3339 marks timestamp and timestamptz values, emitted as
+	// RFC 3339 strings with a time zone offset. timestamp (without time zone)
+	// values carry no zone in PostgreSQL and are reported as UTC.
+	EncodingRFC3339 = "rfc3339"
+)
+
+// Layouts for date/time values that are not timestamps.
+const (
+	dateLayout   = "2006-01-02"
+	timeLayout   = "15:04:05.999999"
+	timetzLayout = "15:04:05.999999Z07:00"
+)
+
+// Interval is the structured form of a PostgreSQL interval. The three
+// components are kept separate, as PostgreSQL does, because months and days
+// have no fixed length in microseconds.
+type Interval struct {
+	Months       int64 `json:"months"`
+	Days         int64 `json:"days"`
+	Microseconds int64 `json:"microseconds"`
+}
+
+// Range is the structured form of a PostgreSQL range value. A nil bound is
+// unbounded; Empty is set for the empty range.
+type Range struct {
+	Lower          any  `json:"lower"`
+	Upper          any  `json:"upper"`
+	LowerInclusive bool `json:"lower_inclusive"`
+	UpperInclusive bool `json:"upper_inclusive"`
+	Empty          bool `json:"empty,omitempty"`
+}
+
+// valueDecoder converts a driver value for one column into its JSON-ready
+// form. Inputs are either the value pgx decoded from the binary protocol
+// (int32, pgtype.Numeric, pgtype.Range[any], …) or, for types pgx does not
+// know and for elements parsed out of text literals, the PostgreSQL text
+// representation. A decoder never fails: values it cannot interpret are
+// returned as text.
+type valueDecoder func(v any) any
+
+var errMalformedLiteral = errors.New("malformed literal")
+
+// rangeSubtypes maps each built-in range type to its element type.
+var rangeSubtypes = map[string]string{
+	"int4range": "int4",
+	"int8range": "int8",
+	"numrange":  "numeric",
+	"tsrange":   "timestamp",
+	"tstzrange": "timestamptz",
+	"daterange": "date",
+}
+
+// scalarDecoders maps pg_type.typname to the decoder for that type. Types
+// absent from the map — text, varchar, enums, … — are emitted as text.
+var scalarDecoders = map[string]valueDecoder{
+	"bytea":       decodeBytea,
+	"numeric":     decodeNumeric,
+	"json":        decodeJSON,
+	"jsonb":       decodeJSON,
+	"int2":        decodeInt,
+	"int4":        decodeInt,
+	"int8":        decodeInt,
+	"oid":         decodeInt,
+	"float4":      decodeFloat,
+	"float8":      decodeFloat,
+	"bool":        decodeBool,
+	"timestamp":   decodeTimestamp,
+	"timestamptz": decodeTimestamp,
+	"date":        decodeDate,
+	"time":        decodeTime,
+	"timetz":      decodeTimetz,
+	"interval":    decodeInterval,
+	"uuid":        decodeUUID,
+	"inet":        decodeInet,
+	"cidr":        decodeCIDR,
+}
+
+// scalarEncodings maps pg_type.typname to the Encoding reported for it.
+var scalarEncodings = map[string]string{
+	"bytea":       EncodingBase64,
+	"numeric":     EncodingDecimal,
+	"json":        EncodingJSON,
+	"jsonb":       EncodingJSON,
+	"timestamp":   EncodingRFC3339,
+	"timestamptz": EncodingRFC3339,
+	"interval":    EncodingInterval,
+}
+
+// encodingFor returns the Encoding for a column of the given type, or "" when
+// values are plain JSON scalars or text.
+func encodingFor(typeName string) string {
+	if elem, ok := strings.CutPrefix(typeName, "_"); ok && elem != "" {
+		return EncodingArray
+	}
+	if _, ok := rangeSubtypes[typeName]; ok {
+		return EncodingRange
+	}
+	return scalarEncodings[typeName]
+}
+
+// decoderFor returns the decoder for a column of the given type.
+func decoderFor(typeName string) valueDecoder {
+	if elem, ok := strings.CutPrefix(typeName, "_"); ok && elem != "" {
+		return arrayDecoder(decoderFor(elem))
+	}
+	if subtype, ok := rangeSubtypes[typeName]; ok {
+		return rangeDecoder(decoderFor(subtype))
+	}
+	if dec, ok := scalarDecoders[typeName]; ok {
+		return dec
+	}
+	return decodeText
+}
+
+// decodeRow applies decoders to a scanned row in place. NULLs stay nil.
+func decodeRow(values []any, decoders []valueDecoder) {
+	for i, v := range values {
+		if v == nil || i >= len(decoders) {
+			continue
+		}
+		values[i] = decoders[i](v)
+	}
+}
+
+// textOf returns the text form of a driver value when it is one.
+func textOf(v any) (string, bool) {
+	switch val := v.(type) {
+	case []byte:
+		return string(val), true
+	case string:
+		return val, true
+	}
+	return "", false
+}
+
+// decodeText emits text. Raw bytes that are not valid UTF-8 cannot be
+// represented in a JSON string and are emitted as base64 instead. Native
+// values of types without a dedicated decoder (points, bit strings, MAC
+// addresses, …) are rendered in their PostgreSQL text form.
+func decodeText(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		if utf8.Valid(val) {
+			return string(val)
+		}
+		return base64.StdEncoding.EncodeToString(val)
+	case string, bool, int16, int32, int64, float32, float64:
+		return val
+	case driver.Valuer:
+		if text, err := val.Value(); err == nil && text != nil {
+			return decodeText(text)
+		}
+	case fmt.Stringer:
+		return val.String()
+	}
+	return v
+}
+
+// decodeNumeric emits numeric as an exact decimal string.
+func decodeNumeric(v any) any {
+	if n, ok := v.(pgtype.Numeric); ok {
+		if text, err := n.Value(); err == nil && text != nil {
+			return text
+		}
+	}
+	return decodeText(v)
+}
+
+// decodeUUID formats the 16 raw bytes pgx decodes a uuid into.
+func decodeUUID(v any) any {
+	if b, ok := v.([16]byte); ok {
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}
+	return decodeText(v)
+}
+
+// decodeInet renders inet as PostgreSQL does: host addresses without a
+// prefix length.
+func decodeInet(v any) any {
+	if p, ok := v.(netip.Prefix); ok {
+		if p.Bits() == p.Addr().BitLen() {
+			return p.Addr().String()
+		}
+		return p.String()
+	}
+	return decodeText(v)
+}
+
+// decodeCIDR renders cidr, which always carries its prefix length.
+func decodeCIDR(v any) any {
+	if p, ok := v.(netip.Prefix); ok {
+		return p.String()
+	}
+	return decodeText(v)
+}
+
+// decodeBytea emits bytea as base64. pgx hands bytea values over as raw
+// bytes; elements of text-format array literals arrive in PostgreSQL's hex
+// form (\x0a0b…).
+func decodeBytea(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(val)
+	case string:
+		if h, ok := strings.CutPrefix(val, `\x`); ok {
+			if b, err := hex.DecodeString(h); err == nil {
+				return base64.StdEncoding.EncodeToString(b)
+			}
+		}
+		return base64.StdEncoding.EncodeToString([]byte(val))
+	}
+	return v
+}
+
+// decodeJSON embeds json/jsonb documents verbatim. Documents pgx has
+// already unmarshalled (array elements) are re-encoded.
+func decodeJSON(v any) any {
+	s, ok := textOf(v)
+	if !ok {
+		if b, err := json.Marshal(v); err == nil {
+			return json.RawMessage(b)
+		}
+		return v
+	}
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	return s
+}
+
+// decodeInt emits every integer width as int64.
+func decodeInt(v any) any {
+	switch val := v.(type) {
+	case int16:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case uint32:
+		return int64(val)
+	}
+	s, ok := textOf(v)
+	if !ok {
+		return v
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	return s
+}
+
+// decodeFloat emits float4/float8 as JSON numbers. NaN and ±Infinity have no
+// JSON representation and are emitted as PostgreSQL spells them.
+func decodeFloat(v any) any {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case float32:
+		f = float64(val)
+	default:
+		s, ok := textOf(v)
+		if !ok {
+			return v
+		}
+		parsed, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return s
+		}
+		f = parsed
+	}
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
+
+func decodeBool(v any) any {
+	s, ok := textOf(v)
+	if !ok {
+		return v
+	}
+	switch s {
+	case "t", "true":
+		return true
+	case "f", "false":
+		return false
+	}
+	return s
+}
+
+// timestampLayouts are the text forms PostgreSQL emits for timestamp and
+// timestamptz (DateStyle ISO); the zone offset may be "+00", "+05:30", or
+// "+05:30:12".
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func decodeTimestamp(v any) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case pgtype.InfinityModifier:
+		return val.String()
+	}
+	s, ok := textOf(v)
+	if !ok {
+		return v
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(time.RFC3339Nano)
+		}
+	}
+	// infinity, -infinity, and BC timestamps have no RFC 3339 form.
+	return s
+}
+
+func decodeDate(v any) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.Format(dateLayout)
+	case pgtype.InfinityModifier:
+		return val.String()
+	}
+	return decodeText(v)
+}
+
+func decodeTime(v any) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.Format(timeLayout)
+	case pgtype.Time:
+		// time of day may be 24:00:00, which time.Time cannot represent.
+		us := val.Microseconds
+		hms := us / int64(time.Second/time.Microsecond)
+		out := fmt.Sprintf("%02d:%02d:%02d", hms/3600, hms/60%60, hms%60) //nolint:mnd // seconds per hour/minute
+		if frac := us % int64(time.Second/time.Microsecond); frac != 0 {
+			out += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+		}
+		return out
+	}
+	return decodeText(v)
+}
+
+func decodeTimetz(v any) any {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(timetzLayout)
+	}
+	return decodeText(v)
+}
+
+func decodeInterval(v any) any {
+	if iv, ok := v.(pgtype.Interval); ok {
+		return &Interval{Months: int64(iv.Months), Days: int64(iv.Days), Microseconds: iv.Microseconds}
+	}
+	s, ok := textOf(v)
+	if !ok {
+		return v
+	}
+	iv, err := parseInterval(s)
+	if err != nil {
+		return s
+	}
+	return iv
+}
+
+// intervalUnits maps the unit words of IntervalStyle "postgres" output to
+// their contribution in (months, days).
+var intervalUnits = map[string][2]int64{
+	"year": {12, 0}, "years": {12, 0},
+	"mon": {1, 0}, "mons": {1, 0},
+	"day": {0, 1}, "days": {0, 1},
+}
+
+// parseInterval parses PostgreSQL's default (IntervalStyle "postgres") output,
+// e.g. "1 year 2 mons -3 days +04:05:06.789".
+func parseInterval(s string) (*Interval, error) {
+	iv := &Interval{}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty interval", errMalformedLiteral)
+	}
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if strings.Contains(field, ":") {
+			us, err := parseClock(field)
+			if err != nil {
+				return nil, err
+			}
+			iv.Microseconds += us
+			continue
+		}
+		n, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || i+1 >= len(fields) {
+			return nil, fmt.Errorf("%w: interval %q", errMalformedLiteral, s)
+		}
+		unit, ok := intervalUnits[fields[i+1]]
+		if !ok {
+			return nil, fmt.Errorf("%w: interval unit %q", errMalformedLiteral, fields[i+1])
+		}
+		iv.Months += n * unit[0]
+		iv.Days += n * unit[1]
+		i++
+	}
+	return iv, nil
+}
+
+// parseClock parses "[+-]HH:MM:SS[.ffffff]" into microseconds. Hours may
+// exceed 24.
+func parseClock(s string) (int64, error) {
+	sign := int64(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 { //nolint:mnd // HH:MM:SS
+		return 0, fmt.Errorf("%w: time %q", errMalformedLiteral, s)
+	}
+	h, errH := strconv.ParseInt(parts[0], 10, 64)
+	m, errM := strconv.ParseInt(parts[1], 10, 64)
+	secStr, fracStr, _ := strings.Cut(parts[2], ".")
+	sec, errS := strconv.ParseInt(secStr, 10, 64)
+	if errH != nil || errM != nil || errS != nil {
+		return 0, fmt.Errorf("%w: time %q", errMalformedLiteral, s)
+	}
+	var frac int64
+	if fracStr != "" {
+		fracStr = (fracStr + "000000")[:6]
+		f, err := strconv.ParseInt(fracStr, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: time %q", errMalformedLiteral, s)
+		}
+		frac = f
+	}
+	total := ((h*60+m)*60+sec)*int64(time.Second/time.Microsecond) + frac
+	return sign * total, nil
+}
+
+// rangeDecoder returns a decoder for a range type whose bounds are decoded
+// with elem.
+func rangeDecoder(elem valueDecoder) valueDecoder {
+	return func(v any) any {
+		if r, ok := v.(pgtype.Range[any]); ok {
+			return nativeRange(r, elem)
+		}
+		s, ok := textOf(v)
+		if !ok {
+			return v
+		}
+		r, err := parseRange(s, elem)
+		if err != nil {
+			return s
+		}
+		return r
+	}
+}
+
+// nativeRange converts a range decoded by pgx.
+func nativeRange(r pgtype.Range[any], elem valueDecoder) *Range {
+	if r.LowerType == pgtype.Empty {
+		return &Range{Empty: true}
+	}
+	out := &Range{
+		LowerInclusive: r.LowerType == pgtype.Inclusive,
+		UpperInclusive: r.UpperType == pgtype.Inclusive,
+	}
+	if r.LowerType != pgtype.Unbounded && r.Lower != nil {
+		out.Lower = elem(r.Lower)
+	}
+	if r.UpperType != pgtype.Unbounded && r.Upper != nil {
+		out.Upper = elem(r.Upper)
+	}
+	return out
+}
+
+// parseRange parses a range literal such as "[1,10)", "(,5]", or
+// `["2024-01-01 00:00:00","2024-02-01 00:00:00")`.
+func parseRange(s string, elem valueDecoder) (*Range, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "empty") {
+		return &Range{Empty: true}, nil
+	}
+	if len(s) < 3 { //nolint:mnd // shortest range literal is "(,)"
+		return nil, fmt.Errorf("%w: range %q", errMalformedLiteral, s)
+	}
+	r := &Range{
+		LowerInclusive: s[0] == '[',
+		UpperInclusive: s[len(s)-1] == ']',
+	}
+	if (s[0] != '[' && s[0] != '(') || (s[len(s)-1] != ']' && s[len(s)-1] != ')') {
+		return nil, fmt.Errorf("%w: range %q", errMalformedLiteral, s)
+	}
+	body := s[1 : len(s)-1]
+
+	lower, rest, err := scanRangeBound(body)
+	if err != nil || !strings.HasPrefix(rest, ",") {
+		return nil, fmt.Errorf("%w: range %q", errMalformedLiteral, s)
+	}
+	upper, rest, err := scanRangeBound(rest[1:])
+	if err != nil || rest != "" {
+		return nil, fmt.Errorf("%w: range %q", errMalformedLiteral, s)
+	}
+	if lower != nil {
+		r.Lower = elem(*lower)
+	}
+	if upper != nil {
+		r.Upper = elem(*upper)
+	}
+	return r, nil
+}
+
+// scanRangeBound reads one range bound (possibly double-quoted) up to the
+// next unquoted comma. A missing bound is returned as nil (unbounded).
+func scanRangeBound(s string) (*string, string, error) {
+	if s == "" || s[0] == ',' {
+		return nil, s, nil
+	}
+	var b strings.Builder
+	inQuotes := false
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			if inQuotes && i+1 < len(s) && s[i+1] == '"' {
+				b.WriteByte('"')
+				i++
+				continue
+			}
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			out := b.String()
+			return &out, s[i:], nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, "", fmt.Errorf("%w: unterminated range bound", errMalformedLiteral)
+	}
+	out := b.String()
+	return &out, "", nil
+}
+
+// arrayDecoder returns a decoder for an array type whose elements are
+// decoded with elem.
+func arrayDecoder(elem valueDecoder) valueDecoder {
+	return func(v any) any {
+		if arr, ok := v.(pgtype.Array[any]); ok {
+			return decodeArrayElements(nestArray(arr.Elements, arr.Dims), elem)
+		}
+		s, ok := textOf(v)
+		if !ok {
+			return v
+		}
+		parsed, err := parseArrayLiteral(s)
+		if err != nil {
+			return s
+		}
+		return decodeArrayElements(parsed, elem)
+	}
+}
+
+// nestArray reshapes the flat, row-major elements of a multi-dimensional
+// array into nested slices.
+func nestArray(elements []any, dims []pgtype.ArrayDimension) []any {
+	if len(dims) <= 1 {
+		if elements == nil {
+			return []any{}
+		}
+		return elements
+	}
+	outer := int(dims[0].Length)
+	if outer == 0 {
+		return []any{}
+	}
+	stride := len(elements) / outer
+	out := make([]any, outer)
+	for i := range out {
+		out[i] = nestArray(elements[i*stride:(i+1)*stride], dims[1:])
+	}
+	return out
+}
+
+// decodeArrayElements applies elem to every leaf of a nested array. Leaves
+// are either strings parsed from a text literal or native pgx values.
+func decodeArrayElements(arr []any, elem valueDecoder) []any {
+	out := make([]any, len(arr))
+	for i, e := range arr {
+		switch val := e.(type) {
+		case nil:
+			out[i] = nil
+		case []any:
+			out[i] = decodeArrayElements(val, elem)
+		default:
+			out[i] = elem(val)
+		}
+	}
+	return out
+}
+
+// parseArrayLiteral parses PostgreSQL's array output syntax into nested
+// []any whose leaves are strings (nil for NULL). It accepts the optional
+// dimension decoration ("[0:2]={…}") emitted for non-default lower bounds.
+func parseArrayLiteral(s string) ([]any, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		_, after, found := strings.Cut(s, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: array %q", errMalformedLiteral, s)
+		}
+		s = after
+	}
+	p := &arrayParser{s: s}
+	arr, err := p.parseArray()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(p.s[p.pos:]) != "" {
+		return nil, fmt.Errorf("%w: trailing data in array %q", errMalformedLiteral, s)
+	}
+	return arr, nil
+}
+
+// arrayParser is a recursive-descent parser over an array literal.
+type arrayParser struct {
+	s   string
+	pos int
+}
+
+func (p *arrayParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *arrayParser) parseArray() ([]any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '{' {
+		return nil, fmt.Errorf("%w: expected '{' in array %q", errMalformedLiteral, p.s)
+	}
+	p.pos++
+	out := []any{}
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return out, nil
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("%w: unterminated array %q", errMalformedLiteral, p.s)
+		}
+		var elem any
+		var err error
+		switch p.s[p.pos] {
+		case '{':
+			elem, err = p.parseArray()
+		case '"':
+			elem, err = p.parseQuoted()
+		default:
+			elem = p.parseUnquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, elem)
+
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("%w: unterminated array %q", errMalformedLiteral, p.s)
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return out, nil
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q in array %q", errMalformedLiteral, p.s[p.pos], p.s)
+		}
+	}
+}
+
+func (p *arrayParser) parseQuoted() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch c {
+		case '\\':
+			if p.pos+1 < len(p.s) {
+				b.WriteByte(p.s[p.pos+1])
+			}
+			p.pos += 2
+		case '"':
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("%w: unterminated quoted element in array %q", errMalformedLiteral, p.s)
+}
+
+// parseUnquoted reads an unquoted element; the bare word NULL
+// (case-insensitive) is SQL NULL and is returned as nil.
+func (p *arrayParser) parseUnquoted() any {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != ',' && p.s[p.pos] != '}' {
+		p.pos++
+	}
+	word := strings.TrimSpace(p.s[start:p.pos])
+	if strings.EqualFold(word, "NULL") {
+		return nil
+	}
+	return word
+}
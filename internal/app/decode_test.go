@@ -0,0 +1,173 @@
+package app
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodingFor(t *testing.T) {
+	tests := map[string]string{
+		"bytea":       EncodingBase64,
+		"numeric":     EncodingDecimal,
+		"jsonb":       EncodingJSON,
+		"_int4":       EncodingArray,
+		"tstzrange":   EncodingRange,
+		"interval":    EncodingInterval,
+		"timestamptz": EncodingRFC3339,
+		"int4":        "",
+		"text":        "",
+		"_":           "",
+	}
+	for typeName, want := range tests {
+		assert.Equal(t, want, encodingFor(typeName), typeName)
+	}
+}
+
+func TestDecoderFor_Scalars(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 500000000, time.FixedZone("", 2*3600))
+
+	tests := []struct {
+		typeName string
+		in       any
+		want     any
+	}{
+		{"bytea", []byte{0x00, 0xff, 0x10}, "AP8Q"},
+		{"numeric", []byte("12345678901234567890.000000001"), "12345678901234567890.000000001"},
+		{"numeric", []byte("NaN"), "NaN"},
+		{"jsonb", []byte(`{"a": [1, 2]}`), json.RawMessage(`{"a": [1, 2]}`)},
+		{"int8", int64(42), int64(42)},
+		{"float8", 1.5, 1.5},
+		{"float8", math.NaN(), "NaN"},
+		{"float4", math.Inf(1), "Infinity"},
+		{"float8", math.Inf(-1), "-Infinity"},
+		{"bool", true, true},
+		{"timestamptz", ts, "2024-03-01T12:30:00.5+02:00"},
+		{"timestamp", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), "2024-03-01T12:30:00Z"},
+		{"timestamptz", []byte("infinity"), "infinity"},
+		{"date", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "2024-03-01"},
+		{"time", time.Date(0, 1, 1, 8, 15, 30, 250000000, time.UTC), "08:15:30.25"},
+		{"uuid", []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"), "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		{"mood", []byte("happy"), "happy"},
+		{"text", []byte{0xff, 0xfe}, "//4="},
+	}
+	for _, tt := range tests {
+		got := decoderFor(tt.typeName)(tt.in)
+		assert.Equal(t, tt.want, got, "%s %v", tt.typeName, tt.in)
+	}
+}
+
+func TestDecoderFor_Interval(t *testing.T) {
+	tests := map[string]*Interval{
+		"1 year 2 mons 3 days 04:05:06.789": {Months: 14, Days: 3, Microseconds: 14706789000},
+		"-1 days +02:03:00":                 {Days: -1, Microseconds: 7380000000},
+		"-00:00:01.5":                       {Microseconds: -1500000},
+		"100:00:00":                         {Microseconds: 360000000000},
+		"00:00:00":                          {},
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, decoderFor("interval")([]byte(in)), in)
+	}
+
+	assert.Equal(t, "P1Y", decoderFor("interval")([]byte("P1Y")), "unparseable input is returned as text")
+}
+
+func TestDecoderFor_Ranges(t *testing.T) {
+	assert.Equal(t,
+		&Range{Lower: int64(1), Upper: int64(10), LowerInclusive: true},
+		decoderFor("int4range")([]byte("[1,10)")))
+
+	assert.Equal(t,
+		&Range{Upper: "5.5", UpperInclusive: true},
+		decoderFor("numrange")([]byte("(,5.5]")))
+
+	assert.Equal(t,
+		&Range{Lower: "2024-01-01T00:00:00Z", LowerInclusive: true},
+		decoderFor("tstzrange")([]byte(`["2024-01-01 00:00:00+00",)`)))
+
+	assert.Equal(t, &Range{Empty: true}, decoderFor("daterange")([]byte("empty")))
+
+	out, err := json.Marshal(decoderFor("int8range")([]byte("[3,7)")))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"lower":3,"upper":7,"lower_inclusive":true,"upper_inclusive":false}`, string(out))
+}
+
+func TestDecoderFor_Arrays(t *testing.T) {
+	tests := []struct {
+		typeName string
+		in       string
+		want     []any
+	}{
+		{"_int4", "{1,2,NULL}", []any{int64(1), int64(2), nil}},
+		{"_int4", "{{1,2},{3,4}}", []any{[]any{int64(1), int64(2)}, []any{int64(3), int64(4)}}},
+		{"_int4", "[0:1]={5,6}", []any{int64(5), int64(6)}},
+		{"_text", `{"a b","c\"d",NULL,"NULL",plain}`, []any{"a b", `c"d`, nil, "NULL", "plain"}},
+		{"_text", "{}", []any{}},
+		{"_bool", "{t,f}", []any{true, false}},
+		{"_numeric", "{1.10,NaN}", []any{"1.10", "NaN"}},
+		{"_bytea", `{"\\x0102"}`, []any{"AQI="}},
+		{"_jsonb", `{"{\"k\": 1}"}`, []any{json.RawMessage(`{"k": 1}`)}},
+		{"_timestamptz", `{"2024-01-02 03:04:05.5+05:30"}`, []any{"2024-01-02T03:04:05.5+05:30"}},
+		{"_int4range", `{"[1,3)",empty}`, []any{
+			&Range{Lower: int64(1), Upper: int64(3), LowerInclusive: true},
+			&Range{Empty: true},
+		}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, decoderFor(tt.typeName)([]byte(tt.in)), "%s %s", tt.typeName, tt.in)
+	}
+
+	assert.Equal(t, "{1,2", decoderFor("_int4")([]byte("{1,2")), "malformed input is returned as text")
+}
+
+func TestDecodeRow_LeavesNullsAlone(t *testing.T) {
+	values := []any{nil, []byte{0x01}, []byte("x")}
+	decodeRow(values, []valueDecoder{decoderFor("bytea"), decoderFor("bytea"), decoderFor("text")})
+	assert.Equal(t, []any{nil, "AQ==", "x"}, values)
+}
+
+func TestDecoderFor_NativeValues(t *testing.T) {
+	assert.Equal(t, int64(7), decoderFor("int4")(int32(7)))
+	assert.Equal(t, int64(7), decoderFor("int2")(int16(7)))
+	assert.Equal(t, "1.50", decoderFor("numeric")(pgtype.Numeric{Int: big.NewInt(150), Exp: -2, Valid: true}))
+	assert.Equal(t, "NaN", decoderFor("numeric")(pgtype.Numeric{NaN: true, Valid: true}))
+	assert.Equal(t, "infinity", decoderFor("timestamptz")(pgtype.Infinity))
+	assert.Equal(t, "-infinity", decoderFor("date")(pgtype.NegativeInfinity))
+	assert.Equal(t, "24:00:00", decoderFor("time")(pgtype.Time{Microseconds: 86400000000, Valid: true}))
+	assert.Equal(t, "08:15:30.25", decoderFor("time")(pgtype.Time{Microseconds: 29730250000, Valid: true}))
+	assert.Equal(t,
+		&Interval{Months: 14, Days: 3, Microseconds: 5},
+		decoderFor("interval")(pgtype.Interval{Months: 14, Days: 3, Microseconds: 5, Valid: true}))
+	assert.Equal(t,
+		"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		decoderFor("uuid")([16]byte{0xa0, 0xee, 0xbc, 0x99, 0x9c, 0x0b, 0x4e, 0xf8, 0xbb, 0x6d, 0x6b, 0xb9, 0xbd, 0x38, 0x0a, 0x11}))
+	assert.Equal(t, "10.0.0.1", decoderFor("inet")(netip.MustParsePrefix("10.0.0.1/32")))
+	assert.Equal(t, "10.0.0.0/8", decoderFor("inet")(netip.MustParsePrefix("10.0.0.0/8")))
+	assert.Equal(t, "10.0.0.1/32", decoderFor("cidr")(netip.MustParsePrefix("10.0.0.1/32")))
+	assert.Equal(t, json.RawMessage(`{"k":1}`), decoderFor("jsonb")(map[string]any{"k": 1}))
+
+	assert.Equal(t,
+		&Range{Lower: int64(1), LowerInclusive: true},
+		decoderFor("int4range")(pgtype.Range[any]{
+			Lower: int32(1), LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true,
+		}))
+	assert.Equal(t, &Range{Empty: true},
+		decoderFor("int4range")(pgtype.Range[any]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}))
+
+	grid := pgtype.Array[any]{
+		Elements: []any{int32(1), int32(2), int32(3), nil, int32(5), int32(6)},
+		Dims:     []pgtype.ArrayDimension{{Length: 2, LowerBound: 1}, {Length: 3, LowerBound: 1}},
+		Valid:    true,
+	}
+	assert.Equal(t,
+		[]any{[]any{int64(1), int64(2), int64(3)}, []any{nil, int64(5), int64(6)}},
+		decoderFor("_int4")(grid))
+	assert.Equal(t, []any{}, decoderFor("_int4")(pgtype.Array[any]{Valid: true}))
+}
@@ -0,0 +1,427 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// OIDs of the catalogs the walk starts from, which are fixed at bootstrap.
+const (
+	pgTypeClassID  = 1247
+	pgProcClassID  = 1255
+	pgClassClassID = 1259
+)
+
+// maxDependencyDepth caps how many levels GetDependencies follows through
+// views and functions, as a guard against unexpectedly deep chains.
+const maxDependencyDepth = 16
+
+// Object types GetDependencies reports besides the DDLObject and
+// SchemaObject ones.
+const (
+	DependencyObjectPolicy     = "policy"
+	DependencyObjectRule       = "rule"
+	DependencyObjectDefault    = "default"
+	DependencyObjectStatistics = "statistics"
+)
+
+// Dependency kinds reported in Dependency.Dependency. A normal dependent
+// makes DROP fail unless CASCADE is given, and is dropped by it; an
+// automatic one is dropped along with what it depends on. A source
+// dependent is a function whose body mentions the object by name, which
+// PostgreSQL does not track.
+const (
+	DependencyNormal    = "normal"
+	DependencyAutomatic = "automatic"
+	DependencySource    = "source"
+)
+
+// DependencyReport lists the objects that depend on a table, or on one of
+// its columns, directly or through views and functions.
+type DependencyReport struct {
+	Schema       string        `json:"schema"`
+	Table        string        `json:"table"`
+	Column       string        `json:"column,omitempty"`
+	Dependencies []*Dependency `json:"dependencies"`
+}
+
+// Dependency is an object that depends on the table or column. Table is
+// the table an index, constraint, trigger, policy, rule, default, or column
+// belongs to. Via identifies what the object depends on, as
+// pg_identify_object spells it: the table, one of its columns, or a
+// dependent found at the previous Depth.
+type Dependency struct {
+	Type       string `json:"type"`
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	Table      string `json:"table,omitempty"`
+	Dependency string `json:"dependency"`
+	Via        string `json:"via"`
+	Depth      int    `json:"depth"`
+}
+
+// ResultSections renders the dependencies as a table for the non-JSON
+// result formats.
+func (r *DependencyReport) ResultSections() []ResultSection {
+	return []ResultSection{{Name: "dependencies", Records: r.Dependencies}}
+}
+
+// dependencyObject identifies a catalog object, or a column when subID is
+// set, as pg_depend does.
+type dependencyObject struct {
+	classID uint32
+	objID   uint32
+	subID   int32
+}
+
+// dependencyTargetQuery resolves the table, its row type, and the column,
+// whose attnum is 0 when no column is given or it does not exist.
+const dependencyTargetQuery = `
+	SELECT
+		c.oid,
+		c.reltype,
+		COALESCE(a.attnum, 0),
+		(pg_identify_object('pg_class'::regclass, c.oid, COALESCE(a.attnum, 0))).identity
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attname = $3 AND a.attnum > 0 AND NOT a.attisdropped
+	WHERE n.nspname = $1 AND c.relname = $2`
+
+// dependentsQuery lists the objects depending on a set of objects, given as
+// parallel class, object, and column arrays. The _RETURN rule holding a
+// view's query stands for the view. Internal and extension dependencies
+// are left out, as are the defaults of the columns themselves.
+const dependentsQuery = `
+	WITH found AS (
+		SELECT
+			CASE WHEN rw.rulename = '_RETURN' THEN 'pg_class'::regclass::oid ELSE d.classid END AS classid,
+			CASE WHEN rw.rulename = '_RETURN' THEN rw.ev_class ELSE d.objid END AS objid,
+			CASE WHEN rw.rulename = '_RETURN' THEN 0 ELSE d.objsubid END AS objsubid,
+			d.deptype,
+			(pg_identify_object(d.refclassid, d.refobjid, d.refobjsubid)).identity AS via
+		FROM unnest($1::oid[], $2::oid[], $3::int4[]) AS r(classid, objid, objsubid)
+		JOIN pg_depend d
+			ON d.refclassid = r.classid AND d.refobjid = r.objid
+			AND (r.objsubid = 0 OR d.refobjsubid = r.objsubid)
+		LEFT JOIN pg_rewrite rw ON d.classid = 'pg_rewrite'::regclass AND rw.oid = d.objid
+		WHERE d.deptype IN ('n', 'a')
+			AND NOT (d.classid = 'pg_attrdef'::regclass AND d.deptype = 'a')
+			AND NOT COALESCE(rw.rulename = '_RETURN' AND rw.ev_class = r.objid AND r.classid = 'pg_class'::regclass, false)
+	)
+	SELECT DISTINCT ON (f.classid, f.objid, f.objsubid)
+		f.classid,
+		f.objid,
+		f.objsubid,
+		f.classid::regclass::text,
+		COALESCE(c.relkind::text, ''),
+		COALESCE(con.contype::text, ''),
+		COALESCE(p.prokind::text, ''),
+		COALESCE(cn.nspname, pn.nspname, tn.nspname, o.schema, ''),
+		COALESCE(CASE
+			WHEN c.oid IS NOT NULL AND f.objsubid > 0 THEN
+				(SELECT a.attname::text FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum = f.objsubid)
+			WHEN c.oid IS NOT NULL THEN c.relname::text
+			WHEN p.oid IS NOT NULL THEN p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')'
+			WHEN ad.oid IS NOT NULL THEN
+				(SELECT a.attname::text FROM pg_attribute a WHERE a.attrelid = ad.adrelid AND a.attnum = ad.adnum)
+			ELSE COALESCE(con.conname, tg.tgname, pol.polname, rw.rulename, st.stxname)::text
+		END, o.name, o.identity),
+		COALESCE(tc.relname, ''),
+		f.deptype::text,
+		f.via,
+		o.type
+	FROM found f
+	CROSS JOIN LATERAL pg_identify_object(f.classid, f.objid, f.objsubid) o
+	LEFT JOIN pg_class c ON f.classid = 'pg_class'::regclass AND c.oid = f.objid
+	LEFT JOIN pg_namespace cn ON cn.oid = c.relnamespace
+	LEFT JOIN pg_index i ON i.indexrelid = c.oid
+	LEFT JOIN pg_proc p ON f.classid = 'pg_proc'::regclass AND p.oid = f.objid
+	LEFT JOIN pg_namespace pn ON pn.oid = p.pronamespace
+	LEFT JOIN pg_constraint con ON f.classid = 'pg_constraint'::regclass AND con.oid = f.objid
+	LEFT JOIN pg_trigger tg ON f.classid = 'pg_trigger'::regclass AND tg.oid = f.objid
+	LEFT JOIN pg_policy pol ON f.classid = 'pg_policy'::regclass AND pol.oid = f.objid
+	LEFT JOIN pg_rewrite rw ON f.classid = 'pg_rewrite'::regclass AND rw.oid = f.objid
+	LEFT JOIN pg_attrdef ad ON f.classid = 'pg_attrdef'::regclass AND ad.oid = f.objid
+	LEFT JOIN pg_statistic_ext st ON f.classid = 'pg_statistic_ext'::regclass AND st.oid = f.objid
+	LEFT JOIN pg_class tc ON tc.oid = COALESCE(
+		i.indrelid, NULLIF(con.conrelid, 0), tg.tgrelid, pol.polrelid, rw.ev_class, ad.adrelid, st.stxrelid,
+		CASE WHEN f.objsubid > 0 THEN c.oid END)
+	LEFT JOIN pg_namespace tn ON tn.oid = tc.relnamespace
+	ORDER BY f.classid, f.objid, f.objsubid, f.deptype DESC, f.via`
+
+// dependencySourceQuery lists the functions and procedures outside the
+// system schemas and extensions whose source matches the name patterns
+// $1 and, when not empty, $2.
+const dependencySourceQuery = `
+	SELECT p.oid, n.nspname, p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')', p.prokind::text
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	JOIN pg_language l ON l.oid = p.prolang
+	WHERE l.lanname NOT IN ('c', 'internal')
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg\_toast%'
+		AND p.prosrc ~* $1 AND ($2 = '' OR p.prosrc ~* $2)
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+		)
+	ORDER BY n.nspname, 3`
+
+// dependencyKinds maps pg_depend.deptype to the Dependency kinds.
+var dependencyKinds = map[string]string{
+	"n": DependencyNormal,
+	"a": DependencyAutomatic,
+}
+
+// dependencyType names the type of a dependent from its catalog and kind
+// columns, falling back to the type pg_identify_object reports.
+func dependencyType(catalog, relkind, contype, prokind string, subID int32, identifiedType string) string {
+	switch catalog {
+	case "pg_class":
+		switch {
+		case subID > 0:
+			return SchemaObjectColumn
+		case relkind == "v":
+			return DDLObjectView
+		case relkind == "m":
+			return DDLObjectMaterializedView
+		case relkind == "i" || relkind == "I":
+			return DDLObjectIndex
+		case relkind == "S":
+			return DDLObjectSequence
+		}
+	case "pg_proc":
+		if prokind == "p" {
+			return SchemaObjectProcedure
+		}
+		return DDLObjectFunction
+	case "pg_constraint":
+		if contype == "f" {
+			return DDLObjectForeignKey
+		}
+		return DDLObjectConstraint
+	case "pg_trigger":
+		return DDLObjectTrigger
+	case "pg_policy":
+		return DependencyObjectPolicy
+	case "pg_rewrite":
+		return DependencyObjectRule
+	case "pg_attrdef":
+		return DependencyObjectDefault
+	case "pg_statistic_ext":
+		return DependencyObjectStatistics
+	}
+	return strings.ReplaceAll(identifiedType, " ", "_")
+}
+
+// recursesThrough reports whether objects depending on a dependent of
+// this type are followed: what depends on a view, a function, or a column
+// breaks along with it.
+func recursesThrough(dependencyType string) bool {
+	switch dependencyType {
+	case DDLObjectView, DDLObjectMaterializedView, DDLObjectFunction, SchemaObjectProcedure, SchemaObjectColumn:
+		return true
+	}
+	return false
+}
+
+// GetDependencies walks pg_depend from a table, or from one of its columns
+// when column is not empty, and returns what depends on it: views and
+// materialized views through their rewrite rules, functions with SQL-standard
+// bodies or taking the table's row type, foreign keys, constraints, indexes,
+// triggers, policies, rules, owned sequences, and generated columns. The
+// walk goes on through views, functions, and columns, level by level.
+// Functions whose source mentions the table (and column) are added as
+// source dependents, since PostgreSQL does not track what function bodies
+// use.
+func (c *PostgreSQLClientImpl) GetDependencies(ctx context.Context, schema, table, column string) (*DependencyReport, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	target := dependencyObject{classID: pgClassClassID}
+	var rowType uint32
+	var identity string
+	err := db.QueryRowContext(ctx, dependencyTargetQuery, schema, table, column).
+		Scan(&target.objID, &rowType, &target.subID, &identity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependency target: %w", err)
+	}
+	if column != "" && target.subID == 0 {
+		return nil, fmt.Errorf("column %s.%s.%s: %w", schema, table, column, ErrColumnNotFound)
+	}
+
+	report := &DependencyReport{Schema: schema, Table: table, Column: column, Dependencies: []*Dependency{}}
+	visited := map[dependencyObject]bool{target: true}
+	level := []dependencyObject{target}
+	if column == "" && rowType != 0 {
+		level = append(level, dependencyObject{classID: pgTypeClassID, objID: rowType})
+	}
+
+	for depth := 1; len(level) > 0 && depth <= maxDependencyDepth; depth++ {
+		found, next, err := loadDependents(ctx, db, level, visited, depth)
+		if err != nil {
+			return nil, err
+		}
+		report.Dependencies = append(report.Dependencies, found...)
+		level = next
+	}
+
+	sources, err := loadSourceDependents(ctx, db, table, column, identity, visited)
+	if err != nil {
+		return nil, err
+	}
+	report.Dependencies = append(report.Dependencies, sources...)
+	return report, nil
+}
+
+// loadDependents returns the dependents of one level of the walk that were
+// not visited yet, sorted by type and name, and the objects of the next
+// level.
+func loadDependents(ctx context.Context, db *sql.DB, level []dependencyObject, visited map[dependencyObject]bool,
+	depth int) ([]*Dependency, []dependencyObject, error) {
+	classIDs := make([]uint32, len(level))
+	objIDs := make([]uint32, len(level))
+	subIDs := make([]int32, len(level))
+	for i, object := range level {
+		classIDs[i], objIDs[i], subIDs[i] = object.classID, object.objID, object.subID
+	}
+
+	rows, err := db.QueryContext(ctx, dependentsQuery, classIDs, objIDs, subIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list dependents: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var found []*Dependency
+	var next []dependencyObject
+	for rows.Next() {
+		var object dependencyObject
+		var catalog, relkind, contype, prokind, deptype, identifiedType string
+		dependency := &Dependency{Depth: depth}
+		if err := rows.Scan(
+			&object.classID,
+			&object.objID,
+			&object.subID,
+			&catalog,
+			&relkind,
+			&contype,
+			&prokind,
+			&dependency.Schema,
+			&dependency.Name,
+			&dependency.Table,
+			&deptype,
+			&dependency.Via,
+			&identifiedType,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan dependent row: %w", err)
+		}
+		if visited[object] {
+			continue
+		}
+		visited[object] = true
+
+		dependency.Type = dependencyType(catalog, relkind, contype, prokind, object.subID, identifiedType)
+		dependency.Dependency = dependencyKinds[deptype]
+		found = append(found, dependency)
+		if recursesThrough(dependency.Type) {
+			next = append(next, object)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate dependent rows: %w", err)
+	}
+
+	sortDependencies(found)
+	return found, next, nil
+}
+
+// loadSourceDependents returns the functions and procedures, not found in
+// pg_depend, whose source mentions the table and, when given, the column as
+// whole words.
+func loadSourceDependents(ctx context.Context, db *sql.DB, table, column, identity string,
+	visited map[dependencyObject]bool) ([]*Dependency, error) {
+	columnPattern := ""
+	if column != "" {
+		columnPattern = wordPattern(column)
+	}
+	rows, err := db.QueryContext(ctx, dependencySourceQuery, wordPattern(table), columnPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source dependents: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var found []*Dependency
+	for rows.Next() {
+		object := dependencyObject{classID: pgProcClassID}
+		var prokind string
+		dependency := &Dependency{Dependency: DependencySource, Via: identity, Depth: 1}
+		if err := rows.Scan(&object.objID, &dependency.Schema, &dependency.Name, &prokind); err != nil {
+			return nil, fmt.Errorf("failed to scan source dependent row: %w", err)
+		}
+		if visited[object] {
+			continue
+		}
+		visited[object] = true
+		dependency.Type = dependencyType("pg_proc", "", "", prokind, 0, "")
+		found = append(found, dependency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate source dependent rows: %w", err)
+	}
+	return found, nil
+}
+
+// wordPattern returns a PostgreSQL regular expression matching name as a
+// whole word.
+func wordPattern(name string) string {
+	return `\m` + regexp.QuoteMeta(name) + `\M`
+}
+
+// sortDependencies orders dependents by type, schema, table, and name.
+func sortDependencies(dependencies []*Dependency) {
+	slices.SortFunc(dependencies, func(a, b *Dependency) int {
+		return strings.Compare(
+			a.Type+"\x00"+a.Schema+"\x00"+a.Table+"\x00"+a.Name,
+			b.Type+"\x00"+b.Schema+"\x00"+b.Table+"\x00"+b.Name)
+	})
+}
+
+// GetDependencies returns what depends on a table, or on one of its columns
+// when column is not empty, directly or through views and functions.
+func (a *App) GetDependencies(ctx context.Context, schema, table, column string) (*DependencyReport, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	if table == "" {
+		return nil, ErrTableRequired
+	}
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Getting dependencies", "schema", schema, "table", table, "column", column)
+
+	report, err := a.client.GetDependencies(ctx, schema, table, column)
+	if err != nil {
+		a.logger.Error("Failed to get dependencies", "error", err, "schema", schema, "table", table, "column", column)
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	a.logger.Debug("Successfully got dependencies", "count", len(report.Dependencies),
+		"schema", schema, "table", table, "column", column)
+	return report, nil
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testDependencyReport() *DependencyReport {
+	return &DependencyReport{
+		Schema: "public",
+		Table:  "customers",
+		Dependencies: []*Dependency{
+			{Type: DDLObjectForeignKey, Schema: "public", Name: "orders_customer_id_fkey", Table: "orders",
+				Dependency: DependencyNormal, Via: "public.customers.id", Depth: 1},
+			{Type: DDLObjectView, Schema: "public", Name: "active_customers",
+				Dependency: DependencyNormal, Via: "public.customers.email", Depth: 1},
+			{Type: DDLObjectView, Schema: "public", Name: "active_customer_emails",
+				Dependency: DependencyNormal, Via: "public.active_customers.email", Depth: 2},
+		},
+	}
+}
+
+func TestPostgreSQLClient_GetDependenciesWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	report, err := client.GetDependencies(context.Background(), "public", "customers", "")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, report)
+}
+
+func TestDependencyType(t *testing.T) {
+	tests := []struct {
+		catalog, relkind, contype, prokind string
+		subID                              int32
+		identifiedType                     string
+		want                               string
+	}{
+		{catalog: "pg_class", relkind: "v", want: DDLObjectView},
+		{catalog: "pg_class", relkind: "m", want: DDLObjectMaterializedView},
+		{catalog: "pg_class", relkind: "i", want: DDLObjectIndex},
+		{catalog: "pg_class", relkind: "I", want: DDLObjectIndex},
+		{catalog: "pg_class", relkind: "S", want: DDLObjectSequence},
+		{catalog: "pg_class", relkind: "r", subID: 3, want: SchemaObjectColumn},
+		{catalog: "pg_class", relkind: "v", subID: 2, want: SchemaObjectColumn},
+		{catalog: "pg_class", relkind: "c", identifiedType: "composite type", want: "composite_type"},
+		{catalog: "pg_proc", prokind: "f", want: DDLObjectFunction},
+		{catalog: "pg_proc", prokind: "p", want: SchemaObjectProcedure},
+		{catalog: "pg_constraint", contype: "f", want: DDLObjectForeignKey},
+		{catalog: "pg_constraint", contype: "c", want: DDLObjectConstraint},
+		{catalog: "pg_trigger", want: DDLObjectTrigger},
+		{catalog: "pg_policy", want: DependencyObjectPolicy},
+		{catalog: "pg_rewrite", want: DependencyObjectRule},
+		{catalog: "pg_attrdef", want: DependencyObjectDefault},
+		{catalog: "pg_statistic_ext", want: DependencyObjectStatistics},
+		{catalog: "pg_opclass", identifiedType: "operator class", want: "operator_class"},
+	}
+	for _, tt := range tests {
+		got := dependencyType(tt.catalog, tt.relkind, tt.contype, tt.prokind, tt.subID, tt.identifiedType)
+		assert.Equal(t, tt.want, got, "%s %q %q %q %d", tt.catalog, tt.relkind, tt.contype, tt.prokind, tt.subID)
+	}
+}
+
+func TestRecursesThrough(t *testing.T) {
+	for _, objectType := range []string{DDLObjectView, DDLObjectMaterializedView, DDLObjectFunction, SchemaObjectProcedure, SchemaObjectColumn} {
+		assert.True(t, recursesThrough(objectType), objectType)
+	}
+	for _, objectType := range []string{DDLObjectIndex, DDLObjectForeignKey, DDLObjectTrigger, DependencyObjectPolicy, DDLObjectSequence} {
+		assert.False(t, recursesThrough(objectType), objectType)
+	}
+}
+
+func TestWordPattern(t *testing.T) {
+	assert.Equal(t, `\mcustomers\M`, wordPattern("customers"))
+	assert.Equal(t, `\morder\.items\M`, wordPattern("order.items"))
+}
+
+func TestSortDependencies(t *testing.T) {
+	dependencies := testDependencyReport().Dependencies
+	sortDependencies(dependencies)
+
+	names := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		names[i] = dependency.Type + ":" + dependency.Name
+	}
+	assert.Equal(t, []string{
+		"foreign_key:orders_customer_id_fkey",
+		"view:active_customer_emails",
+		"view:active_customers",
+	}, names)
+}
+
+func TestDependencyReport_ResultSections(t *testing.T) {
+	report := testDependencyReport()
+	assert.Equal(t, []ResultSection{{Name: "dependencies", Records: report.Dependencies}}, report.ResultSections())
+}
+
+func TestApp_GetDependencies(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetDependencies", mock.Anything, DefaultSchema, "customers", "").Return(testDependencyReport(), nil)
+
+	report, err := app.GetDependencies(context.Background(), "", "customers", "")
+	require.NoError(t, err)
+	assert.Equal(t, "customers", report.Table)
+	assert.Len(t, report.Dependencies, 3)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetDependenciesError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetDependencies", mock.Anything, "public", "customers", "missing").Return(nil, ErrColumnNotFound)
+	mockClient.On("GetDependencies", mock.Anything, "public", "orders", "").Return(nil, errors.New("connection reset"))
+
+	report, err := app.GetDependencies(context.Background(), "public", "", "")
+	assert.ErrorIs(t, err, ErrTableRequired)
+	assert.Nil(t, report)
+
+	report, err = app.GetDependencies(context.Background(), "public", "customers", "missing")
+	assert.ErrorIs(t, err, ErrColumnNotFound)
+	assert.Nil(t, report)
+
+	report, err = app.GetDependencies(context.Background(), "public", "orders", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get dependencies")
+	assert.Nil(t, report)
+	mockClient.AssertExpectations(t)
+}
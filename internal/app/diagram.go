@@ -0,0 +1,259 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Diagram formats accepted by RelationshipGraph.Diagram.
+const (
+	DiagramMermaid  = "mermaid"
+	DiagramDOT      = "dot"
+	DiagramPlantUML = "plantuml"
+)
+
+// diagramRenderers maps each diagram format to its renderer.
+var diagramRenderers = map[string]func(g *RelationshipGraph) string{
+	DiagramMermaid:  renderMermaid,
+	DiagramDOT:      renderDOT,
+	DiagramPlantUML: renderPlantUML,
+}
+
+// DiagramFormats returns the supported diagram formats in sorted order.
+func DiagramFormats() []string {
+	formats := make([]string, 0, len(diagramRenderers))
+	for name := range diagramRenderers {
+		formats = append(formats, name)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// Diagram renders the graph as an entity-relationship diagram in the named
+// format: a Mermaid erDiagram, a Graphviz digraph, or a PlantUML IE
+// diagram. Each table lists its key columns; each foreign key is an edge
+// labelled with its name.
+func (g *RelationshipGraph) Diagram(format string) (string, error) {
+	render, ok := diagramRenderers[format]
+	if !ok {
+		return "", fmt.Errorf("%w: %q (supported: %s)", ErrUnsupportedDiagram, format, strings.Join(DiagramFormats(), ", "))
+	}
+	return render(g), nil
+}
+
+var (
+	// diagramIDUnsafe matches the characters not allowed in Mermaid entity
+	// names and PlantUML aliases.
+	diagramIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+	// typeModifier matches the modifier of a type such as varchar(255),
+	// which Mermaid does not accept in attribute types.
+	typeModifier = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// diagramIDs assigns each table a unique identifier usable in every diagram
+// syntax: the table name alone when no other table of the graph shares it,
+// schema_table otherwise, with unsafe characters replaced by underscores.
+func diagramIDs(tables []*GraphTable) map[tableRef]string {
+	names := make(map[string]int)
+	for _, t := range tables {
+		names[t.Name]++
+	}
+
+	ids := make(map[tableRef]string, len(tables))
+	used := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		base := t.Name
+		if names[t.Name] > 1 {
+			base = t.Schema + "_" + t.Name
+		}
+		base = diagramIDUnsafe.ReplaceAllString(base, "_")
+		if base == "" || (base[0] >= '0' && base[0] <= '9') {
+			base = "t_" + base
+		}
+
+		id := base
+		for i := 2; used[id]; i++ {
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[id] = true
+		ids[tableRef{t.Schema, t.Name}] = id
+	}
+	return ids
+}
+
+// keyMarkers returns the PK/FK markers of a column, e.g. ["PK", "FK"].
+func keyMarkers(column *KeyColumn) []string {
+	var markers []string
+	if column.PrimaryKey {
+		markers = append(markers, "PK")
+	}
+	if column.ForeignKey {
+		markers = append(markers, "FK")
+	}
+	return markers
+}
+
+// identifying reports whether every referencing column of r is part of its
+// table's primary key, which entity-relationship notation draws as a solid
+// line.
+func identifying(tables map[tableRef]*GraphTable, r *Relationship) bool {
+	t := tables[tableRef{r.Schema, r.Table}]
+	if t == nil {
+		return false
+	}
+	for _, name := range r.Columns {
+		primary := false
+		for _, column := range t.KeyColumns {
+			if column.Name == name && column.PrimaryKey {
+				primary = true
+				break
+			}
+		}
+		if !primary {
+			return false
+		}
+	}
+	return true
+}
+
+// crowsFoot returns the information-engineering notation of a relationship
+// shared by Mermaid and PlantUML: the referenced side ("||" exactly one or
+// "|o" zero or one), the line ("--" identifying or ".." not), and the
+// referencing side ("o{" zero or more or "o|" zero or one).
+func crowsFoot(tables map[tableRef]*GraphTable, r *Relationship) string {
+	parent, line, child := "||", "..", "o{"
+	if r.Optional {
+		parent = "|o"
+	}
+	if identifying(tables, r) {
+		line = "--"
+	}
+	if r.OneToOne {
+		child = "o|"
+	}
+	return parent + line + child
+}
+
+// tablesByRef indexes the tables of the graph.
+func (g *RelationshipGraph) tablesByRef() map[tableRef]*GraphTable {
+	tables := make(map[tableRef]*GraphTable, len(g.Tables))
+	for _, t := range g.Tables {
+		tables[tableRef{t.Schema, t.Name}] = t
+	}
+	return tables
+}
+
+func renderMermaid(g *RelationshipGraph) string {
+	ids := diagramIDs(g.Tables)
+	tables := g.tablesByRef()
+
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, t := range g.Tables {
+		if len(t.KeyColumns) == 0 {
+			fmt.Fprintf(&b, "    %s {\n    }\n", ids[tableRef{t.Schema, t.Name}])
+			continue
+		}
+		fmt.Fprintf(&b, "    %s {\n", ids[tableRef{t.Schema, t.Name}])
+		for _, column := range t.KeyColumns {
+			dataType := strings.Join(strings.Fields(typeModifier.ReplaceAllString(column.DataType, "")), "_")
+			fmt.Fprintf(&b, "        %s %s", dataType, diagramIDUnsafe.ReplaceAllString(column.Name, "_"))
+			if markers := keyMarkers(column); len(markers) > 0 {
+				b.WriteString(" " + strings.Join(markers, ", "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, r := range g.Relationships {
+		fmt.Fprintf(&b, "    %s %s %s : %q\n",
+			ids[tableRef{r.ReferencedSchema, r.ReferencedTable}],
+			crowsFoot(tables, r),
+			ids[tableRef{r.Schema, r.Table}],
+			strings.ReplaceAll(r.Name, `"`, "'"),
+		)
+	}
+	return b.String()
+}
+
+// dotQuote quotes s as a Graphviz string. Newlines are written as the \n
+// escape, which Graphviz renders as a centred line break.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+func renderDOT(g *RelationshipGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph relationships {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=box];\n")
+	for _, t := range g.Tables {
+		label := t.Schema + "." + t.Name
+		if len(t.KeyColumns) > 0 {
+			label += "\n"
+		}
+		for _, column := range t.KeyColumns {
+			label += "\n" + column.Name + " : " + column.DataType
+			if markers := keyMarkers(column); len(markers) > 0 {
+				label += " (" + strings.Join(markers, ", ") + ")"
+			}
+		}
+		fmt.Fprintf(&b, "    %s [label=%s];\n", dotQuote(t.Schema+"."+t.Name), dotQuote(label))
+	}
+	for _, r := range g.Relationships {
+		pairs := make([]string, len(r.Columns))
+		for i, column := range r.Columns {
+			pairs[i] = column + " = " + r.ReferencedColumns[i]
+		}
+		style := ""
+		if r.Optional {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "    %s -> %s [label=%s%s];\n",
+			dotQuote(r.Schema+"."+r.Table),
+			dotQuote(r.ReferencedSchema+"."+r.ReferencedTable),
+			dotQuote(r.Name+"\n"+strings.Join(pairs, ", ")),
+			style,
+		)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderPlantUML(g *RelationshipGraph) string {
+	ids := diagramIDs(g.Tables)
+	tables := g.tablesByRef()
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	b.WriteString("hide circle\n")
+	for _, t := range g.Tables {
+		fmt.Fprintf(&b, "entity %q as %s {\n", t.Schema+"."+t.Name, ids[tableRef{t.Schema, t.Name}])
+		for _, column := range t.KeyColumns {
+			prefix := ""
+			if column.PrimaryKey {
+				prefix = "* "
+			}
+			fmt.Fprintf(&b, "  %s%s : %s", prefix, column.Name, column.DataType)
+			for _, marker := range keyMarkers(column) {
+				b.WriteString(" <<" + marker + ">>")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n")
+	}
+	for _, r := range g.Relationships {
+		fmt.Fprintf(&b, "%s %s %s : %s\n",
+			ids[tableRef{r.ReferencedSchema, r.ReferencedTable}],
+			crowsFoot(tables, r),
+			ids[tableRef{r.Schema, r.Table}],
+			r.Name,
+		)
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDiagramGraph(t *testing.T) *RelationshipGraph {
+	t.Helper()
+	graph, err := testRelationshipGraph().subgraph("public", "orders", 1)
+	require.NoError(t, err)
+	return graph
+}
+
+func TestRelationshipGraph_DiagramMermaid(t *testing.T) {
+	diagram, err := testDiagramGraph(t).Diagram(DiagramMermaid)
+	require.NoError(t, err)
+	assert.Equal(t, `erDiagram
+    invoices {
+        integer id PK
+    }
+    customers {
+        integer id PK
+        integer region_id FK
+    }
+    orders {
+        integer id PK
+        integer customer_id FK
+        integer invoice_id FK
+    }
+    customers ||..o{ orders : "orders_customer_id_fkey"
+    invoices ||..o| orders : "orders_invoice_id_fkey"
+`, diagram)
+}
+
+func TestRelationshipGraph_DiagramDOT(t *testing.T) {
+	diagram, err := testDiagramGraph(t).Diagram(DiagramDOT)
+	require.NoError(t, err)
+	assert.Equal(t, `digraph relationships {
+    rankdir=LR;
+    node [shape=box];
+    "billing.invoices" [label="billing.invoices\n\nid : integer (PK)"];
+    "public.customers" [label="public.customers\n\nid : integer (PK)\nregion_id : integer (FK)"];
+    "public.orders" [label="public.orders\n\nid : integer (PK)\ncustomer_id : integer (FK)\ninvoice_id : integer (FK)"];
+    "public.orders" -> "public.customers" [label="orders_customer_id_fkey\ncustomer_id = id"];
+    "public.orders" -> "billing.invoices" [label="orders_invoice_id_fkey\ninvoice_id = id"];
+}
+`, diagram)
+}
+
+func TestRelationshipGraph_DiagramPlantUML(t *testing.T) {
+	diagram, err := testDiagramGraph(t).Diagram(DiagramPlantUML)
+	require.NoError(t, err)
+	assert.Equal(t, `@startuml
+hide circle
+entity "billing.invoices" as invoices {
+  * id : integer <<PK>>
+}
+entity "public.customers" as customers {
+  * id : integer <<PK>>
+  region_id : integer <<FK>>
+}
+entity "public.orders" as orders {
+  * id : integer <<PK>>
+  customer_id : integer <<FK>>
+  invoice_id : integer <<FK>>
+}
+customers ||..o{ orders : orders_customer_id_fkey
+invoices ||..o| orders : orders_invoice_id_fkey
+@enduml
+`, diagram)
+}
+
+func TestRelationshipGraph_DiagramNotation(t *testing.T) {
+	graph := &RelationshipGraph{
+		Tables: []*GraphTable{
+			{Schema: "public", Name: "users", KeyColumns: []*KeyColumn{
+				{Name: "id", DataType: "character varying(36)", PrimaryKey: true},
+			}},
+			{Schema: "public", Name: "user_roles", KeyColumns: []*KeyColumn{
+				{Name: "user id", DataType: "character varying(36)", PrimaryKey: true, ForeignKey: true},
+			}},
+			{Schema: "public", Name: "profiles", KeyColumns: []*KeyColumn{
+				{Name: "user_id", DataType: "character varying(36)", ForeignKey: true},
+			}},
+		},
+		Relationships: []*Relationship{
+			{
+				Name: "user_roles_user_fkey", Schema: "public", Table: "user_roles", Columns: []string{"user id"},
+				ReferencedSchema: "public", ReferencedTable: "users", ReferencedColumns: []string{"id"},
+			},
+			{
+				Name: `profiles "user" fkey`, Schema: "public", Table: "profiles", Columns: []string{"user_id"},
+				ReferencedSchema: "public", ReferencedTable: "users", ReferencedColumns: []string{"id"},
+				Optional: true, OneToOne: true,
+			},
+		},
+	}
+
+	diagram, err := graph.Diagram(DiagramMermaid)
+	require.NoError(t, err)
+	assert.Contains(t, diagram, "        character_varying id PK\n")
+	assert.Contains(t, diagram, "        character_varying user_id PK, FK\n")
+	assert.Contains(t, diagram, "    users ||--o{ user_roles : \"user_roles_user_fkey\"\n")
+	assert.Contains(t, diagram, "    users |o..o| profiles : \"profiles 'user' fkey\"\n")
+
+	diagram, err = graph.Diagram(DiagramDOT)
+	require.NoError(t, err)
+	assert.Contains(t, diagram, `[label="profiles \"user\" fkey\nuser_id = id", style=dashed];`)
+}
+
+func TestRelationshipGraph_DiagramUnsupported(t *testing.T) {
+	diagram, err := testDiagramGraph(t).Diagram("svg")
+	assert.ErrorIs(t, err, ErrUnsupportedDiagram)
+	assert.Contains(t, err.Error(), "dot, mermaid, plantuml")
+	assert.Empty(t, diagram)
+}
+
+func TestDiagramIDs(t *testing.T) {
+	ids := diagramIDs([]*GraphTable{
+		{Schema: "public", Name: "events"},
+		{Schema: "audit", Name: "events"},
+		{Schema: "public", Name: "order-lines"},
+		{Schema: "public", Name: "order_lines"},
+		{Schema: "public", Name: "2024"},
+	})
+
+	assert.Equal(t, map[tableRef]string{
+		{"public", "events"}:      "public_events",
+		{"audit", "events"}:       "audit_events",
+		{"public", "order-lines"}: "order_lines",
+		{"public", "order_lines"}: "order_lines_2",
+		{"public", "2024"}:        "t_2024",
+	}, ids)
+}
@@ -0,0 +1,603 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Kinds of SchemaChange, read from the source to the target.
+const (
+	SchemaChangeAdded   = "added"
+	SchemaChangeRemoved = "removed"
+	SchemaChangeChanged = "changed"
+)
+
+// Object types reported in SchemaChange.Type, besides the DDLObject* types
+// of tables, views, materialized views, constraints, indexes, and
+// functions.
+const (
+	SchemaObjectColumn    = "column"
+	SchemaObjectProcedure = "procedure"
+)
+
+// DiffSchemaOptions selects the schemas DiffSchema compares. The source is
+// Schema on the current connection. The target is TargetSchema (Schema when
+// empty) on the database TargetConnection points to, or on the current
+// connection when it is empty. Snapshot, when set, is a stored snapshot
+// compared in place of the source, and TargetSchema defaults to its schema.
+// Migration requests the script that brings the source in line with the
+// target.
+type DiffSchemaOptions struct {
+	Schema           string
+	TargetSchema     string
+	TargetConnection string
+	Snapshot         *SchemaSnapshot
+	Migration        bool
+}
+
+// SchemaDiff lists what differs between two schemas, from the source to the
+// target: an object is added when only the target has it, removed when only
+// the source has it, and changed when both have it with different
+// definitions. The columns, constraints, and indexes of an added or removed
+// table are not listed on their own. Migration, when requested, is the
+// script that turns the source into the target, to be run on the source.
+type SchemaDiff struct {
+	SourceDatabase string          `json:"source_database"`
+	SourceSchema   string          `json:"source_schema"`
+	TargetDatabase string          `json:"target_database"`
+	TargetSchema   string          `json:"target_schema"`
+	Changes        []*SchemaChange `json:"changes"`
+	Migration      string          `json:"migration,omitempty"`
+}
+
+// SchemaChange is one difference between the schemas. Table is set for the
+// columns, constraints, and indexes of a table. Source and Target describe
+// the object on each side (a column's type and modifiers, or the object's
+// definition), and Fields names what changed.
+type SchemaChange struct {
+	Change string   `json:"change"`
+	Type   string   `json:"type"`
+	Table  string   `json:"table,omitempty"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+	Source string   `json:"source,omitempty"`
+	Target string   `json:"target,omitempty"`
+}
+
+// ResultSections renders the changes, and the migration script when there
+// is one, as tables for the non-JSON result formats.
+func (d *SchemaDiff) ResultSections() []ResultSection {
+	sections := []ResultSection{{Name: "changes", Records: d.Changes}}
+	if d.Migration != "" {
+		sections = append(sections, ResultSection{
+			Name: "migration",
+			Records: []struct {
+				SQL string `json:"sql"`
+			}{{d.Migration}},
+		})
+	}
+	return sections
+}
+
+// Migration phases. Views and constraints are dropped before the tables and
+// columns they use change, and created after; functions are created first
+// since defaults and checks may call them, and dropped last.
+const (
+	migrateDropViews = iota
+	migrateDropForeignKeys
+	migrateDropConstraints
+	migrateDropIndexes
+	migrateDropTables
+	migrateFunctions
+	migrateTables
+	migrateColumns
+	migrateConstraints
+	migrateIndexes
+	migrateForeignKeys
+	migrateViews
+	migrateDropFunctions
+	migratePhases
+)
+
+// schemaDiffer collects the changes between two snapshots and the
+// migration statements of each phase.
+type schemaDiffer struct {
+	changes    []*SchemaChange
+	statements [migratePhases][]string
+}
+
+func (d *schemaDiffer) change(change *SchemaChange, phase int, statements ...string) {
+	d.changes = append(d.changes, change)
+	d.add(phase, statements...)
+}
+
+func (d *schemaDiffer) add(phase int, statements ...string) {
+	d.statements[phase] = append(d.statements[phase], statements...)
+}
+
+// pairByName calls visit for each name of the source and target objects,
+// in name order, with the object of that name on each side; from is nil
+// for an object only the target has and to for one only the source has.
+func pairByName[T any](source, target []*T, name func(*T) string, visit func(from, to *T)) {
+	sources := make(map[string]*T, len(source))
+	for _, object := range source {
+		sources[name(object)] = object
+	}
+	targets := make(map[string]*T, len(target))
+	for _, object := range target {
+		targets[name(object)] = object
+	}
+	names := slices.Collect(maps.Keys(sources))
+	for name := range targets {
+		if _, ok := sources[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		visit(sources[name], targets[name])
+	}
+}
+
+// quoteName quotes an unqualified identifier. Snapshot definitions leave
+// the schema implicit, and so does the migration.
+func quoteName(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+// diffSnapshots compares two snapshots and, when migration is set, writes
+// the script that turns source into target.
+func diffSnapshots(source, target *SchemaSnapshot, migration bool) *SchemaDiff {
+	d := &schemaDiffer{}
+	d.diffTables(source.Tables, target.Tables)
+	d.diffViews(source.Views, target.Views)
+	d.diffFunctions(source.Functions, target.Functions)
+
+	diff := &SchemaDiff{
+		SourceDatabase: source.Database,
+		SourceSchema:   source.Schema,
+		TargetDatabase: target.Database,
+		TargetSchema:   target.Schema,
+		Changes:        d.changes,
+	}
+	if diff.Changes == nil {
+		diff.Changes = []*SchemaChange{}
+	}
+	if migration && len(d.changes) > 0 {
+		diff.Migration = d.script(source.Schema)
+	}
+	return diff
+}
+
+// script joins the migration statements, after setting the search path to
+// the schema they apply to. Function bodies are not validated, as in
+// DDLScript.Script, since they may use tables created later in the script.
+func (d *schemaDiffer) script(schema string) string {
+	var b strings.Builder
+	b.WriteString("SET check_function_bodies = false;\n")
+	b.WriteString("SET search_path TO " + quoteName(schema) + ";\n")
+	for _, statements := range d.statements {
+		for _, statement := range statements {
+			b.WriteString("\n" + statement + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (d *schemaDiffer) diffTables(source, target []*SnapshotTable) {
+	pairByName(source, target, func(t *SnapshotTable) string { return t.Name }, func(from, to *SnapshotTable) {
+		switch {
+		case from == nil:
+			d.change(&SchemaChange{Change: SchemaChangeAdded, Type: DDLObjectTable, Name: to.Name},
+				migrateTables, createTableStatement(to))
+			for _, column := range to.Columns {
+				d.add(migrateColumns, serialNullableStatements(to.Name, column)...)
+			}
+			for _, constraint := range to.Constraints {
+				d.add(constraintPhase(constraint, false), addConstraintStatement(to.Name, constraint))
+			}
+			for _, index := range to.Indexes {
+				d.add(migrateIndexes, index.Definition+";")
+			}
+		case to == nil:
+			d.change(&SchemaChange{Change: SchemaChangeRemoved, Type: DDLObjectTable, Name: from.Name},
+				migrateDropTables, "DROP TABLE "+quoteName(from.Name)+";")
+		default:
+			d.diffTable(from, to)
+		}
+	})
+}
+
+func (d *schemaDiffer) diffTable(from, to *SnapshotTable) {
+	if from.PartitionBy != to.PartitionBy {
+		d.change(&SchemaChange{
+			Change: SchemaChangeChanged, Type: DDLObjectTable, Name: to.Name, Fields: []string{"partition_by"},
+			Source: from.PartitionBy, Target: to.PartitionBy,
+		}, migrateTables, fmt.Sprintf("-- %s must be recreated to change its partitioning", quoteName(to.Name)))
+	}
+
+	table := to.Name
+	pairByName(from.Columns, to.Columns, func(c *SnapshotColumn) string { return c.Name }, func(a, b *SnapshotColumn) {
+		switch {
+		case a == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeAdded, Type: SchemaObjectColumn, Table: table, Name: b.Name,
+				Target: columnDefinition(b),
+			}, migrateColumns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;",
+				quoteName(table), quoteName(b.Name), columnDeclaration(b)))
+			d.add(migrateColumns, serialNullableStatements(table, b)...)
+		case b == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeRemoved, Type: SchemaObjectColumn, Table: table, Name: a.Name,
+				Source: columnDefinition(a),
+			}, migrateColumns, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", quoteName(table), quoteName(a.Name)))
+		default:
+			if fields := columnChanges(a, b); len(fields) > 0 {
+				d.change(&SchemaChange{
+					Change: SchemaChangeChanged, Type: SchemaObjectColumn, Table: table, Name: b.Name, Fields: fields,
+					Source: columnDefinition(a), Target: columnDefinition(b),
+				}, migrateColumns, alterColumnStatements(table, a, b)...)
+			}
+		}
+	})
+
+	pairByName(from.Constraints, to.Constraints, func(c *SnapshotConstraint) string { return c.Name }, func(a, b *SnapshotConstraint) {
+		switch {
+		case a == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeAdded, Type: DDLObjectConstraint, Table: table, Name: b.Name, Target: b.Definition,
+			}, constraintPhase(b, false), addConstraintStatement(table, b))
+		case b == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeRemoved, Type: DDLObjectConstraint, Table: table, Name: a.Name, Source: a.Definition,
+			}, constraintPhase(a, true), dropConstraintStatement(table, a))
+		case a.Type != b.Type || a.Definition != b.Definition:
+			d.change(&SchemaChange{
+				Change: SchemaChangeChanged, Type: DDLObjectConstraint, Table: table, Name: b.Name,
+				Fields: []string{"definition"}, Source: a.Definition, Target: b.Definition,
+			}, constraintPhase(a, true), dropConstraintStatement(table, a))
+			d.add(constraintPhase(b, false), addConstraintStatement(table, b))
+		}
+	})
+
+	pairByName(from.Indexes, to.Indexes, func(i *SnapshotIndex) string { return i.Name }, func(a, b *SnapshotIndex) {
+		switch {
+		case a == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeAdded, Type: DDLObjectIndex, Table: table, Name: b.Name, Target: b.Definition,
+			}, migrateIndexes, b.Definition+";")
+		case b == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeRemoved, Type: DDLObjectIndex, Table: table, Name: a.Name, Source: a.Definition,
+			}, migrateDropIndexes, "DROP INDEX "+quoteName(a.Name)+";")
+		case a.Definition != b.Definition:
+			d.change(&SchemaChange{
+				Change: SchemaChangeChanged, Type: DDLObjectIndex, Table: table, Name: b.Name,
+				Fields: []string{"definition"}, Source: a.Definition, Target: b.Definition,
+			}, migrateDropIndexes, "DROP INDEX "+quoteName(a.Name)+";")
+			d.add(migrateIndexes, b.Definition+";")
+		}
+	})
+}
+
+func (d *schemaDiffer) diffViews(source, target []*SnapshotView) {
+	pairByName(source, target, func(v *SnapshotView) string { return v.Name }, func(from, to *SnapshotView) {
+		switch {
+		case from == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeAdded, Type: viewType(to), Name: to.Name, Target: to.Definition,
+			}, migrateViews, createViewStatement(to))
+		case to == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeRemoved, Type: viewType(from), Name: from.Name, Source: from.Definition,
+			}, migrateDropViews, dropViewStatement(from))
+		default:
+			var fields []string
+			if from.Materialized != to.Materialized {
+				fields = append(fields, "materialized")
+			}
+			if from.Definition != to.Definition {
+				fields = append(fields, "definition")
+			}
+			if len(fields) == 0 {
+				return
+			}
+			d.change(&SchemaChange{
+				Change: SchemaChangeChanged, Type: viewType(to), Name: to.Name, Fields: fields,
+				Source: from.Definition, Target: to.Definition,
+			}, migrateDropViews, dropViewStatement(from))
+			d.add(migrateViews, createViewStatement(to))
+		}
+	})
+}
+
+func (d *schemaDiffer) diffFunctions(source, target []*SnapshotFunction) {
+	pairByName(source, target, func(f *SnapshotFunction) string { return f.Name }, func(from, to *SnapshotFunction) {
+		switch {
+		case from == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeAdded, Type: to.Kind, Name: to.Name, Target: to.Definition,
+			}, migrateFunctions, to.Definition+";")
+		case to == nil:
+			d.change(&SchemaChange{
+				Change: SchemaChangeRemoved, Type: from.Kind, Name: from.Name, Source: from.Definition,
+			}, migrateDropFunctions, dropFunctionStatement(from))
+		case from.Kind != to.Kind:
+			d.change(&SchemaChange{
+				Change: SchemaChangeChanged, Type: to.Kind, Name: to.Name, Fields: []string{"kind", "definition"},
+				Source: from.Definition, Target: to.Definition,
+			}, migrateFunctions, dropFunctionStatement(from), to.Definition+";")
+		case from.Definition != to.Definition:
+			// CREATE OR REPLACE cannot change the result type or the
+			// parameter names, OUT parameters, and defaults, so the
+			// function is dropped first when those differ.
+			var fields []string
+			if from.Arguments != to.Arguments {
+				fields = append(fields, "arguments")
+			}
+			if from.Result != to.Result {
+				fields = append(fields, "result")
+			}
+			statements := []string{to.Definition + ";"}
+			if len(fields) > 0 {
+				statements = append([]string{dropFunctionStatement(from)}, statements...)
+			}
+			d.change(&SchemaChange{
+				Change: SchemaChangeChanged, Type: to.Kind, Name: to.Name, Fields: append(fields, "definition"),
+				Source: from.Definition, Target: to.Definition,
+			}, migrateFunctions, statements...)
+		}
+	})
+}
+
+// columnDefinition renders a column's type and modifiers as written after
+// its name in CREATE TABLE.
+func columnDefinition(c *SnapshotColumn) string {
+	definition := c.DataType
+	if c.Generated != "" {
+		definition += " " + c.Generated
+	}
+	if c.Identity != "" {
+		definition += " GENERATED " + strings.ToUpper(c.Identity) + " AS IDENTITY"
+	}
+	if c.Default != "" {
+		definition += " DEFAULT " + c.Default
+	}
+	if !c.Nullable {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+// columnDeclaration renders a column as declared by CREATE TABLE and ADD
+// COLUMN. A serial column is declared as such, which creates the sequence
+// its default draws from; the default itself names a sequence the script
+// would otherwise never create.
+func columnDeclaration(c *SnapshotColumn) string {
+	if c.Serial == "" {
+		return columnDefinition(c)
+	}
+	declared := *c
+	declared.DataType, declared.Default = c.Serial, ""
+	return columnDefinition(&declared)
+}
+
+// serialNullableStatements drops the NOT NULL that declaring a nullable
+// column serial adds.
+func serialNullableStatements(table string, c *SnapshotColumn) []string {
+	if c.Serial == "" || !c.Nullable {
+		return nil
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", quoteName(table), quoteName(c.Name))}
+}
+
+// columnChanges names the attributes that differ between two versions of a
+// column.
+func columnChanges(from, to *SnapshotColumn) []string {
+	var fields []string
+	if from.DataType != to.DataType {
+		fields = append(fields, "data_type")
+	}
+	if from.Nullable != to.Nullable {
+		fields = append(fields, "nullable")
+	}
+	if from.Default != to.Default {
+		fields = append(fields, "default")
+	}
+	if from.Identity != to.Identity {
+		fields = append(fields, "identity")
+	}
+	if from.Generated != to.Generated {
+		fields = append(fields, "generated")
+	}
+	return fields
+}
+
+// alterColumnStatements turns column from into to. An identity is dropped
+// first so the column can take a default, and added last, once the column
+// is NOT NULL. A column cannot become generated, or change its expression
+// on every supported version, so a comment flags it instead.
+func alterColumnStatements(table string, from, to *SnapshotColumn) []string {
+	alter := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ", quoteName(table), quoteName(to.Name))
+	var statements []string
+	if from.Identity != "" && to.Identity == "" {
+		statements = append(statements, alter+"DROP IDENTITY;")
+	}
+	switch {
+	case from.Generated != "" && to.Generated == "":
+		statements = append(statements, alter+"DROP EXPRESSION;")
+	case from.Generated != to.Generated:
+		statements = append(statements, fmt.Sprintf("-- %s.%s must be recreated to change its generation expression",
+			quoteName(table), quoteName(to.Name)))
+	}
+	if from.DataType != to.DataType {
+		statements = append(statements, fmt.Sprintf("%sTYPE %s USING %s::%s;", alter, to.DataType, quoteName(to.Name), to.DataType))
+	}
+	if from.Default != to.Default {
+		if to.Default == "" {
+			statements = append(statements, alter+"DROP DEFAULT;")
+		} else {
+			statements = append(statements, alter+"SET DEFAULT "+to.Default+";")
+		}
+	}
+	if from.Nullable != to.Nullable {
+		if to.Nullable {
+			statements = append(statements, alter+"DROP NOT NULL;")
+		} else {
+			statements = append(statements, alter+"SET NOT NULL;")
+		}
+	}
+	if to.Identity != "" && from.Identity != to.Identity {
+		if from.Identity == "" {
+			statements = append(statements, alter+"ADD GENERATED "+strings.ToUpper(to.Identity)+" AS IDENTITY;")
+		} else {
+			statements = append(statements, alter+"SET GENERATED "+strings.ToUpper(to.Identity)+";")
+		}
+	}
+	return statements
+}
+
+// createTableStatement renders the CREATE TABLE statement of a table with
+// its columns; constraints and indexes are added in later phases.
+func createTableStatement(t *SnapshotTable) string {
+	columns := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		columns[i] = quoteName(column.Name) + " " + columnDeclaration(column)
+	}
+	statement := "CREATE TABLE " + quoteName(t.Name) + " ()"
+	if len(columns) > 0 {
+		statement = "CREATE TABLE " + quoteName(t.Name) + " (\n\t" + strings.Join(columns, ",\n\t") + "\n)"
+	}
+	if t.PartitionBy != "" {
+		statement += "\nPARTITION BY " + t.PartitionBy
+	}
+	return statement + ";"
+}
+
+// constraintPhase is the phase a constraint is dropped or added in. Foreign
+// keys are dropped first and added last, so the keys they reference exist.
+func constraintPhase(c *SnapshotConstraint, drop bool) int {
+	switch {
+	case c.Type == ConstraintForeignKey && drop:
+		return migrateDropForeignKeys
+	case c.Type == ConstraintForeignKey:
+		return migrateForeignKeys
+	case drop:
+		return migrateDropConstraints
+	default:
+		return migrateConstraints
+	}
+}
+
+func addConstraintStatement(table string, c *SnapshotConstraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", quoteName(table), quoteName(c.Name), c.Definition)
+}
+
+func dropConstraintStatement(table string, c *SnapshotConstraint) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", quoteName(table), quoteName(c.Name))
+}
+
+func viewType(v *SnapshotView) string {
+	if v.Materialized {
+		return DDLObjectMaterializedView
+	}
+	return DDLObjectView
+}
+
+func viewKeyword(v *SnapshotView) string {
+	if v.Materialized {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+func createViewStatement(v *SnapshotView) string {
+	return fmt.Sprintf("CREATE %s %s AS\n%s;", viewKeyword(v), quoteName(v.Name), v.Definition)
+}
+
+func dropViewStatement(v *SnapshotView) string {
+	return fmt.Sprintf("DROP %s %s;", viewKeyword(v), quoteName(v.Name))
+}
+
+// dropFunctionStatement drops a function or procedure by its identity
+// signature, which SnapshotFunction.Name already quotes.
+func dropFunctionStatement(f *SnapshotFunction) string {
+	return fmt.Sprintf("DROP %s %s;", strings.ToUpper(f.Kind), f.Name)
+}
+
+// newPostgreSQLClient is the default App.newClient.
+func newPostgreSQLClient() PostgreSQLClient {
+	return NewPostgreSQLClient()
+}
+
+// DiffSchema compares two schemas, on the current connection or the source
+// on it and the target on the database opts.TargetConnection points to, or
+// a stored snapshot with a live schema. The target connection is opened
+// read-only for the comparison only and closed afterwards.
+func (a *App) DiffSchema(ctx context.Context, opts DiffSchemaOptions) (*SchemaDiff, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to diff schema: %w", err)
+	}
+
+	if opts.Snapshot != nil {
+		opts.Schema = opts.Snapshot.Schema
+		if opts.TargetSchema == "" {
+			opts.TargetSchema = opts.Snapshot.Schema
+		}
+	} else {
+		if opts.Schema == "" {
+			opts.Schema = DefaultSchema
+		}
+		if opts.TargetSchema == "" {
+			opts.TargetSchema = opts.Schema
+		}
+		if opts.TargetConnection == "" && opts.TargetSchema == opts.Schema {
+			return nil, ErrSameDiffTarget
+		}
+	}
+
+	// The target connection string may hold a password: only log whether
+	// there is one.
+	a.logger.Debug("Diffing schema", "schema", opts.Schema, "target_schema", opts.TargetSchema,
+		"snapshot", opts.Snapshot != nil, "target_connection", opts.TargetConnection != "")
+
+	diff, err := a.diffSchema(ctx, opts)
+	if err != nil {
+		a.logger.Error("Failed to diff schema", "error", err, "schema", opts.Schema, "target_schema", opts.TargetSchema)
+		return nil, fmt.Errorf("failed to diff schema: %w", err)
+	}
+
+	a.logger.Debug("Successfully diffed schema", "change_count", len(diff.Changes),
+		"schema", opts.Schema, "target_schema", opts.TargetSchema)
+	return diff, nil
+}
+
+func (a *App) diffSchema(ctx context.Context, opts DiffSchemaOptions) (*SchemaDiff, error) {
+	source := opts.Snapshot
+	if source == nil {
+		var err error
+		if source, err = a.client.SnapshotSchema(ctx, opts.Schema); err != nil {
+			return nil, fmt.Errorf("failed to snapshot source: %w", err)
+		}
+	}
+
+	targetClient := a.client
+	if opts.TargetConnection != "" {
+		targetClient = a.newClient()
+		if err := targetClient.Connect(ctx, opts.TargetConnection); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTargetConnectionFailed, err)
+		}
+		defer func() { _ = targetClient.Close() }()
+	}
+
+	target, err := targetClient.SnapshotSchema(ctx, opts.TargetSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot target: %w", err)
+	}
+	return diffSnapshots(source, target, opts.Migration), nil
+}
@@ -0,0 +1,433 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testSnapshots returns a source and a target snapshot that differ in
+// every kind of object.
+func testSnapshots() (*SchemaSnapshot, *SchemaSnapshot) {
+	source := &SchemaSnapshot{
+		Database: "app",
+		Schema:   "public",
+		Tables: []*SnapshotTable{
+			{
+				Name: "customers",
+				Columns: []*SnapshotColumn{
+					{Name: "id", DataType: "integer"},
+					{Name: "email", DataType: "text", Nullable: true},
+					{Name: "name", DataType: "character varying(50)", Nullable: true},
+				},
+				Constraints: []*SnapshotConstraint{
+					{Name: "customers_pkey", Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+				},
+				Indexes: []*SnapshotIndex{
+					{Name: "customers_email_idx", Definition: "CREATE INDEX customers_email_idx ON customers USING btree (email)"},
+				},
+			},
+			{Name: "legacy", Columns: []*SnapshotColumn{{Name: "id", DataType: "integer", Nullable: true}}},
+		},
+		Views: []*SnapshotView{
+			{Name: "active_customers", Definition: "SELECT customers.id\n   FROM customers"},
+		},
+		Functions: []*SnapshotFunction{
+			{Name: "old_total()", Kind: DDLObjectFunction, Definition: "CREATE OR REPLACE FUNCTION old_total()\n RETURNS integer\n LANGUAGE sql\nAS $function$SELECT 1$function$"},
+		},
+	}
+	target := &SchemaSnapshot{
+		Database: "app",
+		Schema:   "staging",
+		Tables: []*SnapshotTable{
+			{
+				Name: "customers",
+				Columns: []*SnapshotColumn{
+					{Name: "id", DataType: "bigint"},
+					{Name: "email", DataType: "text"},
+					{Name: "created_at", DataType: "timestamp with time zone", Nullable: true, Default: "now()"},
+				},
+				Constraints: []*SnapshotConstraint{
+					{Name: "customers_email_key", Type: ConstraintUnique, Definition: "UNIQUE (email)"},
+					{Name: "customers_pkey", Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+				},
+				Indexes: []*SnapshotIndex{
+					{Name: "customers_email_idx", Definition: "CREATE INDEX customers_email_idx ON customers USING btree (lower(email))"},
+				},
+			},
+			{
+				Name: "orders",
+				Columns: []*SnapshotColumn{
+					{Name: "id", DataType: "integer", Identity: "always"},
+					{Name: "customer_id", DataType: "bigint", Nullable: true},
+				},
+				Constraints: []*SnapshotConstraint{
+					{Name: "orders_customer_id_fkey", Type: ConstraintForeignKey, Definition: "FOREIGN KEY (customer_id) REFERENCES customers(id)"},
+					{Name: "orders_pkey", Type: ConstraintPrimaryKey, Definition: "PRIMARY KEY (id)"},
+				},
+				Indexes: []*SnapshotIndex{},
+			},
+		},
+		Views: []*SnapshotView{
+			{Name: "active_customers", Definition: "SELECT customers.id,\n    customers.email\n   FROM customers"},
+		},
+		Functions: []*SnapshotFunction{},
+	}
+	return source, target
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	source, target := testSnapshots()
+	diff := diffSnapshots(source, target, true)
+
+	assert.Equal(t, "public", diff.SourceSchema)
+	assert.Equal(t, "staging", diff.TargetSchema)
+
+	type change struct{ change, typ, table, name string }
+	changes := make([]change, len(diff.Changes))
+	for i, c := range diff.Changes {
+		changes[i] = change{c.Change, c.Type, c.Table, c.Name}
+	}
+	assert.Equal(t, []change{
+		{SchemaChangeAdded, SchemaObjectColumn, "customers", "created_at"},
+		{SchemaChangeChanged, SchemaObjectColumn, "customers", "email"},
+		{SchemaChangeChanged, SchemaObjectColumn, "customers", "id"},
+		{SchemaChangeRemoved, SchemaObjectColumn, "customers", "name"},
+		{SchemaChangeAdded, DDLObjectConstraint, "customers", "customers_email_key"},
+		{SchemaChangeChanged, DDLObjectIndex, "customers", "customers_email_idx"},
+		{SchemaChangeRemoved, DDLObjectTable, "", "legacy"},
+		{SchemaChangeAdded, DDLObjectTable, "", "orders"},
+		{SchemaChangeChanged, DDLObjectView, "", "active_customers"},
+		{SchemaChangeRemoved, DDLObjectFunction, "", "old_total()"},
+	}, changes)
+
+	assert.Equal(t, &SchemaChange{
+		Change: SchemaChangeChanged, Type: SchemaObjectColumn, Table: "customers", Name: "email",
+		Fields: []string{"nullable"}, Source: "text", Target: "text NOT NULL",
+	}, diff.Changes[1])
+	assert.Equal(t, "timestamp with time zone DEFAULT now()", diff.Changes[0].Target)
+
+	assert.Equal(t, `SET check_function_bodies = false;
+SET search_path TO "public";
+
+DROP VIEW "active_customers";
+
+DROP INDEX "customers_email_idx";
+
+DROP TABLE "legacy";
+
+CREATE TABLE "orders" (
+	"id" integer GENERATED ALWAYS AS IDENTITY NOT NULL,
+	"customer_id" bigint
+);
+
+ALTER TABLE "customers" ADD COLUMN "created_at" timestamp with time zone DEFAULT now();
+
+ALTER TABLE "customers" ALTER COLUMN "email" SET NOT NULL;
+
+ALTER TABLE "customers" ALTER COLUMN "id" TYPE bigint USING "id"::bigint;
+
+ALTER TABLE "customers" DROP COLUMN "name";
+
+ALTER TABLE "customers" ADD CONSTRAINT "customers_email_key" UNIQUE (email);
+
+ALTER TABLE "orders" ADD CONSTRAINT "orders_pkey" PRIMARY KEY (id);
+
+CREATE INDEX customers_email_idx ON customers USING btree (lower(email));
+
+ALTER TABLE "orders" ADD CONSTRAINT "orders_customer_id_fkey" FOREIGN KEY (customer_id) REFERENCES customers(id);
+
+CREATE VIEW "active_customers" AS
+SELECT customers.id,
+    customers.email
+   FROM customers;
+
+DROP FUNCTION old_total();
+`, diff.Migration)
+}
+
+func TestDiffSnapshots_Identical(t *testing.T) {
+	source, _ := testSnapshots()
+	target, _ := testSnapshots()
+	target.Schema = "copy"
+
+	diff := diffSnapshots(source, target, true)
+	assert.Empty(t, diff.Changes)
+	assert.NotNil(t, diff.Changes)
+	assert.Empty(t, diff.Migration)
+
+	source, target = testSnapshots()
+	diff = diffSnapshots(source, target, false)
+	assert.NotEmpty(t, diff.Changes)
+	assert.Empty(t, diff.Migration)
+}
+
+func TestDiffSnapshots_Functions(t *testing.T) {
+	source := &SchemaSnapshot{Schema: "public", Functions: []*SnapshotFunction{
+		{Name: "refresh()", Kind: DDLObjectFunction, Definition: "CREATE OR REPLACE FUNCTION refresh() v1"},
+		{Name: "total(integer)", Kind: DDLObjectFunction, Definition: "CREATE OR REPLACE FUNCTION total(integer) v1"},
+	}}
+	target := &SchemaSnapshot{Schema: "public", Functions: []*SnapshotFunction{
+		{Name: "archive()", Kind: SchemaObjectProcedure, Definition: "CREATE OR REPLACE PROCEDURE archive() v1"},
+		{Name: "refresh()", Kind: SchemaObjectProcedure, Definition: "CREATE OR REPLACE PROCEDURE refresh() v1"},
+		{Name: "total(integer)", Kind: DDLObjectFunction, Definition: "CREATE OR REPLACE FUNCTION total(integer) v2"},
+	}}
+
+	diff := diffSnapshots(source, target, true)
+	require.Len(t, diff.Changes, 3)
+	assert.Equal(t, SchemaChangeAdded, diff.Changes[0].Change)
+	assert.Equal(t, []string{"kind", "definition"}, diff.Changes[1].Fields)
+	assert.Equal(t, []string{"definition"}, diff.Changes[2].Fields)
+	assert.Equal(t, `SET check_function_bodies = false;
+SET search_path TO "public";
+
+CREATE OR REPLACE PROCEDURE archive() v1;
+
+DROP FUNCTION refresh();
+
+CREATE OR REPLACE PROCEDURE refresh() v1;
+
+CREATE OR REPLACE FUNCTION total(integer) v2;
+`, diff.Migration)
+}
+
+func TestDiffSnapshots_FunctionSignature(t *testing.T) {
+	source := &SchemaSnapshot{Schema: "public", Functions: []*SnapshotFunction{
+		{Name: "label(integer)", Kind: DDLObjectFunction, Arguments: "integer", Result: "text",
+			Definition: "CREATE OR REPLACE FUNCTION label(integer) RETURNS text v1"},
+		{Name: "split(text)", Kind: DDLObjectFunction, Arguments: "s text, OUT head text", Result: "text",
+			Definition: "CREATE OR REPLACE FUNCTION split(s text, OUT head text) v1"},
+	}}
+	target := &SchemaSnapshot{Schema: "public", Functions: []*SnapshotFunction{
+		{Name: "label(integer)", Kind: DDLObjectFunction, Arguments: "integer", Result: "character varying",
+			Definition: "CREATE OR REPLACE FUNCTION label(integer) RETURNS character varying v1"},
+		{Name: "split(text)", Kind: DDLObjectFunction, Arguments: "s text, OUT head text, OUT tail text", Result: "record",
+			Definition: "CREATE OR REPLACE FUNCTION split(s text, OUT head text, OUT tail text) v1"},
+	}}
+
+	diff := diffSnapshots(source, target, true)
+	require.Len(t, diff.Changes, 2)
+	assert.Equal(t, []string{"result", "definition"}, diff.Changes[0].Fields)
+	assert.Equal(t, []string{"arguments", "result", "definition"}, diff.Changes[1].Fields)
+	assert.Equal(t, `SET check_function_bodies = false;
+SET search_path TO "public";
+
+DROP FUNCTION label(integer);
+
+CREATE OR REPLACE FUNCTION label(integer) RETURNS character varying v1;
+
+DROP FUNCTION split(text);
+
+CREATE OR REPLACE FUNCTION split(s text, OUT head text, OUT tail text) v1;
+`, diff.Migration)
+}
+
+func TestAlterColumnStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to SnapshotColumn
+		expected []string
+	}{
+		{
+			name: "default and nullability",
+			from: SnapshotColumn{Name: "status", DataType: "text", Nullable: true},
+			to:   SnapshotColumn{Name: "status", DataType: "text", Default: "'new'::text"},
+			expected: []string{
+				`ALTER TABLE "t" ALTER COLUMN "status" SET DEFAULT 'new'::text;`,
+				`ALTER TABLE "t" ALTER COLUMN "status" SET NOT NULL;`,
+			},
+		},
+		{
+			name: "serial to identity",
+			from: SnapshotColumn{Name: "id", DataType: "integer", Default: "nextval('t_id_seq'::regclass)"},
+			to:   SnapshotColumn{Name: "id", DataType: "integer", Identity: "by default"},
+			expected: []string{
+				`ALTER TABLE "t" ALTER COLUMN "id" DROP DEFAULT;`,
+				`ALTER TABLE "t" ALTER COLUMN "id" ADD GENERATED BY DEFAULT AS IDENTITY;`,
+			},
+		},
+		{
+			name: "identity to plain column",
+			from: SnapshotColumn{Name: "id", DataType: "integer", Identity: "always"},
+			to:   SnapshotColumn{Name: "id", DataType: "bigint", Nullable: true},
+			expected: []string{
+				`ALTER TABLE "t" ALTER COLUMN "id" DROP IDENTITY;`,
+				`ALTER TABLE "t" ALTER COLUMN "id" TYPE bigint USING "id"::bigint;`,
+				`ALTER TABLE "t" ALTER COLUMN "id" DROP NOT NULL;`,
+			},
+		},
+		{
+			name:     "identity kind",
+			from:     SnapshotColumn{Name: "id", DataType: "integer", Identity: "by default"},
+			to:       SnapshotColumn{Name: "id", DataType: "integer", Identity: "always"},
+			expected: []string{`ALTER TABLE "t" ALTER COLUMN "id" SET GENERATED ALWAYS;`},
+		},
+		{
+			name:     "generated to plain column",
+			from:     SnapshotColumn{Name: "total", DataType: "numeric", Nullable: true, Generated: "GENERATED ALWAYS AS (price * 2) STORED"},
+			to:       SnapshotColumn{Name: "total", DataType: "numeric", Nullable: true},
+			expected: []string{`ALTER TABLE "t" ALTER COLUMN "total" DROP EXPRESSION;`},
+		},
+		{
+			name:     "generation expression",
+			from:     SnapshotColumn{Name: "total", DataType: "numeric", Nullable: true},
+			to:       SnapshotColumn{Name: "total", DataType: "numeric", Nullable: true, Generated: "GENERATED ALWAYS AS (price * 2) STORED"},
+			expected: []string{`-- "t"."total" must be recreated to change its generation expression`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, alterColumnStatements("t", &tt.from, &tt.to))
+		})
+	}
+}
+
+func TestDiffSnapshotsSerialColumns(t *testing.T) {
+	source := &SchemaSnapshot{Schema: "public", Tables: []*SnapshotTable{
+		{Name: "customers", Columns: []*SnapshotColumn{{Name: "id", DataType: "integer"}}},
+	}}
+	target := &SchemaSnapshot{Schema: "public", Tables: []*SnapshotTable{
+		{Name: "customers", Columns: []*SnapshotColumn{
+			{Name: "id", DataType: "integer"},
+			{Name: "ref", DataType: "smallint", Nullable: true, Default: "nextval('customers_ref_seq'::regclass)", Serial: "smallserial"},
+		}},
+		{Name: "orders", Columns: []*SnapshotColumn{
+			{Name: "id", DataType: "integer", Default: "nextval('orders_id_seq'::regclass)", Serial: "serial"},
+		}},
+	}}
+
+	diff := diffSnapshots(source, target, true)
+	require.Len(t, diff.Changes, 2)
+	assert.Equal(t, "smallint DEFAULT nextval('customers_ref_seq'::regclass)", diff.Changes[0].Target)
+	assert.Equal(t, `SET check_function_bodies = false;
+SET search_path TO "public";
+
+CREATE TABLE "orders" (
+	"id" serial NOT NULL
+);
+
+ALTER TABLE "customers" ADD COLUMN "ref" smallserial;
+
+ALTER TABLE "customers" ALTER COLUMN "ref" DROP NOT NULL;
+`, diff.Migration)
+}
+
+func TestCreateTableStatement(t *testing.T) {
+	assert.Equal(t, `CREATE TABLE "empty" ();`, createTableStatement(&SnapshotTable{Name: "empty"}))
+	assert.Equal(t, "CREATE TABLE \"orders\" (\n\t\"id\" bigserial NOT NULL,\n\t\"note\" text DEFAULT ''::text\n);",
+		createTableStatement(&SnapshotTable{
+			Name: "orders",
+			Columns: []*SnapshotColumn{
+				{Name: "id", DataType: "bigint", Default: "nextval('orders_id_seq'::regclass)", Serial: "bigserial"},
+				{Name: "note", DataType: "text", Nullable: true, Default: "''::text"},
+			},
+		}))
+	assert.Equal(t, "CREATE TABLE \"events\" (\n\t\"at\" date NOT NULL\n)\nPARTITION BY RANGE (at);",
+		createTableStatement(&SnapshotTable{
+			Name:        "events",
+			PartitionBy: "RANGE (at)",
+			Columns:     []*SnapshotColumn{{Name: "at", DataType: "date"}},
+		}))
+}
+
+func TestSchemaDiff_ResultSections(t *testing.T) {
+	diff := &SchemaDiff{Changes: []*SchemaChange{}}
+	sections := diff.ResultSections()
+	require.Len(t, sections, 1)
+	assert.Equal(t, "changes", sections[0].Name)
+
+	diff.Migration = "DROP TABLE \"legacy\";\n"
+	sections = diff.ResultSections()
+	require.Len(t, sections, 2)
+	assert.Equal(t, "migration", sections[1].Name)
+}
+
+func TestApp_DiffSchema(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+	source, target := testSnapshots()
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "public").Return(source, nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "staging").Return(target, nil)
+
+	diff, err := app.DiffSchema(context.Background(), DiffSchemaOptions{TargetSchema: "staging"})
+	require.NoError(t, err)
+	assert.Len(t, diff.Changes, 10)
+	assert.Empty(t, diff.Migration)
+
+	_, err = app.DiffSchema(context.Background(), DiffSchemaOptions{Schema: "public"})
+	assert.ErrorIs(t, err, ErrSameDiffTarget)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_DiffSchemaTargetConnection(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	targetClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+	app.newClient = func() PostgreSQLClient { return targetClient }
+	source, target := testSnapshots()
+	target.Database = "production"
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "public").Return(source, nil)
+	targetClient.On("Connect", mock.Anything, "postgres://prod/app").Return(nil)
+	targetClient.On("SnapshotSchema", mock.Anything, "public").Return(target, nil)
+	targetClient.On("Close").Return(nil)
+
+	diff, err := app.DiffSchema(context.Background(), DiffSchemaOptions{
+		TargetConnection: "postgres://prod/app", Migration: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "production", diff.TargetDatabase)
+	assert.NotEmpty(t, diff.Migration)
+	mockClient.AssertExpectations(t)
+	targetClient.AssertExpectations(t)
+}
+
+func TestApp_DiffSchemaError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	targetClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+	app.newClient = func() PostgreSQLClient { return targetClient }
+	source, _ := testSnapshots()
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "public").Return(source, nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "missing").Return(nil, ErrSchemaNotFound)
+	targetClient.On("Connect", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+	diff, err := app.DiffSchema(context.Background(), DiffSchemaOptions{TargetSchema: "missing"})
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	assert.Contains(t, err.Error(), "failed to snapshot target")
+	assert.Nil(t, diff)
+
+	diff, err = app.DiffSchema(context.Background(), DiffSchemaOptions{TargetConnection: "postgres://prod/app"})
+	assert.ErrorIs(t, err, ErrTargetConnectionFailed)
+	assert.Nil(t, diff)
+	targetClient.AssertNotCalled(t, "Close")
+	mockClient.AssertExpectations(t)
+	targetClient.AssertExpectations(t)
+}
+
+func TestApp_DiffSchemaSnapshot(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+	source, target := testSnapshots()
+	target.Schema = "public"
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "public").Return(target, nil)
+
+	diff, err := app.DiffSchema(context.Background(), DiffSchemaOptions{Snapshot: source})
+	require.NoError(t, err)
+	assert.Equal(t, "public", diff.SourceSchema)
+	assert.Equal(t, "public", diff.TargetSchema)
+	assert.Len(t, diff.Changes, 10)
+	mockClient.AssertNumberOfCalls(t, "SnapshotSchema", 1)
+}
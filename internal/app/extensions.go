@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExtensionInfo describes an extension available on the server. For
+// installed extensions, InstalledVersion and Schema are set, and
+// UpdateAvailable reports whether DefaultVersion is newer than the installed
+// one (ALTER EXTENSION ... UPDATE would change it).
+type ExtensionInfo struct {
+	Name             string `json:"name"`
+	Installed        bool   `json:"installed"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+	DefaultVersion   string `json:"default_version"`
+	UpdateAvailable  bool   `json:"update_available"`
+	Schema           string `json:"schema,omitempty"`
+	Description      string `json:"description,omitempty"`
+}
+
+// listExtensionsQuery lists every extension whose control file is present
+// on the server, joined to pg_extension for those installed in the current
+// database. pg_available_extensions lacks the schema, hence the join.
+const listExtensionsQuery = `
+	SELECT
+		a.name,
+		e.oid IS NOT NULL,
+		COALESCE(e.extversion, ''),
+		COALESCE(a.default_version, ''),
+		COALESCE(e.extversion <> a.default_version, false),
+		COALESCE(n.nspname, ''),
+		COALESCE(a.comment, '')
+	FROM pg_available_extensions a
+	LEFT JOIN pg_extension e ON e.extname = a.name
+	LEFT JOIN pg_namespace n ON n.oid = e.extnamespace
+	ORDER BY a.name`
+
+// ListExtensions returns the extensions available on the server, marking
+// those installed in the current database.
+func (c *PostgreSQLClientImpl) ListExtensions(ctx context.Context) ([]*ExtensionInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	rows, err := db.QueryContext(ctx, listExtensionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list extensions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	extensions := []*ExtensionInfo{}
+	for rows.Next() {
+		var extension ExtensionInfo
+		if err := rows.Scan(
+			&extension.Name,
+			&extension.Installed,
+			&extension.InstalledVersion,
+			&extension.DefaultVersion,
+			&extension.UpdateAvailable,
+			&extension.Schema,
+			&extension.Description,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan extension row: %w", err)
+		}
+		extensions = append(extensions, &extension)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate extension rows: %w", err)
+	}
+	return extensions, nil
+}
+
+// ListExtensions returns the extensions available on the server, or only
+// those installed in the current database when installedOnly is true.
+func (a *App) ListExtensions(ctx context.Context, installedOnly bool) ([]*ExtensionInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list extensions: %w", err)
+	}
+
+	a.logger.Debug("Listing extensions", "installed_only", installedOnly)
+
+	extensions, err := a.client.ListExtensions(ctx)
+	if err != nil {
+		a.logger.Error("Failed to list extensions", "error", err)
+		return nil, fmt.Errorf("failed to list extensions: %w", err)
+	}
+
+	if installedOnly {
+		installed := []*ExtensionInfo{}
+		for _, extension := range extensions {
+			if extension.Installed {
+				installed = append(installed, extension)
+			}
+		}
+		extensions = installed
+	}
+
+	a.logger.Debug("Successfully listed extensions", "count", len(extensions))
+	return extensions, nil
+}
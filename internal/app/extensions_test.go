@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_ListExtensionsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	extensions, err := client.ListExtensions(context.Background())
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, extensions)
+}
+
+func TestApp_ListExtensions(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	plpgsql := &ExtensionInfo{
+		Name: "plpgsql", Installed: true, InstalledVersion: "1.0", DefaultVersion: "1.0", Schema: "pg_catalog",
+	}
+	trgm := &ExtensionInfo{
+		Name: "pg_trgm", Installed: true, InstalledVersion: "1.5", DefaultVersion: "1.6",
+		UpdateAvailable: true, Schema: "public",
+	}
+	postgis := &ExtensionInfo{Name: "postgis", DefaultVersion: "3.4.2"}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListExtensions", mock.Anything).Return([]*ExtensionInfo{trgm, plpgsql, postgis}, nil)
+
+	extensions, err := app.ListExtensions(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ExtensionInfo{trgm, plpgsql, postgis}, extensions)
+
+	extensions, err = app.ListExtensions(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ExtensionInfo{trgm, plpgsql}, extensions)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListExtensionsError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListExtensions", mock.Anything).Return(nil, errors.New("connection reset"))
+
+	extensions, err := app.ListExtensions(context.Background(), true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list extensions")
+	assert.Nil(t, extensions)
+	mockClient.AssertExpectations(t)
+}
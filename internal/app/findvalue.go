@@ -0,0 +1,322 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Table limits of FindValue.
+const (
+	DefaultFindValueTables = 50
+	MaxFindValueTables     = 200
+)
+
+// findValueTableTimeout caps the probe of each table, like
+// countFallbackTimeout, so one large table without a usable index cannot use
+// up the whole search.
+const findValueTableTimeout = 5 * time.Second
+
+// FindValueOptions selects what FindValue looks for and where. Schema and
+// Table, when set, restrict the search to that schema and to tables of that
+// name; MaxTables caps the number of tables probed.
+type FindValueOptions struct {
+	Value     string
+	Schema    string
+	Table     string
+	MaxTables int
+}
+
+// ValueSearch is the outcome of FindValue. Matches lists the columns holding
+// the value with the number of rows that do. TablesSearched counts the
+// tables probed, Skipped those whose probe failed or timed out, and
+// TablesNotSearched the tables left out by MaxTables or because the overall
+// deadline passed.
+type ValueSearch struct {
+	Value             string          `json:"value"`
+	Matches           []*ValueMatch   `json:"matches"`
+	TablesSearched    int             `json:"tables_searched"`
+	Skipped           []*SkippedTable `json:"skipped,omitempty"`
+	TablesNotSearched int             `json:"tables_not_searched"`
+}
+
+// ValueMatch is a column holding the searched value in Rows rows.
+type ValueMatch struct {
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	Column   string `json:"column"`
+	DataType string `json:"data_type"`
+	Rows     int64  `json:"rows"`
+}
+
+// SkippedTable is a table FindValue could not search, with the reason:
+// "timeout" or the error PostgreSQL returned.
+type SkippedTable struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	Reason string `json:"reason"`
+}
+
+// ResultSections renders the matches as a table for the non-JSON result
+// formats.
+func (s *ValueSearch) ResultSections() []ResultSection {
+	return []ResultSection{{Name: "matches", Records: s.Matches}}
+}
+
+// valueColumn is a column FindValue may compare with the value. baseType is
+// the name of its type, or of the base type of its domain; castType is the
+// SQL spelling of that base type without a length, which the value is cast
+// to. It is bpchar for character columns, since character alone means
+// character(1) and would cut the value to its first character.
+type valueColumn struct {
+	schema   string
+	table    string
+	name     string
+	dataType string
+	baseType string
+	castType string
+}
+
+// valueColumnsQuery lists the columns of the tables and materialized views
+// the user may read, optionally restricted to a schema ($1) and a table name
+// ($2). Partitions are left out: probing the partitioned table covers them.
+const valueColumnsQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		a.attname,
+		format_type(a.atttypid, a.atttypmod),
+		bt.typname,
+		CASE WHEN bt.typname = 'bpchar' THEN 'bpchar' ELSE format_type(bt.oid, NULL) END
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_type t ON t.oid = a.atttypid
+	JOIN pg_type bt ON bt.oid = CASE WHEN t.typtype = 'd' THEN t.typbasetype ELSE t.oid END
+	WHERE c.relkind IN ('r', 'p', 'm') AND NOT c.relispartition
+		AND a.attnum > 0 AND NOT a.attisdropped
+		AND n.nspname NOT IN ('information_schema', 'pg_catalog') AND n.nspname !~ '^pg_(toast|temp_)'
+		AND has_schema_privilege(n.oid, 'USAGE')
+		AND has_column_privilege(c.oid, a.attnum, 'SELECT')
+		AND ($1 = '' OR n.nspname = $1)
+		AND ($2 = '' OR c.relname = $2)
+	ORDER BY n.nspname, c.relname, a.attnum`
+
+var (
+	// numberPattern matches the decimal numbers accepted by numeric and the
+	// floating-point types, leaving out NaN and Infinity.
+	numberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+	// uuidPattern matches a UUID with or without hyphens.
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-?([0-9a-fA-F]{4}-?){3}[0-9a-fA-F]{12}$`)
+)
+
+// valueCompatible reports whether value can be cast to the base type
+// without error, so the column can be compared with it. Only text, integer,
+// numeric, floating-point, and uuid columns are searched.
+func valueCompatible(baseType, value string) bool {
+	switch baseType {
+	case "text", "varchar", "bpchar", "name", "citext":
+		return true
+	case "int2":
+		_, err := strconv.ParseInt(value, 10, 16)
+		return err == nil
+	case "int4":
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	case "int8":
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case "numeric":
+		return numberPattern.MatchString(value)
+	case "float4":
+		_, err := strconv.ParseFloat(value, 32)
+		return err == nil && numberPattern.MatchString(value)
+	case "float8":
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil && numberPattern.MatchString(value)
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	default:
+		return false
+	}
+}
+
+// valueProbeQuery counts, in a single scan of the table, the rows where each
+// column equals $1. The OR of the comparisons lets the planner combine the
+// indexes of the columns.
+func valueProbeQuery(columns []*valueColumn) string {
+	counts := make([]string, len(columns))
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("%s = CAST($1 AS %s)", pgx.Identifier{column.name}.Sanitize(), column.castType)
+		counts[i] = "count(*) FILTER (WHERE " + conditions[i] + ")"
+	}
+	table := pgx.Identifier{columns[0].schema, columns[0].table}.Sanitize()
+	return "SELECT " + strings.Join(counts, ", ") + " FROM " + table + " WHERE " + strings.Join(conditions, " OR ")
+}
+
+// groupValueColumns keeps the columns compatible with value and groups them
+// by table, in catalog order.
+func groupValueColumns(columns []*valueColumn, value string) [][]*valueColumn {
+	var tables [][]*valueColumn
+	for _, column := range columns {
+		if !valueCompatible(column.baseType, value) {
+			continue
+		}
+		if n := len(tables); n > 0 && tables[n-1][0].schema == column.schema && tables[n-1][0].table == column.table {
+			tables[n-1] = append(tables[n-1], column)
+			continue
+		}
+		tables = append(tables, []*valueColumn{column})
+	}
+	return tables
+}
+
+// FindValue searches the columns whose type can hold opts.Value for rows
+// equal to it, one table at a time. Each probe is bounded by
+// findValueTableTimeout and at most opts.MaxTables tables are probed. Only
+// the number of matching rows per column is returned, never the rows.
+func (c *PostgreSQLClientImpl) FindValue(ctx context.Context, opts FindValueOptions) (*ValueSearch, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	rows, err := db.QueryContext(ctx, valueColumnsQuery, opts.Schema, opts.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list value columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []*valueColumn
+	for rows.Next() {
+		var column valueColumn
+		if err := rows.Scan(
+			&column.schema,
+			&column.table,
+			&column.name,
+			&column.dataType,
+			&column.baseType,
+			&column.castType,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan value column row: %w", err)
+		}
+		columns = append(columns, &column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate value column rows: %w", err)
+	}
+	if opts.Table != "" && len(columns) == 0 {
+		return nil, fmt.Errorf("table %s: %w", opts.Table, ErrTableNotFound)
+	}
+
+	search := &ValueSearch{Value: opts.Value, Matches: []*ValueMatch{}}
+	tables := groupValueColumns(columns, opts.Value)
+	for i, table := range tables {
+		if i >= opts.MaxTables || ctx.Err() != nil {
+			search.TablesNotSearched = len(tables) - i
+			break
+		}
+
+		counts, err := probeValue(ctx, db, table, opts.Value)
+		if err != nil {
+			if ctx.Err() != nil {
+				search.TablesNotSearched = len(tables) - i
+				break
+			}
+			search.Skipped = append(search.Skipped, &SkippedTable{
+				Schema: table[0].schema,
+				Table:  table[0].table,
+				Reason: skipReason(err),
+			})
+			continue
+		}
+
+		search.TablesSearched++
+		for j, count := range counts {
+			if count > 0 {
+				column := table[j]
+				search.Matches = append(search.Matches, &ValueMatch{
+					Schema:   column.schema,
+					Table:    column.table,
+					Column:   column.name,
+					DataType: column.dataType,
+					Rows:     count,
+				})
+			}
+		}
+	}
+	return search, nil
+}
+
+// probeValue runs valueProbeQuery on a table within findValueTableTimeout and
+// returns the number of matching rows of each column.
+func probeValue(ctx context.Context, db *sql.DB, columns []*valueColumn, value string) ([]int64, error) {
+	probeCtx, cancel := context.WithTimeout(ctx, findValueTableTimeout)
+	defer cancel()
+
+	counts := make([]int64, len(columns))
+	dest := make([]any, len(columns))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	if err := db.QueryRowContext(probeCtx, valueProbeQuery(columns), value).Scan(dest...); err != nil {
+		if probeCtx.Err() != nil {
+			return nil, probeCtx.Err()
+		}
+		return nil, err
+	}
+	return counts, nil
+}
+
+// skipReason describes why a table probe failed: "timeout" when it ran past
+// findValueTableTimeout, or PostgreSQL's message.
+func skipReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Message
+	}
+	return err.Error()
+}
+
+// FindValue reports which columns of which tables hold a value, and in how
+// many rows, searching every column whose type can hold it. MaxTables
+// defaults to DefaultFindValueTables and is capped at MaxFindValueTables.
+func (a *App) FindValue(ctx context.Context, opts FindValueOptions) (*ValueSearch, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find value: %w", err)
+	}
+
+	if opts.Value == "" {
+		return nil, ErrValueRequired
+	}
+	if opts.MaxTables <= 0 {
+		opts.MaxTables = DefaultFindValueTables
+	}
+	opts.MaxTables = min(opts.MaxTables, MaxFindValueTables)
+
+	a.logger.Debug("Finding value", "schema", opts.Schema, "table", opts.Table, "max_tables", opts.MaxTables)
+
+	search, err := a.client.FindValue(ctx, opts)
+	if err != nil {
+		a.logger.Error("Failed to find value", "error", err, "schema", opts.Schema, "table", opts.Table)
+		return nil, fmt.Errorf("failed to find value: %w", err)
+	}
+
+	a.logger.Debug("Successfully searched for value", "match_count", len(search.Matches),
+		"tables_searched", search.TablesSearched, "tables_skipped", len(search.Skipped),
+		"tables_not_searched", search.TablesNotSearched)
+	return search, nil
+}
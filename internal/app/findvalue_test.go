@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_FindValueWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	search, err := client.FindValue(context.Background(), FindValueOptions{Value: "42", MaxTables: 1})
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, search)
+}
+
+func TestValueCompatible(t *testing.T) {
+	tests := []struct {
+		baseType string
+		value    string
+		expected bool
+	}{
+		{"text", "anything", true},
+		{"varchar", "48213", true},
+		{"int2", "32767", true},
+		{"int2", "32768", false},
+		{"int4", "48213", true},
+		{"int4", "4821300000", false},
+		{"int8", "4821300000", true},
+		{"int8", "48.5", false},
+		{"numeric", "48.5", true},
+		{"numeric", "-1e3", true},
+		{"numeric", "NaN", false},
+		{"float4", "1e39", false},
+		{"float8", "1e39", true},
+		{"float8", "Infinity", false},
+		{"uuid", "6f1c2a9e-3b4d-4e5f-8a7b-9c0d1e2f3a4b", true},
+		{"uuid", "6f1c2a9e3b4d4e5f8a7b9c0d1e2f3a4b", true},
+		{"uuid", "48213", false},
+		{"jsonb", "48213", false},
+		{"bool", "true", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseType+" "+tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, valueCompatible(tt.baseType, tt.value))
+		})
+	}
+}
+
+func TestGroupValueColumns(t *testing.T) {
+	columns := []*valueColumn{
+		{schema: "public", table: "orders", name: "id", baseType: "int4", castType: "integer"},
+		{schema: "public", table: "orders", name: "created_at", baseType: "timestamptz"},
+		{schema: "public", table: "orders", name: "note", baseType: "text", castType: "text"},
+		{schema: "public", table: "tags", name: "id", baseType: "uuid", castType: "uuid"},
+		{schema: "sales", table: "orders", name: "id", baseType: "int8", castType: "bigint"},
+	}
+
+	tables := groupValueColumns(columns, "48213")
+	assert.Len(t, tables, 2)
+	assert.Equal(t, []*valueColumn{columns[0], columns[2]}, tables[0])
+	assert.Equal(t, []*valueColumn{columns[4]}, tables[1])
+
+	assert.Empty(t, groupValueColumns(columns[1:2], "48213"))
+}
+
+func TestValueProbeQuery(t *testing.T) {
+	query := valueProbeQuery([]*valueColumn{
+		{schema: "public", table: "Orders", name: "id", castType: "integer"},
+		{schema: "public", table: "Orders", name: "ref", castType: "character varying"},
+		{schema: "public", table: "Orders", name: "code", castType: "bpchar"},
+	})
+	assert.Equal(t, `SELECT count(*) FILTER (WHERE "id" = CAST($1 AS integer)), `+
+		`count(*) FILTER (WHERE "ref" = CAST($1 AS character varying)), `+
+		`count(*) FILTER (WHERE "code" = CAST($1 AS bpchar)) `+
+		`FROM "public"."Orders" WHERE "id" = CAST($1 AS integer) OR "ref" = CAST($1 AS character varying) `+
+		`OR "code" = CAST($1 AS bpchar)`, query)
+}
+
+func TestSkipReason(t *testing.T) {
+	assert.Equal(t, "timeout", skipReason(fmt.Errorf("probe: %w", context.DeadlineExceeded)))
+	assert.Equal(t, `materialized view "totals" has not been populated`,
+		skipReason(&pgconn.PgError{Code: "55000", Message: `materialized view "totals" has not been populated`}))
+	assert.Equal(t, "connection reset", skipReason(errors.New("connection reset")))
+}
+
+func TestApp_FindValue(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := &ValueSearch{
+		Value: "48213",
+		Matches: []*ValueMatch{
+			{Schema: "public", Table: "orders", Column: "customer_id", DataType: "integer", Rows: 12},
+		},
+		TablesSearched: 3,
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("FindValue", mock.Anything, FindValueOptions{Value: "48213", MaxTables: DefaultFindValueTables}).
+		Return(expected, nil)
+	mockClient.On("FindValue", mock.Anything, FindValueOptions{Value: "48213", Schema: "sales", MaxTables: MaxFindValueTables}).
+		Return(&ValueSearch{Value: "48213", Matches: []*ValueMatch{}}, nil)
+
+	search, err := app.FindValue(context.Background(), FindValueOptions{Value: "48213"})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, search)
+
+	search, err = app.FindValue(context.Background(), FindValueOptions{Value: "48213", Schema: "sales", MaxTables: 1000})
+	assert.NoError(t, err)
+	assert.Empty(t, search.Matches)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_FindValueError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+
+	search, err := app.FindValue(context.Background(), FindValueOptions{})
+	assert.ErrorIs(t, err, ErrValueRequired)
+	assert.Nil(t, search)
+
+	mockClient.On("FindValue", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("table missing: %w", ErrTableNotFound))
+
+	search, err = app.FindValue(context.Background(), FindValueOptions{Value: "42", Table: "missing"})
+	assert.ErrorIs(t, err, ErrTableNotFound)
+	assert.Contains(t, err.Error(), "failed to find value")
+	assert.Nil(t, search)
+	mockClient.AssertExpectations(t)
+}
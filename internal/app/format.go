@@ -0,0 +1,551 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result format names accepted by EncodeResult and the "format" argument of
+// the execute_query, list_tables, and table-based MCP tools.
+const (
+	// FormatJSON is the historical {"columns","rows","row_count"} shape.
+	FormatJSON = "json"
+	// FormatCompact is a JSON array whose first element is the header row,
+	// followed by one array per data row. It carries no keys at all, which
+	// makes it the cheapest JSON shape for wide result sets.
+	FormatCompact = "compact"
+	// FormatRecords is a JSON array of objects keyed by column name.
+	FormatRecords = "records"
+	// FormatCSV is RFC 4180 CSV with a header line.
+	FormatCSV = "csv"
+	// FormatMarkdown is a GitHub-flavored Markdown table.
+	FormatMarkdown = "markdown"
+	// FormatNDJSON is newline-delimited JSON, one record object per line.
+	FormatNDJSON = "ndjson"
+)
+
+// markdownNull is how SQL NULL is rendered in Markdown tables, where an empty
+// cell would be indistinguishable from an empty string.
+const markdownNull = "NULL"
+
+// ResultEncoder renders a QueryResult in a specific wire format.
+type ResultEncoder interface {
+	Encode(w io.Writer, result *QueryResult) error
+}
+
+// ResultEncoderFunc adapts a plain function to the ResultEncoder interface.
+type ResultEncoderFunc func(w io.Writer, result *QueryResult) error
+
+// Encode calls f(w, result).
+func (f ResultEncoderFunc) Encode(w io.Writer, result *QueryResult) error {
+	return f(w, result)
+}
+
+// JSONResultEncoder is implemented by encoders whose output is always a
+// single JSON value, whatever the number of rows. EncodeSections nests the
+// output of such encoders in one JSON object instead of writing headings.
+type JSONResultEncoder interface {
+	ResultEncoder
+	EncodesJSONValue() bool
+}
+
+// JSONResultEncoderFunc adapts a plain function writing a single JSON value
+// to the JSONResultEncoder interface.
+type JSONResultEncoderFunc func(w io.Writer, result *QueryResult) error
+
+// Encode calls f(w, result).
+func (f JSONResultEncoderFunc) Encode(w io.Writer, result *QueryResult) error {
+	return f(w, result)
+}
+
+// EncodesJSONValue reports true.
+func (JSONResultEncoderFunc) EncodesJSONValue() bool {
+	return true
+}
+
+// resultEncoders is the registry consulted by EncodeResult. It is guarded by
+// resultEncodersMu so RegisterResultEncoder is safe to call at any time.
+var (
+	resultEncodersMu sync.RWMutex
+	resultEncoders   = map[string]ResultEncoder{
+		FormatJSON:     JSONResultEncoderFunc(encodeJSON),
+		FormatCompact:  JSONResultEncoderFunc(encodeCompact),
+		FormatRecords:  JSONResultEncoderFunc(encodeRecords),
+		FormatCSV:      ResultEncoderFunc(encodeCSV),
+		FormatMarkdown: ResultEncoderFunc(encodeMarkdown),
+		FormatNDJSON:   ResultEncoderFunc(encodeNDJSON),
+	}
+)
+
+// RegisterResultEncoder adds (or replaces) the encoder used for format name.
+// Names are matched case-insensitively.
+func RegisterResultEncoder(name string, encoder ResultEncoder) {
+	resultEncodersMu.Lock()
+	defer resultEncodersMu.Unlock()
+	resultEncoders[strings.ToLower(name)] = encoder
+}
+
+// ResultFormats returns the registered format names in sorted order.
+func ResultFormats() []string {
+	resultEncodersMu.RLock()
+	defer resultEncodersMu.RUnlock()
+	names := make([]string, 0, len(resultEncoders))
+	for name := range resultEncoders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lookupResultEncoder resolves a format name, defaulting to FormatJSON when
+// format is empty.
+func lookupResultEncoder(format string) (ResultEncoder, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = FormatJSON
+	}
+	resultEncodersMu.RLock()
+	encoder, ok := resultEncoders[format]
+	resultEncodersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (supported: %s)",
+			ErrUnsupportedFormat, format, strings.Join(ResultFormats(), ", "))
+	}
+	return encoder, nil
+}
+
+// ValidateResultFormat reports whether format names a registered encoder so
+// callers can reject a bad format before running a query. An empty format
+// is valid and selects FormatJSON.
+func ValidateResultFormat(format string) error {
+	_, err := lookupResultEncoder(format)
+	return err
+}
+
+// EncodeResult renders result in the named format. An empty format selects
+// FormatJSON.
+func EncodeResult(format string, result *QueryResult) (string, error) {
+	encoder, err := lookupResultEncoder(format)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, result); err != nil {
+		return "", fmt.Errorf("failed to encode result as %s: %w", format, err)
+	}
+	return buf.String(), nil
+}
+
+// ResultSection is one named table of a multi-part result.
+type ResultSection struct {
+	Name    string
+	Records any
+}
+
+// SectionedResult is implemented by results made of several tables (such as
+// a table description's columns and constraints). The non-JSON formats
+// render each section separately instead of flattening the result into a
+// single row.
+type SectionedResult interface {
+	ResultSections() []ResultSection
+}
+
+// EncodeSections renders each section with NewQueryResult and the named
+// format. When the format's encoder is a JSONResultEncoder (json, compact,
+// records) the output is a JSON object keyed by section name; otherwise
+// (csv, markdown, ndjson) the sections are written one after another, each
+// under a "## name" heading.
+func EncodeSections(format string, sections []ResultSection) (string, error) {
+	encoder, err := lookupResultEncoder(format)
+	if err != nil {
+		return "", err
+	}
+	jsonEncoder, ok := encoder.(JSONResultEncoder)
+	asJSON := ok && jsonEncoder.EncodesJSONValue()
+
+	encoded := make([]string, len(sections))
+	for i, section := range sections {
+		result, err := NewQueryResult(section.Records)
+		if err != nil {
+			return "", fmt.Errorf("failed to tabulate %s: %w", section.Name, err)
+		}
+		out, err := EncodeResult(format, result)
+		if err != nil {
+			return "", err
+		}
+		encoded[i] = out
+	}
+
+	var buf bytes.Buffer
+	if asJSON {
+		buf.WriteByte('{')
+		for i, section := range sections {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(section.Name)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrMarshalFailed, err)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.WriteString(encoded[i])
+		}
+		buf.WriteByte('}')
+		return buf.String(), nil
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("## " + section.Name + "\n\n")
+		buf.WriteString(encoded[i])
+		if !strings.HasSuffix(encoded[i], "\n") {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.String(), nil
+}
+
+// NewQueryResult converts a struct, a pointer to a struct, or a slice of
+// either into a QueryResult so that tools returning metadata structs (such
+// as list_tables or describe_table) can reuse the result encoders. Columns
+// follow the struct's exported fields in declaration order and are named
+// after their json tags; fields tagged "-" are skipped. Nested slices and
+// structs are kept as-is and rendered as JSON by the text encoders.
+func NewQueryResult(records any) (*QueryResult, error) {
+	v := reflect.ValueOf(records)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return &QueryResult{Columns: []string{}, Rows: [][]any{}}, nil
+		}
+		v = v.Elem()
+	}
+
+	var elemType reflect.Type
+	var items []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType = v.Type().Elem()
+		for i := range v.Len() {
+			items = append(items, v.Index(i))
+		}
+	case reflect.Struct:
+		elemType = v.Type()
+		items = []reflect.Value{v}
+	default:
+		return nil, fmt.Errorf("%w: cannot tabulate %s", ErrUnsupportedFormat, v.Kind())
+	}
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: cannot tabulate elements of kind %s", ErrUnsupportedFormat, elemType.Kind())
+	}
+
+	fields, columns := recordFields(elemType)
+	rows := make([][]any, 0, len(items))
+	for _, item := range items {
+		for item.Kind() == reflect.Pointer {
+			if item.IsNil() {
+				break
+			}
+			item = item.Elem()
+		}
+		if item.Kind() != reflect.Struct {
+			continue
+		}
+		row := make([]any, len(fields))
+		for i, idx := range fields {
+			row[i] = cellValue(item.FieldByIndex(idx))
+		}
+		rows = append(rows, row)
+	}
+	return &QueryResult{Columns: columns, Rows: rows, RowCount: len(rows)}, nil
+}
+
+// cellValue unwraps a struct field for use as a QueryResult cell. Nil
+// pointers, slices, and maps become an untyped nil so that encoders render
+// them as NULL; non-nil pointers are dereferenced.
+func cellValue(field reflect.Value) any {
+	switch field.Kind() {
+	case reflect.Pointer:
+		if field.IsNil() {
+			return nil
+		}
+		return field.Elem().Interface()
+	case reflect.Slice, reflect.Map, reflect.Interface:
+		if field.IsNil() {
+			return nil
+		}
+	default:
+	}
+	return field.Interface()
+}
+
+// recordFields returns the index paths and JSON column names of the
+// exported fields of t. Like encoding/json, the fields of an untagged
+// embedded struct are promoted into t's columns.
+func recordFields(t reflect.Type) ([][]int, []string) {
+	var indexes [][]int
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("json")
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			embedded, embeddedNames := recordFields(field.Type)
+			for _, index := range embedded {
+				indexes = append(indexes, append([]int{i}, index...))
+			}
+			names = append(names, embeddedNames...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tagged {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		indexes = append(indexes, []int{i})
+		names = append(names, name)
+	}
+	return indexes, names
+}
+
+// encodeJSON writes the historical {"columns","rows","row_count"} shape.
+func encodeJSON(w io.Writer, result *QueryResult) error {
+	return writeJSON(w, result)
+}
+
+// encodeCompact writes [[header...], [row...], ...].
+func encodeCompact(w io.Writer, result *QueryResult) error {
+	out := make([][]any, 0, len(result.Rows)+1)
+	header := make([]any, len(result.Columns))
+	for i, c := range result.Columns {
+		header[i] = c
+	}
+	out = append(out, header)
+	out = append(out, result.Rows...)
+	return writeJSON(w, out)
+}
+
+// writeJSON marshals v with json.Marshal (no trailing newline, matching the
+// tool handlers' historical output) and writes it to w.
+func writeJSON(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalFailed, err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// encodeRecords writes a JSON array of objects keyed by column name.
+func encodeRecords(w io.Writer, result *QueryResult) error {
+	keys := recordKeys(result.Columns)
+	if _, err := io.WriteString(w, "["); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	for i, row := range result.Rows {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return fmt.Errorf("failed to write records: %w", err)
+			}
+		}
+		if err := writeRecord(w, keys, row); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, "]"); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	return nil
+}
+
+// encodeNDJSON writes one record object per line.
+func encodeNDJSON(w io.Writer, result *QueryResult) error {
+	keys := recordKeys(result.Columns)
+	for _, row := range result.Rows {
+		if err := writeRecord(w, keys, row); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return fmt.Errorf("failed to write ndjson: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordKeys derives unique object keys from column names. PostgreSQL allows
+// duplicate output names (SELECT a.id, b.id …); later duplicates get a
+// numeric suffix ("id_2") so no value is silently overwritten.
+func recordKeys(columns []string) []string {
+	keys := make([]string, len(columns))
+	seen := make(map[string]int, len(columns))
+	for i, c := range columns {
+		seen[c]++
+		key := c
+		for n := seen[c]; n > 1; n++ {
+			candidate := c + "_" + strconv.Itoa(n)
+			if _, taken := seen[candidate]; !taken {
+				key = candidate
+				seen[candidate] = 1
+				break
+			}
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// writeRecord writes a single JSON object, preserving column order (a Go map
+// would sort the keys).
+func writeRecord(w io.Writer, keys []string, row []any) error {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMarshalFailed, err)
+		}
+		var value any
+		if i < len(row) {
+			value = row[i]
+		}
+		v, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMarshalFailed, err)
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
+
+// encodeCSV writes a header line followed by one line per row. NULL is
+// written as an empty field.
+func encodeCSV(w io.Writer, result *QueryResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(result.Columns); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	record := make([]string, len(result.Columns))
+	for _, row := range result.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) && row[i] != nil {
+				record[i] = formatCell(row[i])
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+	return nil
+}
+
+// encodeMarkdown writes a GitHub-flavored Markdown table.
+func encodeMarkdown(w io.Writer, result *QueryResult) error {
+	var buf bytes.Buffer
+	writeMarkdownRow(&buf, result.Columns)
+	sep := make([]string, len(result.Columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeMarkdownRow(&buf, sep)
+	cells := make([]string, len(result.Columns))
+	for _, row := range result.Rows {
+		for i := range cells {
+			cells[i] = markdownNull
+			if i < len(row) && row[i] != nil {
+				cells[i] = escapeMarkdownCell(formatCell(row[i]))
+			}
+		}
+		writeMarkdownRow(&buf, cells)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+func writeMarkdownRow(buf *bytes.Buffer, cells []string) {
+	buf.WriteString("| ")
+	buf.WriteString(strings.Join(cells, " | "))
+	buf.WriteString(" |\n")
+}
+
+// escapeMarkdownCell keeps a value on one table row: pipes are escaped and
+// line breaks become <br>.
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, `|`, `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// formatCell renders a single non-NULL value for the text formats (CSV and
+// Markdown). Scalars use their natural textual form; composite values
+// (slices, maps, structs, json.RawMessage) are rendered as JSON.
+func formatCell(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case []byte:
+		return base64.StdEncoding.EncodeToString(val)
+	case json.RawMessage:
+		return string(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return val.String()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Pointer:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	default:
+	}
+	return fmt.Sprint(v)
+}
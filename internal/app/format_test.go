@@ -0,0 +1,260 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleResult() *QueryResult {
+	return &QueryResult{
+		Columns: []string{"id", "name", "note"},
+		Rows: [][]any{
+			{int64(1), "Alice", nil},
+			{int64(2), "Bob, Jr.", "line1\nline2 | piped"},
+		},
+		RowCount: 2,
+	}
+}
+
+func TestEncodeResult_JSONMatchesMarshal(t *testing.T) {
+	result := sampleResult()
+	want, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	for _, format := range []string{"", "json", "JSON"} {
+		got, err := EncodeResult(format, result)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), got, "format %q", format)
+	}
+}
+
+func TestEncodeResult_Compact(t *testing.T) {
+	got, err := EncodeResult(FormatCompact, sampleResult())
+	require.NoError(t, err)
+	assert.Equal(t, `[["id","name","note"],[1,"Alice",null],[2,"Bob, Jr.","line1\nline2 | piped"]]`, got)
+}
+
+func TestEncodeResult_Records(t *testing.T) {
+	got, err := EncodeResult(FormatRecords, sampleResult())
+	require.NoError(t, err)
+	assert.Equal(t,
+		`[{"id":1,"name":"Alice","note":null},{"id":2,"name":"Bob, Jr.","note":"line1\nline2 | piped"}]`, got)
+
+	empty, err := EncodeResult(FormatRecords, &QueryResult{Columns: []string{"id"}})
+	require.NoError(t, err)
+	assert.Equal(t, "[]", empty)
+}
+
+func TestEncodeResult_RecordsDisambiguatesDuplicateColumns(t *testing.T) {
+	result := &QueryResult{
+		Columns:  []string{"id", "id", "id_2"},
+		Rows:     [][]any{{1, 2, 3}},
+		RowCount: 1,
+	}
+	got, err := EncodeResult(FormatRecords, result)
+	require.NoError(t, err)
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(got), &decoded))
+	require.Len(t, decoded, 1)
+	assert.Len(t, decoded[0], 3, "no value may be overwritten by a duplicate key")
+	assert.Equal(t, float64(1), decoded[0]["id"])
+}
+
+func TestEncodeResult_NDJSON(t *testing.T) {
+	got, err := EncodeResult(FormatNDJSON, sampleResult())
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)), "line %q must be valid JSON", line)
+	}
+	assert.Equal(t, `{"id":1,"name":"Alice","note":null}`, lines[0])
+}
+
+func TestEncodeResult_CSV(t *testing.T) {
+	got, err := EncodeResult(FormatCSV, sampleResult())
+	require.NoError(t, err)
+	assert.Equal(t, "id,name,note\n1,Alice,\n2,\"Bob, Jr.\",\"line1\nline2 | piped\"\n", got)
+}
+
+func TestEncodeResult_Markdown(t *testing.T) {
+	got, err := EncodeResult(FormatMarkdown, sampleResult())
+	require.NoError(t, err)
+	assert.Equal(t,
+		"| id | name | note |\n"+
+			"| --- | --- | --- |\n"+
+			"| 1 | Alice | NULL |\n"+
+			"| 2 | Bob, Jr. | line1<br>line2 \\| piped |\n",
+		got)
+}
+
+func TestEncodeResult_UnsupportedFormat(t *testing.T) {
+	_, err := EncodeResult("xml", sampleResult())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+	assert.Contains(t, err.Error(), "markdown", "error should list supported formats")
+
+	assert.NoError(t, ValidateResultFormat(""))
+	assert.NoError(t, ValidateResultFormat("CSV"))
+	assert.ErrorIs(t, ValidateResultFormat("xml"), ErrUnsupportedFormat)
+}
+
+func TestRegisterResultEncoder(t *testing.T) {
+	RegisterResultEncoder("Count", ResultEncoderFunc(func(w io.Writer, r *QueryResult) error {
+		_, err := io.WriteString(w, "rows="+strings.Repeat("x", r.RowCount))
+		return err
+	}))
+	t.Cleanup(func() {
+		resultEncodersMu.Lock()
+		delete(resultEncoders, "count")
+		resultEncodersMu.Unlock()
+	})
+
+	assert.Contains(t, ResultFormats(), "count")
+	got, err := EncodeResult("count", sampleResult())
+	require.NoError(t, err)
+	assert.Equal(t, "rows=xx", got)
+}
+
+func TestResultFormats_Sorted(t *testing.T) {
+	formats := ResultFormats()
+	assert.Equal(t, []string{"compact", "csv", "json", "markdown", "ndjson", "records"}, formats)
+}
+
+func TestNewQueryResult_FromStructSlice(t *testing.T) {
+	tables := []*TableInfo{
+		{Schema: "public", Name: "users", Type: "table", Owner: "postgres", RowCount: 10},
+		{Schema: "public", Name: "v_users", Type: "view", Owner: "postgres"},
+	}
+	result, err := NewQueryResult(tables)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"schema", "name", "type", "row_count", "size", "owner", "description",
+		"partition_of", "partition_bound", "partitions",
+	}, result.Columns)
+	assert.Equal(t, 2, result.RowCount)
+	assert.Equal(t, []any{"public", "users", "table", int64(10), "", "postgres", "", "", "", nil}, result.Rows[0])
+}
+
+func TestNewQueryResult_FromSingleStruct(t *testing.T) {
+	result, err := NewQueryResult(&TableInfo{Schema: "s", Name: "t"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.RowCount)
+
+	var nilInfo *TableInfo
+	result, err = NewQueryResult(nilInfo)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.RowCount)
+}
+
+func TestNewQueryResult_NilFieldsBecomeNull(t *testing.T) {
+	type record struct {
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags,omitempty"`
+		Limit   *int     `json:"limit"`
+		Hidden  string   `json:"-"`
+		private string
+	}
+	limit := 5
+	result, err := NewQueryResult([]record{
+		{Name: "a", private: "x"},
+		{Name: "b", Tags: []string{"t1"}, Limit: &limit},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "tags", "limit"}, result.Columns)
+	assert.Equal(t, []any{"a", nil, nil}, result.Rows[0])
+	assert.Equal(t, []any{"b", []string{"t1"}, 5}, result.Rows[1])
+
+	csvOut, err := EncodeResult(FormatCSV, result)
+	require.NoError(t, err)
+	assert.Equal(t, "name,tags,limit\na,,\nb,\"[\"\"t1\"\"]\",5\n", csvOut)
+}
+
+func TestNewQueryResult_PromotesEmbeddedFields(t *testing.T) {
+	result, err := NewQueryResult([]*FunctionDefinition{{
+		FunctionInfo: FunctionInfo{Schema: "public", Name: "touch", Kind: FunctionKindFunction},
+		Definition:   "CREATE OR REPLACE FUNCTION public.touch() ...",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"schema", "name", "kind", "arguments", "return_type", "language", "volatility",
+		"security_definer", "owner", "description", "definition",
+	}, result.Columns)
+	assert.Equal(t, "touch", result.Rows[0][1])
+	assert.Equal(t, "CREATE OR REPLACE FUNCTION public.touch() ...", result.Rows[0][10])
+}
+
+func TestNewQueryResult_RejectsNonStructs(t *testing.T) {
+	_, err := NewQueryResult([]int{1, 2})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = NewQueryResult("nope")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func sampleDescription() *TableDescription {
+	return &TableDescription{
+		Schema:  "public",
+		Name:    "orders",
+		Columns: []*ColumnInfo{{Name: "id", DataType: "integer"}},
+		Constraints: []*ConstraintInfo{{
+			Name:              "orders_user_fk",
+			Type:              ConstraintForeignKey,
+			Columns:           []string{"user_id"},
+			Definition:        "FOREIGN KEY (user_id) REFERENCES users(id)",
+			ReferencedSchema:  "public",
+			ReferencedTable:   "users",
+			ReferencedColumns: []string{"id"},
+			OnDelete:          "no action",
+			OnUpdate:          "no action",
+		}},
+	}
+}
+
+func TestEncodeSections_JSONFormatsNestBySection(t *testing.T) {
+	got, err := EncodeSections(FormatCompact, sampleDescription().ResultSections())
+	require.NoError(t, err)
+
+	var decoded map[string][][]any
+	require.NoError(t, json.Unmarshal([]byte(got), &decoded))
+	assert.Equal(t, []any{"name", "data_type"}, decoded["columns"][0][:2])
+	require.Len(t, decoded["constraints"], 2)
+	assert.Equal(t, "orders_user_fk", decoded["constraints"][1][0])
+}
+
+func TestEncodeSections_TextFormatsUseHeadings(t *testing.T) {
+	got, err := EncodeSections(FormatCSV, sampleDescription().ResultSections())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "## columns\n\nname,data_type,is_nullable,"), got)
+	assert.Contains(t, got, "\n## constraints\n\nname,type,columns,definition,")
+
+	empty := &TableDescription{Columns: []*ColumnInfo{}, Constraints: []*ConstraintInfo{}}
+	got, err = EncodeSections(FormatMarkdown, empty.ResultSections())
+	require.NoError(t, err)
+	assert.Contains(t, got, "## constraints\n\n| name | type |", "empty sections keep their header row")
+}
+
+func TestEncodeSections_ShapeFollowsFormat(t *testing.T) {
+	// Single-row NDJSON sections are valid JSON, but still get headings.
+	got, err := EncodeSections(FormatNDJSON, sampleDescription().ResultSections())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "## columns\n\n{"), got)
+	assert.Contains(t, got, "\n## constraints\n\n{")
+
+	empty := &TableDescription{Columns: []*ColumnInfo{}, Constraints: []*ConstraintInfo{}}
+	got, err = EncodeSections(FormatRecords, empty.ResultSections())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"columns":[],"constraints":[]}`, got)
+}
+
+func TestEncodeSections_UnsupportedFormat(t *testing.T) {
+	_, err := EncodeSections("xml", sampleDescription().ResultSections())
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,244 @@
+package app
+
+import (
+	"context"
+	"fmt"
+)
+
+// Routine kinds reported in FunctionInfo.Kind, one per pg_proc.prokind.
+const (
+	FunctionKindFunction  = "function"
+	FunctionKindProcedure = "procedure"
+	FunctionKindAggregate = "aggregate"
+	FunctionKindWindow    = "window"
+)
+
+// FunctionInfo describes a function or procedure. Arguments is the argument
+// list as written in CREATE FUNCTION, with names, modes, and defaults;
+// ReturnType is empty for procedures and reads "TABLE(...)" or "SETOF ..."
+// for set-returning functions. Volatility is "immutable", "stable", or
+// "volatile". SecurityDefiner reports whether the function runs with the
+// privileges of its owner rather than the caller.
+type FunctionInfo struct {
+	Schema          string `json:"schema"`
+	Name            string `json:"name"`
+	Kind            string `json:"kind"`
+	Arguments       string `json:"arguments"`
+	ReturnType      string `json:"return_type,omitempty"`
+	Language        string `json:"language"`
+	Volatility      string `json:"volatility"`
+	SecurityDefiner bool   `json:"security_definer"`
+	Owner           string `json:"owner"`
+	Description     string `json:"description,omitempty"`
+}
+
+// FunctionDefinition is a FunctionInfo together with its complete CREATE OR
+// REPLACE statement as rendered by pg_get_functiondef. Aggregates have no
+// such statement, so their Definition is empty.
+type FunctionDefinition struct {
+	FunctionInfo
+
+	Definition string `json:"definition"`
+}
+
+// functionKinds maps pg_proc.prokind to the FunctionKind* constants.
+var functionKinds = map[string]string{
+	"f": FunctionKindFunction,
+	"p": FunctionKindProcedure,
+	"a": FunctionKindAggregate,
+	"w": FunctionKindWindow,
+}
+
+// functionVolatilities maps pg_proc.provolatile to a volatility name.
+var functionVolatilities = map[string]string{
+	"i": "immutable",
+	"s": "stable",
+	"v": "volatile",
+}
+
+// functionColumns are the FunctionInfo columns selected from pg_proc p,
+// pg_namespace n, and pg_language l, in scanFunction order.
+const functionColumns = `
+		n.nspname,
+		p.proname,
+		p.prokind::text,
+		pg_get_function_arguments(p.oid),
+		CASE WHEN p.prokind = 'p' THEN '' ELSE COALESCE(pg_get_function_result(p.oid), '') END,
+		l.lanname,
+		p.provolatile::text,
+		p.prosecdef,
+		pg_get_userbyid(p.proowner),
+		COALESCE(obj_description(p.oid, 'pg_proc'), '')`
+
+// functionFrom joins pg_proc to its schema and language and skips the
+// members of extensions, whose functions are not part of the schema's own
+// code.
+const functionFrom = `
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	JOIN pg_language l ON l.oid = p.prolang
+	WHERE NOT EXISTS (
+		SELECT 1 FROM pg_depend d
+		WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+	)`
+
+// scanFunction scans the functionColumns of one row, followed by extra.
+func scanFunction(rows interface{ Scan(dest ...any) error }, extra ...any) (*FunctionInfo, error) {
+	var fn FunctionInfo
+	var kind, volatility string
+	dest := append([]any{
+		&fn.Schema,
+		&fn.Name,
+		&kind,
+		&fn.Arguments,
+		&fn.ReturnType,
+		&fn.Language,
+		&volatility,
+		&fn.SecurityDefiner,
+		&fn.Owner,
+		&fn.Description,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller with its own context
+	}
+	fn.Kind = functionKinds[kind]
+	fn.Volatility = functionVolatilities[volatility]
+	return &fn, nil
+}
+
+// ListFunctions returns the functions and procedures defined in a schema.
+func (c *PostgreSQLClientImpl) ListFunctions(ctx context.Context, schema string) ([]*FunctionInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT`+functionColumns+functionFrom+`
+		AND n.nspname = $1
+		ORDER BY p.proname, pg_get_function_identity_arguments(p.oid)`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list functions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	functions := []*FunctionInfo{}
+	for rows.Next() {
+		fn, err := scanFunction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan function row: %w", err)
+		}
+		functions = append(functions, fn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate function rows: %w", err)
+	}
+	return functions, nil
+}
+
+// GetFunctionDefinition returns the definition of every overload of a
+// function, or only of the overload taking argTypes (e.g. "integer, text")
+// when it is not empty. argTypes is resolved by to_regprocedure, so type
+// aliases such as "int" and "varchar" are accepted.
+func (c *PostgreSQLClientImpl) GetFunctionDefinition(
+	ctx context.Context,
+	schema, name, argTypes string,
+) ([]*FunctionDefinition, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT`+functionColumns+`,
+		CASE WHEN p.prokind = 'a' THEN '' ELSE pg_get_functiondef(p.oid) END`+functionFrom+`
+		AND n.nspname = $1 AND p.proname = $2
+		AND ($3 = '' OR p.oid = to_regprocedure(format('%I.%I(%s)', $1::text, $2::text, $3::text)))
+		ORDER BY pg_get_function_identity_arguments(p.oid)`, schema, name, argTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get function definition: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var definitions []*FunctionDefinition
+	for rows.Next() {
+		var definition string
+		fn, err := scanFunction(rows, &definition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan function definition row: %w", err)
+		}
+		definitions = append(definitions, &FunctionDefinition{FunctionInfo: *fn, Definition: definition})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate function definition rows: %w", err)
+	}
+	if len(definitions) == 0 {
+		if argTypes != "" {
+			return nil, fmt.Errorf("function %s.%s(%s): %w", schema, name, argTypes, ErrFunctionNotFound)
+		}
+		return nil, fmt.Errorf("function %s.%s: %w", schema, name, ErrFunctionNotFound)
+	}
+	return definitions, nil
+}
+
+// ListFunctions returns the functions and procedures of a schema with their
+// signatures, language, volatility, and security mode.
+func (a *App) ListFunctions(ctx context.Context, schema string) ([]*FunctionInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list functions: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Listing functions", "schema", schema)
+
+	functions, err := a.client.ListFunctions(ctx, schema)
+	if err != nil {
+		a.logger.Error("Failed to list functions", "error", err, "schema", schema)
+		return nil, fmt.Errorf("failed to list functions: %w", err)
+	}
+
+	a.logger.Debug("Successfully listed functions", "count", len(functions), "schema", schema)
+	return functions, nil
+}
+
+// GetFunctionDefinition returns the source of a function or procedure; see
+// PostgreSQLClientImpl.GetFunctionDefinition for how overloads are chosen.
+func (a *App) GetFunctionDefinition(
+	ctx context.Context,
+	schema, name, argTypes string,
+) ([]*FunctionDefinition, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get function definition: %w", err)
+	}
+
+	if name == "" {
+		return nil, ErrFunctionRequired
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Getting function definition", "schema", schema, "function", name, "arguments", argTypes)
+
+	definitions, err := a.client.GetFunctionDefinition(ctx, schema, name, argTypes)
+	if err != nil {
+		a.logger.Error("Failed to get function definition", "error", err, "schema", schema, "function", name)
+		return nil, fmt.Errorf("failed to get function definition: %w", err)
+	}
+
+	a.logger.Debug("Successfully retrieved function definition", "overload_count", len(definitions),
+		"schema", schema, "function", name)
+	return definitions, nil
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_FunctionsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+
+	functions, err := client.ListFunctions(context.Background(), "public")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, functions)
+
+	definitions, err := client.GetFunctionDefinition(context.Background(), "public", "touch", "")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, definitions)
+}
+
+func TestApp_ListFunctions(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*FunctionInfo{
+		{
+			Schema:     DefaultSchema,
+			Name:       "touch_updated_at",
+			Kind:       FunctionKindFunction,
+			ReturnType: "trigger",
+			Language:   "plpgsql",
+			Volatility: "volatile",
+			Owner:      "postgres",
+		},
+		{
+			Schema:          DefaultSchema,
+			Name:            "archive_orders",
+			Kind:            FunctionKindProcedure,
+			Arguments:       "before date",
+			Language:        "plpgsql",
+			Volatility:      "volatile",
+			SecurityDefiner: true,
+			Owner:           "postgres",
+		},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListFunctions", mock.Anything, DefaultSchema).Return(expected, nil)
+
+	functions, err := app.ListFunctions(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, functions)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListFunctionsError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListFunctions", mock.Anything, "billing").Return(nil, errors.New("permission denied"))
+
+	functions, err := app.ListFunctions(context.Background(), "billing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list functions")
+	assert.Nil(t, functions)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetFunctionDefinition(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*FunctionDefinition{{
+		FunctionInfo: FunctionInfo{
+			Schema:     "billing",
+			Name:       "apply_discount",
+			Kind:       FunctionKindFunction,
+			Arguments:  "amount numeric, pct integer DEFAULT 10",
+			ReturnType: "numeric",
+			Language:   "sql",
+			Volatility: "immutable",
+			Owner:      "postgres",
+		},
+		Definition: "CREATE OR REPLACE FUNCTION billing.apply_discount(amount numeric, pct integer DEFAULT 10)\n" +
+			" RETURNS numeric\n LANGUAGE sql\n IMMUTABLE\nAS $function$ SELECT amount * (100 - pct) / 100 $function$\n",
+	}}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetFunctionDefinition", mock.Anything, "billing", "apply_discount", "numeric, int").
+		Return(expected, nil)
+
+	definitions, err := app.GetFunctionDefinition(context.Background(), "billing", "apply_discount", "numeric, int")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, definitions)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetFunctionDefinitionErrors(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetFunctionDefinition", mock.Anything, DefaultSchema, "missing", "").
+		Return(nil, fmt.Errorf("function public.missing: %w", ErrFunctionNotFound))
+
+	_, err := app.GetFunctionDefinition(context.Background(), "", "", "")
+	assert.ErrorIs(t, err, ErrFunctionRequired)
+
+	definitions, err := app.GetFunctionDefinition(context.Background(), "", "missing", "")
+	assert.ErrorIs(t, err, ErrFunctionNotFound)
+	assert.Nil(t, definitions)
+	mockClient.AssertExpectations(t)
+}
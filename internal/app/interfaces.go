@@ -25,58 +25,207 @@ var (
 	ErrNoDatabaseConnection = errors.New("no database connection")
 	ErrTableNotFound        = errors.New("table does not exist")
 	ErrMarshalFailed        = errors.New("failed to marshal data to JSON")
+	ErrUnsupportedFormat    = errors.New("unsupported result format")
+	ErrInvalidParams        = errors.New("invalid query parameters")
+	ErrInvalidTableType     = errors.New("invalid table type")
+	ErrNotPartitioned       = errors.New("table is not partitioned")
+	ErrNotAView             = errors.New("relation is not a view or materialized view")
+	ErrFunctionRequired     = errors.New("function name is required")
+	ErrFunctionNotFound     = errors.New("function does not exist")
+	ErrRoleNotFound         = errors.New("role does not exist")
+	ErrSchemaNotFound       = errors.New("schema does not exist")
+	ErrUnsupportedDiagram   = errors.New("unsupported diagram format")
+	ErrNoJoinPath           = errors.New("no join path between the tables")
+	ErrSearchPatternRequired = errors.New("search pattern is required")
+	ErrInvalidSearchMode     = errors.New("invalid search mode")
+	ErrInvalidObjectType     = errors.New("invalid object type")
+	ErrTrigramUnavailable    = errors.New("pg_trgm extension is not installed")
+	ErrValueRequired         = errors.New("value is required")
+	ErrSameDiffTarget        = errors.New("source and target are the same schema")
+	ErrTargetConnectionFailed = errors.New("failed to connect to the target database")
+	ErrInvalidSnapshot        = errors.New("invalid schema snapshot")
+	ErrUnsupportedSnapshot    = errors.New("unsupported schema snapshot version")
+	ErrLanguageRequired       = errors.New("language is required")
+	ErrColumnNotFound         = errors.New("column does not exist")
+	ErrUnsupportedLanguage    = errors.New("unsupported type language")
+	ErrInvalidTemplate        = errors.New("invalid type template")
 )
 
 // DatabaseInfo represents basic database metadata.
 type DatabaseInfo struct {
 	Name     string `json:"name"`
 	Owner    string `json:"owner"`
-	Encoding string `json:"encoding"`
-	Size     string `json:"size,omitempty"`
+	Encoding    string `json:"encoding"`
+	Size        string `json:"size,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 // SchemaInfo represents schema metadata.
 type SchemaInfo struct {
-	Name  string `json:"name"`
-	Owner string `json:"owner"`
-}
-
-// TableInfo represents table metadata.
-type TableInfo struct {
-	Schema      string `json:"schema"`
 	Name        string `json:"name"`
-	Type        string `json:"type"` // table, view, materialized view
-	RowCount    int64  `json:"row_count,omitempty"`
-	Size        string `json:"size,omitempty"`
 	Owner       string `json:"owner"`
 	Description string `json:"description,omitempty"`
 }
 
-// ColumnInfo represents column metadata.
+// Relation types reported in TableInfo.Type, one per pg_class.relkind that
+// list_tables enumerates.
+const (
+	TableTypeTable            = "table"
+	TableTypePartitionedTable = "partitioned table"
+	TableTypeView             = "view"
+	TableTypeMaterializedView = "materialized view"
+	TableTypeForeignTable     = "foreign table"
+)
+
+// TableInfo represents table metadata. For a partition, PartitionOf is the
+// quoted, schema-qualified parent and PartitionBound its FOR VALUES clause;
+// for a partitioned table, Partitions lists its direct partitions the same
+// way.
+type TableInfo struct {
+	Schema         string   `json:"schema"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"` // one of the TableType* constants
+	RowCount       int64    `json:"row_count,omitempty"`
+	Size           string   `json:"size,omitempty"`
+	Owner          string   `json:"owner"`
+	Description    string   `json:"description,omitempty"`
+	PartitionOf    string   `json:"partition_of,omitempty"`
+	PartitionBound string   `json:"partition_bound,omitempty"`
+	Partitions     []string `json:"partitions,omitempty"`
+}
+
+// ColumnInfo represents column metadata. DataType is the type as written in
+// DDL, including modifiers ("character varying(255)", "numeric(10,2)",
+// "integer[]", or the name of an enum or domain). Identity is "always" or
+// "by default" for identity columns; Generated is "stored" (or "virtual" on
+// PostgreSQL 18) for generated columns, whose expression is in
+// GenerationExpression rather than DefaultValue. Collation is set only when
+// it differs from the type's default. ElementType is the element type of an
+// array column, DomainBaseType the underlying type of a domain, and
+// EnumLabels the values of an enum (or of the enum behind an array or
+// domain) in sort order. StatisticsTarget is set only when overridden with
+// ALTER COLUMN ... SET STATISTICS. Selectable reports whether the current
+// role may SELECT the column.
 type ColumnInfo struct {
-	Name         string `json:"name"`
-	DataType     string `json:"data_type"`
-	IsNullable   bool   `json:"is_nullable"`
-	DefaultValue string `json:"default_value,omitempty"`
-	Description  string `json:"description,omitempty"`
+	Name                 string   `json:"name"`
+	DataType             string   `json:"data_type"`
+	IsNullable           bool     `json:"is_nullable"`
+	DefaultValue         string   `json:"default_value,omitempty"`
+	Description          string   `json:"description,omitempty"`
+	Identity             string   `json:"identity,omitempty"`
+	Generated            string   `json:"generated,omitempty"`
+	GenerationExpression string   `json:"generation_expression,omitempty"`
+	Collation            string   `json:"collation,omitempty"`
+	ElementType          string   `json:"element_type,omitempty"`
+	DomainBaseType       string   `json:"domain_base_type,omitempty"`
+	EnumLabels           []string `json:"enum_labels,omitempty"`
+	StatisticsTarget     *int64   `json:"statistics_target,omitempty"`
+	Selectable           bool     `json:"selectable"`
 }
 
-// IndexInfo represents index metadata.
+// Constraint types reported in ConstraintInfo.Type.
+const (
+	ConstraintPrimaryKey = "primary_key"
+	ConstraintUnique     = "unique"
+	ConstraintForeignKey = "foreign_key"
+	ConstraintCheck      = "check"
+	ConstraintExclusion  = "exclusion"
+)
+
+// ConstraintInfo represents a table constraint. Columns lists the
+// constrained columns in key order (empty for check constraints that are
+// not tied to specific columns); Definition is the constraint as rendered by
+// pg_get_constraintdef. The Referenced* fields and the OnDelete/OnUpdate
+// actions ("no action", "restrict", "cascade", "set null", "set default")
+// are only set for foreign keys, whose ReferencedColumns pair up with
+// Columns by position.
+type ConstraintInfo struct {
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Columns           []string `json:"columns"`
+	Definition        string   `json:"definition"`
+	ReferencedSchema  string   `json:"referenced_schema,omitempty"`
+	ReferencedTable   string   `json:"referenced_table,omitempty"`
+	ReferencedColumns []string `json:"referenced_columns,omitempty"`
+	OnDelete          string   `json:"on_delete,omitempty"`
+	OnUpdate          string   `json:"on_update,omitempty"`
+	Deferrable        bool     `json:"deferrable"`
+	InitiallyDeferred bool     `json:"initially_deferred"`
+}
+
+// TableDescription is the structure of a table: its columns in ordinal
+// order and its constraints.
+type TableDescription struct {
+	Schema      string            `json:"schema"`
+	Name        string            `json:"name"`
+	Columns     []*ColumnInfo     `json:"columns"`
+	Constraints []*ConstraintInfo `json:"constraints"`
+}
+
+// ResultSections splits the description into one table per section for the
+// non-JSON result formats.
+func (d *TableDescription) ResultSections() []ResultSection {
+	return []ResultSection{
+		{Name: "columns", Records: d.Columns},
+		{Name: "constraints", Records: d.Constraints},
+	}
+}
+
+// IndexInfo represents index metadata. Columns are the key columns in
+// index order, with expression keys rendered as SQL (e.g. "lower(email)");
+// Expressions repeats just those expression keys. IncludeColumns are the
+// non-key columns of an INCLUDE clause and Predicate the WHERE clause of a
+// partial index. An index is usable by queries only when IsValid; IsReady
+// without IsValid means a CREATE INDEX CONCURRENTLY is in progress or
+// failed. Scans and TuplesRead count index scans and the index entries they
+// returned since statistics were last reset. Definition is the full CREATE
+// INDEX statement.
 type IndexInfo struct {
-	Name      string   `json:"name"`
-	Table     string   `json:"table"`
-	Columns   []string `json:"columns"`
-	IsUnique  bool     `json:"is_unique"`
-	IsPrimary bool     `json:"is_primary"`
-	IndexType string   `json:"index_type"`
-	Size      string   `json:"size,omitempty"`
+	Name           string   `json:"name"`
+	Table          string   `json:"table"`
+	Columns        []string `json:"columns"`
+	IncludeColumns []string `json:"include_columns,omitempty"`
+	Expressions    []string `json:"expressions,omitempty"`
+	Predicate      string   `json:"predicate,omitempty"`
+	IsUnique       bool     `json:"is_unique"`
+	IsPrimary      bool     `json:"is_primary"`
+	IsValid        bool     `json:"is_valid"`
+	IsReady        bool     `json:"is_ready"`
+	IndexType      string   `json:"index_type"`
+	Size           string   `json:"size,omitempty"`
+	Scans          int64    `json:"scans"`
+	TuplesRead     int64    `json:"tuples_read"`
+	Definition     string   `json:"definition"`
+}
+
+// ColumnType describes a result column as reported by the driver. Type is
+// the PostgreSQL type name as found in pg_type.typname (e.g. "int4",
+// "numeric", "varchar", "_text" for text[]). OID is omitted when the driver
+// cannot resolve the type (e.g. user-defined enums). Nullable, Length,
+// Precision, and Scale are only set when known: Nullable is true when the
+// result column is taken from a table column that allows NULL, and unset
+// otherwise, as an outer join can yield NULL for a NOT NULL column; Length carries the declared
+// length of varchar/char/bit types, Precision and Scale the typmod of a
+// numeric column. Encoding names how values of the column are represented
+// in Rows when that is not evident from the JSON type (one of the Encoding*
+// constants); it is empty for plain numbers, booleans, and text.
+type ColumnType struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	OID       uint32 `json:"oid,omitempty"`
+	Nullable  *bool  `json:"nullable,omitempty"`
+	Length    *int64 `json:"length,omitempty"`
+	Precision *int64 `json:"precision,omitempty"`
+	Scale     *int64 `json:"scale,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
 }
 
 // QueryResult represents the result of a query execution.
 type QueryResult struct {
-	Columns  []string `json:"columns"`
-	Rows     [][]any  `json:"rows"`
-	RowCount int      `json:"row_count"`
+	Columns     []string      `json:"columns"`
+	ColumnTypes []*ColumnType `json:"column_types,omitempty"`
+	Rows        [][]any       `json:"rows"`
+	RowCount    int           `json:"row_count"`
 }
 
 // ConnectionManager handles database connection lifecycle.
@@ -100,21 +249,92 @@ type DatabaseExplorer interface {
 	GetCurrentDatabase(ctx context.Context) (string, error)
 	// ListSchemas returns all user-created schemas (excludes system schemas).
 	ListSchemas(ctx context.Context) ([]*SchemaInfo, error)
+	// ListExtensions returns the extensions available on the server, marking
+	// those installed in the current database.
+	ListExtensions(ctx context.Context) ([]*ExtensionInfo, error)
+	// GetSettings returns the server configuration parameters matching opts.
+	GetSettings(ctx context.Context, opts SettingsOptions) ([]*SettingInfo, error)
 }
 
 // TableExplorer handles table metadata and statistics retrieval.
 type TableExplorer interface {
-	// ListTables returns tables and views in the given schema.
-	ListTables(ctx context.Context, schema string) ([]*TableInfo, error)
-	// ListTablesWithStats returns tables with size and row count in a single optimized query.
-	ListTablesWithStats(ctx context.Context, schema string) ([]*TableInfo, error)
+	// ListTables returns the tables, partitioned tables, views, materialized
+	// views, and foreign tables of opts.Schema matching its Types, Pattern,
+	// and Search filters.
+	ListTables(ctx context.Context, opts *ListTablesOptions) ([]*TableInfo, error)
+	// ListTablesWithStats returns the tables of opts.Schema matching its
+	// Types, Pattern, and Search filters, with size and row count, in a
+	// single optimized query.
+	ListTablesWithStats(ctx context.Context, opts *ListTablesOptions) ([]*TableInfo, error)
 	// DescribeTable returns column metadata (name, type, nullable, default) for a table.
 	DescribeTable(ctx context.Context, schema, table string) ([]*ColumnInfo, error)
+	// ListConstraints returns the primary key, unique, foreign key, check, and
+	// exclusion constraints of a table.
+	ListConstraints(ctx context.Context, schema, table string) ([]*ConstraintInfo, error)
 	// GetTableStats returns row count statistics for a table, using pg_stat estimates
 	// and falling back to pg_class.reltuples for tables not yet covered by pg_stat.
 	GetTableStats(ctx context.Context, schema, table string) (*TableInfo, error)
-	// ListIndexes returns all indexes on a table with column, uniqueness, and type info.
+	// ListIndexes returns all indexes on a table with key and INCLUDE columns,
+	// expressions, predicates, validity, size, usage, and definition.
 	ListIndexes(ctx context.Context, schema, table string) ([]*IndexInfo, error)
+	// DescribePartitions returns the partition hierarchy of a partitioned table.
+	DescribePartitions(ctx context.Context, schema, table string) (*PartitionTree, error)
+	// GetViewDefinition returns the SQL, base relations, and updatability of a
+	// view or materialized view.
+	GetViewDefinition(ctx context.Context, schema, view string) (*ViewDefinition, error)
+	// ListTriggers returns the user-defined triggers of a table or view.
+	ListTriggers(ctx context.Context, schema, table string) ([]*TriggerInfo, error)
+	// ListRelationships returns the foreign-key graph of the database: the
+	// tables of the user schemas with their key columns, and the foreign
+	// keys between them.
+	ListRelationships(ctx context.Context) (*RelationshipGraph, error)
+	// ListIDColumns returns the *_id columns of the user tables that no
+	// foreign key covers.
+	ListIDColumns(ctx context.Context) ([]*IDColumn, error)
+}
+
+// CatalogExplorer handles discovery of schema objects other than relations.
+type CatalogExplorer interface {
+	// ListFunctions returns the functions and procedures defined in a schema,
+	// excluding those that belong to extensions.
+	ListFunctions(ctx context.Context, schema string) ([]*FunctionInfo, error)
+	// GetFunctionDefinition returns the source of every overload of a
+	// function, or of the one taking argTypes when it is not empty.
+	GetFunctionDefinition(ctx context.Context, schema, name, argTypes string) ([]*FunctionDefinition, error)
+	// ListTypes returns the enums, domains, composite types, and range types
+	// defined in a schema.
+	ListTypes(ctx context.Context, schema string) ([]*TypeInfo, error)
+	// ListSequences returns the sequences of a schema.
+	ListSequences(ctx context.Context, schema string) ([]*SequenceInfo, error)
+	// GetDDL reconstructs the DDL of a schema, or of one table when table is
+	// not empty, ordered so that it can be replayed.
+	GetDDL(ctx context.Context, schema, table string) (*DDLScript, error)
+	// SearchSchema returns the relations, columns, functions, and procedures
+	// of every usable schema whose name or comment matches opts, best first.
+	SearchSchema(ctx context.Context, opts SearchOptions) ([]*SearchResult, error)
+	// SnapshotSchema captures the tables, columns, constraints, indexes,
+	// views, and functions of a schema for comparison.
+	SnapshotSchema(ctx context.Context, schema string) (*SchemaSnapshot, error)
+	// ListCodegenTables returns the tables of a schema, or the one named
+	// table, with their column types broken down for type generation.
+	ListCodegenTables(ctx context.Context, schema, table string) ([]*CodegenTable, error)
+	// GetDependencies returns the objects depending on a table, or on one of
+	// its columns, directly or through views and functions.
+	GetDependencies(ctx context.Context, schema, table, column string) (*DependencyReport, error)
+}
+
+// SecurityExplorer handles roles, privileges, and row-level security.
+type SecurityExplorer interface {
+	// ListRoles returns the roles of the cluster other than the predefined
+	// pg_* roles, with their attributes and memberships.
+	ListRoles(ctx context.Context) ([]*RoleInfo, error)
+	// GetTablePrivileges returns the privileges role (the current user when
+	// empty) holds on the relations of a schema, or on one relation when table
+	// is not empty, and the grants on them.
+	GetTablePrivileges(ctx context.Context, role, schema, table string) ([]*TablePrivileges, error)
+	// ListPolicies returns the row-level security state and policies of the
+	// tables of a schema, or of one table when table is not empty.
+	ListPolicies(ctx context.Context, schema, table string) ([]*TableSecurity, error)
 }
 
 // QueryExecutor handles read-only query execution and analysis.
@@ -127,6 +347,9 @@ type QueryExecutor interface {
 	// runs EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON), which actually executes
 	// the query and is bounded by the caller's context (issue #89).
 	ExplainQuery(ctx context.Context, query string, analyze bool, args ...any) (*QueryResult, error)
+	// FindValue counts, per column, the rows of the readable tables that hold
+	// a value, probing each table under its own timeout.
+	FindValue(ctx context.Context, opts FindValueOptions) (*ValueSearch, error)
 }
 
 // PostgreSQLClient combines all database operations into a single read-only interface.
@@ -136,5 +359,7 @@ type PostgreSQLClient interface {
 	ConnectionManager
 	DatabaseExplorer
 	TableExplorer
+	CatalogExplorer
+	SecurityExplorer
 	QueryExecutor
 }
@@ -226,6 +226,40 @@ func TestPrimaryKeyIndex(t *testing.T) {
 	assert.True(t, deserializedIndex.IsPrimary)
 }
 
+func TestIndexInfoExpressionAndPartial(t *testing.T) {
+	index := &IndexInfo{
+		Name:           "idx_users_lower_email",
+		Table:          "users",
+		Columns:        []string{"lower(email::text)", "tenant_id"},
+		IncludeColumns: []string{"name"},
+		Expressions:    []string{"lower(email::text)"},
+		Predicate:      "deleted_at IS NULL",
+		IsValid:        true,
+		IsReady:        true,
+		IndexType:      "btree",
+		Scans:          12,
+		TuplesRead:     34,
+		Definition:     "CREATE INDEX idx_users_lower_email ON public.users USING btree (lower((email)::text), tenant_id) INCLUDE (name) WHERE (deleted_at IS NULL)",
+	}
+
+	jsonData, err := json.Marshal(index)
+	assert.NoError(t, err)
+	assert.Contains(t, string(jsonData), `"include_columns":["name"]`)
+	assert.Contains(t, string(jsonData), `"predicate":"deleted_at IS NULL"`)
+	assert.Contains(t, string(jsonData), `"scans":12`)
+
+	var deserializedIndex IndexInfo
+	err = json.Unmarshal(jsonData, &deserializedIndex)
+	assert.NoError(t, err)
+	assert.Equal(t, index, &deserializedIndex)
+
+	plain, err := json.Marshal(&IndexInfo{Name: "users_pkey", Columns: []string{"id"}})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(plain), "include_columns")
+	assert.NotContains(t, string(plain), "expressions")
+	assert.NotContains(t, string(plain), "predicate")
+}
+
 func TestQueryResultSerialization(t *testing.T) {
 	result := &QueryResult{
 		Columns: []string{"id", "name", "email"},
@@ -316,3 +350,26 @@ func TestQueryResultWithMixedTypes(t *testing.T) {
 	assert.Equal(t, true, deserializedResult.Rows[0][3])
 	assert.Equal(t, 95.5, deserializedResult.Rows[0][4])
 }
+
+func TestQueryResultColumnTypesSerialization(t *testing.T) {
+	precision, scale := int64(10), int64(2)
+	result := &QueryResult{
+		Columns: []string{"id", "price"},
+		ColumnTypes: []*ColumnType{
+			{Name: "id", Type: "int4", OID: 23},
+			{Name: "price", Type: "numeric", OID: 1700, Precision: &precision, Scale: &scale},
+		},
+		Rows:     [][]interface{}{{1, "9.99"}},
+		RowCount: 1,
+	}
+
+	jsonData, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(jsonData), `"column_types":[{"name":"id","type":"int4","oid":23},`)
+	assert.Contains(t, string(jsonData), `"precision":10,"scale":2`)
+
+	// column_types is omitted entirely when no metadata is available.
+	jsonData, err = json.Marshal(&QueryResult{Columns: []string{"x"}, Rows: [][]interface{}{}})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(jsonData), "column_types")
+}
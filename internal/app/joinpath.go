@@ -0,0 +1,368 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// JoinPathOptions selects the two tables FindJoinPath connects.
+// IncludeInferred adds the relationships guessed from *_id column names to
+// the declared foreign keys.
+type JoinPathOptions struct {
+	FromSchema      string
+	FromTable       string
+	ToSchema        string
+	ToTable         string
+	IncludeInferred bool
+}
+
+// JoinPath is a shortest chain of joins from one table to another. SQL is
+// the FROM clause that follows it, ready to paste into a query.
+type JoinPath struct {
+	FromSchema string      `json:"from_schema"`
+	FromTable  string      `json:"from_table"`
+	ToSchema   string      `json:"to_schema"`
+	ToTable    string      `json:"to_table"`
+	Joins      []*JoinStep `json:"joins"`
+	SQL        string      `json:"sql"`
+}
+
+// JoinStep is one join of a JoinPath: it adds Table to the tables joined so
+// far by equating each column pair. FromColumns belong to the previous table
+// of the path (FromSchema.FromTable), Columns to the joined one. Constraint
+// names the foreign key the join follows, and is empty when Inferred.
+type JoinStep struct {
+	Schema      string   `json:"schema"`
+	Table       string   `json:"table"`
+	Columns     []string `json:"columns"`
+	FromSchema  string   `json:"from_schema"`
+	FromTable   string   `json:"from_table"`
+	FromColumns []string `json:"from_columns"`
+	Constraint  string   `json:"constraint,omitempty"`
+	Inferred    bool     `json:"inferred"`
+	SQL         string   `json:"sql"`
+}
+
+// ResultSections renders the joins as a table for the non-JSON result
+// formats.
+func (p *JoinPath) ResultSections() []ResultSection {
+	return []ResultSection{{Name: "joins", Records: p.Joins}}
+}
+
+// IDColumn is a column named like a reference to another table (*_id) that
+// no foreign key covers.
+type IDColumn struct {
+	Schema   string
+	Table    string
+	Name     string
+	DataType string
+}
+
+// idColumnsQuery lists the *_id columns of every user table that are not
+// part of a foreign key. Partitions are left out, as in
+// relationshipTablesQuery.
+const idColumnsQuery = `
+	SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, a.atttypmod)
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition
+		AND a.attnum > 0 AND NOT a.attisdropped
+		AND a.attname LIKE '%\_id'
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_constraint con
+			WHERE con.conrelid = c.oid AND con.contype = 'f' AND a.attnum = ANY(con.conkey)
+		)
+		AND n.nspname NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
+		AND n.nspname NOT LIKE 'pg_temp%' AND n.nspname NOT LIKE 'pg_toast_temp%'
+	ORDER BY n.nspname, c.relname, a.attnum`
+
+// ListIDColumns returns the *_id columns of every user table that no foreign
+// key covers, the candidates for inferred relationships.
+func (c *PostgreSQLClientImpl) ListIDColumns(ctx context.Context) ([]*IDColumn, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	rows, err := db.QueryContext(ctx, idColumnsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list id columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []*IDColumn
+	for rows.Next() {
+		var column IDColumn
+		if err := rows.Scan(&column.Schema, &column.Table, &column.Name, &column.DataType); err != nil {
+			return nil, fmt.Errorf("failed to scan id column row: %w", err)
+		}
+		columns = append(columns, &column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate id column rows: %w", err)
+	}
+	return columns, nil
+}
+
+// referencedTableNames returns the table names a column named <prefix>_id
+// may refer to: the prefix itself and its plural forms.
+func referencedTableNames(column string) []string {
+	prefix := strings.TrimSuffix(column, "_id")
+	if prefix == "" || prefix == column {
+		return nil
+	}
+	names := []string{prefix}
+	switch {
+	case strings.HasSuffix(prefix, "y"):
+		names = append(names, prefix+"s", strings.TrimSuffix(prefix, "y")+"ies")
+	case strings.HasSuffix(prefix, "s"), strings.HasSuffix(prefix, "x"), strings.HasSuffix(prefix, "z"),
+		strings.HasSuffix(prefix, "ch"), strings.HasSuffix(prefix, "sh"):
+		names = append(names, prefix+"es")
+	default:
+		names = append(names, prefix+"s")
+	}
+	return names
+}
+
+// inferRelationships guesses the relationships of the given *_id columns.
+// A column <prefix>_id refers to a table named <prefix> or its plural whose
+// primary key is a single column of the same type. A table of the column's
+// own schema is preferred; otherwise the name must be unique in the graph.
+func (g *RelationshipGraph) inferRelationships(columns []*IDColumn) []*Relationship {
+	primaryKeys := make(map[tableRef]*KeyColumn)
+	bySchema := make(map[tableRef]bool)
+	byName := make(map[string][]tableRef)
+	for _, t := range g.Tables {
+		ref := tableRef{t.Schema, t.Name}
+		bySchema[ref] = true
+		byName[t.Name] = append(byName[t.Name], ref)
+
+		var pk []*KeyColumn
+		for _, column := range t.KeyColumns {
+			if column.PrimaryKey {
+				pk = append(pk, column)
+			}
+		}
+		if len(pk) == 1 {
+			primaryKeys[ref] = pk[0]
+		}
+	}
+
+	var inferred []*Relationship
+	for _, column := range columns {
+		source := tableRef{column.Schema, column.Table}
+		var target tableRef
+		found := false
+		for _, name := range referencedTableNames(column.Name) {
+			if ref := (tableRef{column.Schema, name}); bySchema[ref] {
+				target, found = ref, true
+				break
+			}
+			if refs := byName[name]; len(refs) == 1 {
+				target, found = refs[0], true
+				break
+			}
+		}
+		if !found || target == source {
+			continue
+		}
+
+		pk := primaryKeys[target]
+		if pk == nil || pk.DataType != column.DataType {
+			continue
+		}
+		inferred = append(inferred, &Relationship{
+			Schema:            column.Schema,
+			Table:             column.Table,
+			Columns:           []string{column.Name},
+			ReferencedSchema:  target.schema,
+			ReferencedTable:   target.name,
+			ReferencedColumns: []string{pk.Name},
+			Inferred:          true,
+		})
+	}
+	return inferred
+}
+
+// joinPath searches the graph breadth-first for a shortest chain of
+// relationships between two tables, following each in either direction.
+// Among equally short chains, declared foreign keys are tried before
+// inferred relationships.
+func (g *RelationshipGraph) joinPath(from, to tableRef) (*JoinPath, error) {
+	for _, ref := range []tableRef{from, to} {
+		if !g.hasTable(ref) {
+			return nil, fmt.Errorf("table %s.%s: %w", ref.schema, ref.name, ErrTableNotFound)
+		}
+	}
+
+	type edge struct {
+		relationship *Relationship
+		next         tableRef
+	}
+	neighbours := make(map[tableRef][]edge)
+	for _, inferred := range []bool{false, true} {
+		for _, r := range g.Relationships {
+			if r.Inferred != inferred {
+				continue
+			}
+			child, parent := tableRef{r.Schema, r.Table}, tableRef{r.ReferencedSchema, r.ReferencedTable}
+			neighbours[child] = append(neighbours[child], edge{r, parent})
+			if parent != child {
+				neighbours[parent] = append(neighbours[parent], edge{r, child})
+			}
+		}
+	}
+
+	// reached records, for each table reached, the relationship it was
+	// reached by and the table at the other end.
+	type hop struct {
+		relationship *Relationship
+		from         tableRef
+	}
+	reached := map[tableRef]*hop{from: nil}
+	frontier := []tableRef{from}
+	for len(frontier) > 0 {
+		if _, ok := reached[to]; ok {
+			break
+		}
+		var next []tableRef
+		for _, ref := range frontier {
+			for _, e := range neighbours[ref] {
+				if _, ok := reached[e.next]; ok {
+					continue
+				}
+				reached[e.next] = &hop{e.relationship, ref}
+				next = append(next, e.next)
+			}
+		}
+		frontier = next
+	}
+	if _, ok := reached[to]; !ok {
+		return nil, fmt.Errorf("%s.%s to %s.%s: %w", from.schema, from.name, to.schema, to.name, ErrNoJoinPath)
+	}
+
+	var steps []*JoinStep
+	for ref := to; ref != from; {
+		h := reached[ref]
+		r := h.relationship
+		step := &JoinStep{
+			Schema:     ref.schema,
+			Table:      ref.name,
+			FromSchema: h.from.schema,
+			FromTable:  h.from.name,
+			Constraint: r.Name,
+			Inferred:   r.Inferred,
+		}
+		if ref == (tableRef{r.Schema, r.Table}) {
+			step.Columns, step.FromColumns = r.Columns, r.ReferencedColumns
+		} else {
+			step.Columns, step.FromColumns = r.ReferencedColumns, r.Columns
+		}
+		steps = append([]*JoinStep{step}, steps...)
+		ref = h.from
+	}
+
+	path := &JoinPath{
+		FromSchema: from.schema,
+		FromTable:  from.name,
+		ToSchema:   to.schema,
+		ToTable:    to.name,
+		Joins:      steps,
+	}
+	path.writeSQL()
+	return path, nil
+}
+
+// hasTable reports whether ref is a table of the graph.
+func (g *RelationshipGraph) hasTable(ref tableRef) bool {
+	for _, t := range g.Tables {
+		if t.Schema == ref.schema && t.Name == ref.name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSQL fills in the SQL of the path and of each of its joins. Columns
+// are qualified by table name, or by schema and table name when two tables
+// of the path share a name.
+func (p *JoinPath) writeSQL() {
+	names := map[string]int{p.FromTable: 1}
+	for _, step := range p.Joins {
+		names[step.Table]++
+	}
+	qualifier := func(schema, table string) pgx.Identifier {
+		if names[table] > 1 {
+			return pgx.Identifier{schema, table}
+		}
+		return pgx.Identifier{table}
+	}
+
+	lines := []string{"FROM " + pgx.Identifier{p.FromSchema, p.FromTable}.Sanitize()}
+	for _, step := range p.Joins {
+		joined, previous := qualifier(step.Schema, step.Table), qualifier(step.FromSchema, step.FromTable)
+		conditions := make([]string, len(step.Columns))
+		for i := range step.Columns {
+			conditions[i] = slices.Concat(joined, pgx.Identifier{step.Columns[i]}).Sanitize() + " = " +
+				slices.Concat(previous, pgx.Identifier{step.FromColumns[i]}).Sanitize()
+		}
+		step.SQL = "JOIN " + pgx.Identifier{step.Schema, step.Table}.Sanitize() + " ON " + strings.Join(conditions, " AND ")
+		lines = append(lines, step.SQL)
+	}
+	p.SQL = strings.Join(lines, "\n")
+}
+
+// FindJoinPath returns a shortest chain of joins between two tables over the
+// foreign-key graph, optionally including the relationships inferred from
+// *_id column names. Both schemas default to DefaultSchema.
+func (a *App) FindJoinPath(ctx context.Context, opts *JoinPathOptions) (*JoinPath, error) {
+	if opts == nil || opts.FromTable == "" || opts.ToTable == "" {
+		return nil, ErrTableRequired
+	}
+
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find join path: %w", err)
+	}
+
+	from := tableRef{opts.FromSchema, opts.FromTable}
+	if from.schema == "" {
+		from.schema = DefaultSchema
+	}
+	to := tableRef{opts.ToSchema, opts.ToTable}
+	if to.schema == "" {
+		to.schema = DefaultSchema
+	}
+
+	a.logger.Debug("Finding join path", "from", from.schema+"."+from.name, "to", to.schema+"."+to.name,
+		"include_inferred", opts.IncludeInferred)
+
+	graph, err := a.client.ListRelationships(ctx)
+	if err != nil {
+		a.logger.Error("Failed to find join path", "error", err)
+		return nil, fmt.Errorf("failed to find join path: %w", err)
+	}
+
+	if opts.IncludeInferred {
+		columns, err := a.client.ListIDColumns(ctx)
+		if err != nil {
+			a.logger.Error("Failed to find join path", "error", err)
+			return nil, fmt.Errorf("failed to find join path: %w", err)
+		}
+		graph.Relationships = append(graph.Relationships, graph.inferRelationships(columns)...)
+	}
+
+	path, err := graph.joinPath(from, to)
+	if err != nil {
+		a.logger.Error("Failed to find join path", "error", err)
+		return nil, fmt.Errorf("failed to find join path: %w", err)
+	}
+
+	a.logger.Debug("Successfully found join path", "join_count", len(path.Joins))
+	return path, nil
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLClient_ListIDColumnsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	columns, err := client.ListIDColumns(context.Background())
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, columns)
+}
+
+func TestReferencedTableNames(t *testing.T) {
+	assert.Equal(t, []string{"customer", "customers"}, referencedTableNames("customer_id"))
+	assert.Equal(t, []string{"category", "categorys", "categories"}, referencedTableNames("category_id"))
+	assert.Equal(t, []string{"address", "addresses"}, referencedTableNames("address_id"))
+	assert.Equal(t, []string{"batch", "batches"}, referencedTableNames("batch_id"))
+	assert.Nil(t, referencedTableNames("_id"))
+	assert.Nil(t, referencedTableNames("identifier"))
+}
+
+func TestRelationshipGraph_InferRelationships(t *testing.T) {
+	graph := testRelationshipGraph()
+	graph.Tables = append(graph.Tables,
+		&GraphTable{Schema: "billing", Name: "customers", KeyColumns: []*KeyColumn{
+			{Name: "id", DataType: "integer", PrimaryKey: true},
+		}},
+		&GraphTable{Schema: "public", Name: "categories", KeyColumns: []*KeyColumn{
+			{Name: "code", DataType: "text", PrimaryKey: true},
+		}},
+	)
+
+	inferred := graph.inferRelationships([]*IDColumn{
+		// Same-schema table wins over billing.customers.
+		{Schema: "public", Table: "products", Name: "customer_id", DataType: "integer"},
+		// Unique name in another schema.
+		{Schema: "public", Table: "products", Name: "invoice_id", DataType: "integer"},
+		// Plural in -ies.
+		{Schema: "public", Table: "products", Name: "category_id", DataType: "text"},
+		// Type mismatch with the primary key.
+		{Schema: "public", Table: "orders", Name: "region_id", DataType: "bigint"},
+		// No such table.
+		{Schema: "public", Table: "orders", Name: "warehouse_id", DataType: "integer"},
+		// The column's own table.
+		{Schema: "public", Table: "orders", Name: "order_id", DataType: "integer"},
+	})
+
+	require.Len(t, inferred, 3)
+	assert.Equal(t, &Relationship{
+		Schema: "public", Table: "products", Columns: []string{"customer_id"},
+		ReferencedSchema: "public", ReferencedTable: "customers", ReferencedColumns: []string{"id"},
+		Inferred: true,
+	}, inferred[0])
+	assert.Equal(t, "billing", inferred[1].ReferencedSchema)
+	assert.Equal(t, "invoices", inferred[1].ReferencedTable)
+	assert.Equal(t, "categories", inferred[2].ReferencedTable)
+	assert.Equal(t, []string{"code"}, inferred[2].ReferencedColumns)
+}
+
+func TestRelationshipGraph_JoinPath(t *testing.T) {
+	graph := testRelationshipGraph()
+
+	t.Run("follows foreign keys in both directions", func(t *testing.T) {
+		path, err := graph.joinPath(tableRef{"public", "regions"}, tableRef{"billing", "invoices"})
+		require.NoError(t, err)
+		require.Len(t, path.Joins, 3)
+
+		assert.Equal(t, &JoinStep{
+			Schema: "public", Table: "customers", Columns: []string{"region_id"},
+			FromSchema: "public", FromTable: "regions", FromColumns: []string{"id"},
+			Constraint: "customers_region_id_fkey",
+			SQL:        `JOIN "public"."customers" ON "customers"."region_id" = "regions"."id"`,
+		}, path.Joins[0])
+		assert.Equal(t, "FROM \"public\".\"regions\"\n"+
+			"JOIN \"public\".\"customers\" ON \"customers\".\"region_id\" = \"regions\".\"id\"\n"+
+			"JOIN \"public\".\"orders\" ON \"orders\".\"customer_id\" = \"customers\".\"id\"\n"+
+			"JOIN \"billing\".\"invoices\" ON \"invoices\".\"id\" = \"orders\".\"invoice_id\"",
+			path.SQL)
+	})
+
+	t.Run("same table", func(t *testing.T) {
+		path, err := graph.joinPath(tableRef{"public", "orders"}, tableRef{"public", "orders"})
+		require.NoError(t, err)
+		assert.Empty(t, path.Joins)
+		assert.Equal(t, `FROM "public"."orders"`, path.SQL)
+	})
+
+	t.Run("no path", func(t *testing.T) {
+		path, err := graph.joinPath(tableRef{"public", "orders"}, tableRef{"public", "products"})
+		assert.ErrorIs(t, err, ErrNoJoinPath)
+		assert.Nil(t, path)
+	})
+
+	t.Run("unknown table", func(t *testing.T) {
+		path, err := graph.joinPath(tableRef{"public", "orders"}, tableRef{"public", "missing"})
+		assert.ErrorIs(t, err, ErrTableNotFound)
+		assert.Nil(t, path)
+	})
+}
+
+func TestRelationshipGraph_JoinPathPrefersDeclaredKeys(t *testing.T) {
+	graph := testRelationshipGraph()
+	graph.Relationships = append([]*Relationship{{
+		Schema: "public", Table: "orders", Columns: []string{"customer_ref_id"},
+		ReferencedSchema: "public", ReferencedTable: "customers", ReferencedColumns: []string{"id"},
+		Inferred: true,
+	}}, graph.Relationships...)
+
+	path, err := graph.joinPath(tableRef{"public", "customers"}, tableRef{"public", "orders"})
+	require.NoError(t, err)
+	require.Len(t, path.Joins, 1)
+	assert.Equal(t, "orders_customer_id_fkey", path.Joins[0].Constraint)
+	assert.False(t, path.Joins[0].Inferred)
+}
+
+func TestJoinPath_WriteSQLQualifiesSharedNames(t *testing.T) {
+	path := &JoinPath{
+		FromSchema: "public",
+		FromTable:  "events",
+		Joins: []*JoinStep{{
+			Schema: "audit", Table: "events", Columns: []string{"event_id"},
+			FromSchema: "public", FromTable: "events", FromColumns: []string{"id"},
+		}},
+	}
+	path.writeSQL()
+	assert.Equal(t, "FROM \"public\".\"events\"\n"+
+		"JOIN \"audit\".\"events\" ON \"audit\".\"events\".\"event_id\" = \"public\".\"events\".\"id\"", path.SQL)
+}
+
+func TestApp_FindJoinPath(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListRelationships", mock.Anything).Return(testRelationshipGraph(), nil)
+	mockClient.On("ListIDColumns", mock.Anything).Return([]*IDColumn{
+		{Schema: "public", Table: "products", Name: "order_id", DataType: "integer"},
+	}, nil)
+
+	path, err := app.FindJoinPath(context.Background(), &JoinPathOptions{FromTable: "orders", ToTable: "regions"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSchema, path.FromSchema)
+	assert.Equal(t, DefaultSchema, path.ToSchema)
+	assert.Len(t, path.Joins, 2)
+
+	_, err = app.FindJoinPath(context.Background(), &JoinPathOptions{FromTable: "products", ToTable: "regions"})
+	assert.ErrorIs(t, err, ErrNoJoinPath)
+	mockClient.AssertNotCalled(t, "ListIDColumns", mock.Anything)
+
+	path, err = app.FindJoinPath(context.Background(), &JoinPathOptions{
+		FromTable: "products", ToTable: "regions", IncludeInferred: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, path.Joins, 3)
+	assert.True(t, path.Joins[0].Inferred)
+	assert.Equal(t, `JOIN "public"."orders" ON "orders"."id" = "products"."order_id"`, path.Joins[0].SQL)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_FindJoinPathError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	path, err := app.FindJoinPath(context.Background(), &JoinPathOptions{FromTable: "orders"})
+	assert.ErrorIs(t, err, ErrTableRequired)
+	assert.Nil(t, path)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListRelationships", mock.Anything).Return(nil, errors.New("connection reset"))
+
+	path, err = app.FindJoinPath(context.Background(), &JoinPathOptions{FromTable: "orders", ToTable: "regions"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to find join path")
+	assert.Nil(t, path)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,359 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// BindParams prepares query parameters supplied as decoded JSON for the
+// driver. params may be:
+//
+//   - nil: the query is returned unchanged with no arguments;
+//   - a JSON array ([]any): bound positionally to $1, $2, …;
+//   - a JSON object (map[string]any): every :name placeholder in the query is
+//     rewritten to $n, with repeated names sharing one argument.
+//
+// Placeholders inside string literals, quoted identifiers, dollar-quoted
+// strings (including E'...' escape strings), and comments are left alone,
+// as are :: casts and the colons of array slices (arr[1:n], arr[lo:hi],
+// arr[:n]); inside a subscript a placeholder follows an operator or
+// parenthesis, e.g. arr[(:i)]. The number of arguments must match the
+// placeholders in the query exactly, so a typo in a parameter name is
+// reported instead of silently ignored. Values are coerced with CoerceParam.
+func BindParams(query string, params any) (string, []any, error) {
+	switch p := params.(type) {
+	case nil:
+		return query, nil, nil
+	case []any:
+		return bindPositional(query, p)
+	case map[string]any:
+		return bindNamed(query, p)
+	default:
+		return "", nil, fmt.Errorf("%w: params must be an array or an object, got %T", ErrInvalidParams, params)
+	}
+}
+
+func bindPositional(query string, params []any) (string, []any, error) {
+	want := 0
+	scanPlaceholders(query, func(name string, positional bool) string {
+		if positional {
+			if n, err := strconv.Atoi(name); err == nil && n > want {
+				want = n
+			}
+		}
+		return ""
+	})
+	if want != len(params) {
+		return "", nil, fmt.Errorf("%w: query uses %d positional parameters but %d were supplied",
+			ErrInvalidParams, want, len(params))
+	}
+
+	args := make([]any, len(params))
+	for i, p := range params {
+		v, err := CoerceParam(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: $%d: %w", ErrInvalidParams, i+1, err)
+		}
+		args[i] = v
+	}
+	return query, args, nil
+}
+
+func bindNamed(query string, params map[string]any) (string, []any, error) {
+	positions := make(map[string]int, len(params))
+	var args []any
+	var missing []string
+	var coerceErr error
+
+	rewritten := scanPlaceholders(query, func(name string, positional bool) string {
+		if positional {
+			return ""
+		}
+		if n, ok := positions[name]; ok {
+			return "$" + strconv.Itoa(n)
+		}
+		value, ok := params[name]
+		if !ok {
+			missing = append(missing, name)
+			return ""
+		}
+		v, err := CoerceParam(value)
+		if err != nil && coerceErr == nil {
+			coerceErr = fmt.Errorf("%w: :%s: %w", ErrInvalidParams, name, err)
+		}
+		args = append(args, v)
+		positions[name] = len(args)
+		return "$" + strconv.Itoa(len(args))
+	})
+
+	if coerceErr != nil {
+		return "", nil, coerceErr
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("%w: no value for :%s", ErrInvalidParams, strings.Join(missing, ", :"))
+	}
+	var unused []string
+	for name := range params {
+		if _, ok := positions[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("%w: query does not use :%s", ErrInvalidParams, strings.Join(unused, ", :"))
+	}
+	return rewritten, args, nil
+}
+
+// scanPlaceholders walks query, calling visit for every $n (positional) and
+// :name (named) placeholder outside literals and comments. When visit returns
+// a non-empty string the placeholder is replaced by it in the returned query.
+func scanPlaceholders(query string, visit func(name string, positional bool) string) string {
+	var result strings.Builder
+	result.Grow(len(query))
+	runes := []rune(query)
+	i := 0
+	// subscripts has one entry per open '[', true when it subscripts an
+	// array rather than opening an ARRAY[...] constructor.
+	var subscripts []bool
+
+	for i < len(runes) {
+		switch {
+		case runes[i] == '\'':
+			i = copySingleQuotedLiteral(runes, i, &result)
+		case runes[i] == '"':
+			i = copyDoubleQuotedIdentifier(runes, i, &result)
+		case i+1 < len(runes) && runes[i] == '/' && runes[i+1] == '*':
+			end := skipBlockComment(runes, i)
+			result.WriteString(string(runes[i:end]))
+			i = end
+		case i+1 < len(runes) && runes[i] == '-' && runes[i+1] == '-':
+			end := skipLineComment(runes, i)
+			result.WriteString(string(runes[i:end]))
+			i = end
+		case runes[i] == '$':
+			i = scanDollar(runes, i, &result, visit)
+		case runes[i] == '[':
+			subscripts = append(subscripts, opensSubscript(runes, i))
+			result.WriteRune(runes[i])
+			i++
+		case runes[i] == ']':
+			if n := len(subscripts); n > 0 {
+				subscripts = subscripts[:n-1]
+			}
+			result.WriteRune(runes[i])
+			i++
+		case runes[i] == ':' && len(subscripts) > 0 && subscripts[len(subscripts)-1] && isSliceColon(runes, i):
+			result.WriteRune(runes[i])
+			i++
+		case runes[i] == ':':
+			i = scanColon(runes, i, &result, visit)
+		default:
+			result.WriteRune(runes[i])
+			i++
+		}
+	}
+	return result.String()
+}
+
+// scanDollar handles a '$' at runes[start]: either a $n placeholder or the
+// opening tag of a dollar-quoted string, which is copied through verbatim.
+func scanDollar(runes []rune, start int, result *strings.Builder, visit func(string, bool) string) int {
+	j := start + 1
+	for j < len(runes) && isDigit(runes[j]) {
+		j++
+	}
+	if j > start+1 {
+		if repl := visit(string(runes[start+1:j]), true); repl != "" {
+			result.WriteString(repl)
+		} else {
+			result.WriteString(string(runes[start:j]))
+		}
+		return j
+	}
+
+	// Dollar-quote tag: $$ or $tag$ where tag is an identifier.
+	j = start + 1
+	for j < len(runes) && isIdentRune(runes[j], j == start+1) {
+		j++
+	}
+	if j >= len(runes) || runes[j] != '$' {
+		result.WriteRune(runes[start])
+		return start + 1
+	}
+	tag := string(runes[start : j+1])
+	body := string(runes[j+1:])
+	end := strings.Index(body, tag)
+	if end < 0 {
+		result.WriteString(string(runes[start:]))
+		return len(runes)
+	}
+	closing := j + 1 + len([]rune(body[:end])) + len([]rune(tag))
+	result.WriteString(string(runes[start:closing]))
+	return closing
+}
+
+// scanColon handles a ':' at runes[start]: either a :name placeholder or
+// a :: cast. Slice colons are recognized by scanPlaceholders beforehand.
+func scanColon(runes []rune, start int, result *strings.Builder, visit func(string, bool) string) int {
+	if start+1 < len(runes) && runes[start+1] == ':' {
+		result.WriteString("::")
+		return start + 2 //nolint:mnd // length of "::"
+	}
+	j := start + 1
+	for j < len(runes) && isIdentRune(runes[j], j == start+1) {
+		j++
+	}
+	if j == start+1 {
+		result.WriteRune(runes[start])
+		return start + 1
+	}
+	if repl := visit(string(runes[start+1:j]), false); repl != "" {
+		result.WriteString(repl)
+	} else {
+		result.WriteString(string(runes[start:j]))
+	}
+	return j
+}
+
+// opensSubscript reports whether the '[' at runes[i] subscripts an array,
+// as in arr[1], f(x)[1], or a[1][2], rather than opening an ARRAY[...]
+// constructor or one of its nested dimensions.
+func opensSubscript(runes []rune, i int) bool {
+	j := prevNonSpace(runes, i)
+	if j < 0 {
+		return false
+	}
+	switch r := runes[j]; {
+	case r == ')', r == ']', r == '"':
+		return true
+	case isIdentRune(r, false):
+		k := j
+		for k >= 0 && isIdentRune(runes[k], false) {
+			k--
+		}
+		return !strings.EqualFold(string(runes[k+1:j+1]), "array")
+	}
+	return false
+}
+
+// isSliceColon reports whether the ':' at runes[i], inside a subscript,
+// separates the bounds of a slice: it is not part of a :: cast and follows
+// the opening '[' or a lower bound ending in a name, a digit, or a bracket.
+func isSliceColon(runes []rune, i int) bool {
+	if i+1 < len(runes) && runes[i+1] == ':' {
+		return false
+	}
+	j := prevNonSpace(runes, i)
+	if j < 0 {
+		return false
+	}
+	r := runes[j]
+	return r == '[' || r == ')' || r == ']' || r == '"' || isIdentRune(r, false)
+}
+
+// prevNonSpace returns the index of the last non-space rune before i, or -1.
+func prevNonSpace(runes []rune, i int) int {
+	j := i - 1
+	for j >= 0 && unicode.IsSpace(runes[j]) {
+		j--
+	}
+	return j
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+// isIdentRune reports whether r may appear in an unquoted identifier; digits
+// are not allowed in the first position.
+func isIdentRune(r rune, first bool) bool {
+	switch {
+	case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return true
+	case isDigit(r):
+		return !first
+	}
+	return false
+}
+
+// CoerceParam converts a decoded JSON value into a driver argument that
+// PostgreSQL can cast to the parameter's inferred type:
+//
+//   - null, booleans, and strings are passed through;
+//   - integral numbers become int64, other numbers float64;
+//   - arrays become PostgreSQL array literals ('{1,2,NULL}'), so they bind to
+//     any array-typed parameter;
+//   - objects become JSON text, so they bind to json/jsonb parameters.
+func CoerceParam(v any) (any, error) {
+	switch val := v.(type) {
+	case nil, bool, string, int, int32, int64:
+		return val, nil
+	case float64:
+		return coerceNumber(val), nil
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n, nil
+		}
+		return val.String(), nil
+	case []any:
+		return arrayLiteral(val)
+	case map[string]any:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode object parameter: %w", err)
+		}
+		return string(b), nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %T", v)
+	}
+}
+
+// coerceNumber keeps whole numbers integral so they bind to integer columns
+// (PostgreSQL will not implicitly cast 1.0 to int).
+func coerceNumber(f float64) any {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return f
+}
+
+// arrayLiteral renders a JSON array as a PostgreSQL array literal. Nested
+// arrays become multi-dimensional arrays; elements are always quoted so no
+// value can be mistaken for NULL or break the literal.
+func arrayLiteral(values []any) (string, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		switch val := v.(type) {
+		case nil:
+			b.WriteString("NULL")
+		case []any:
+			inner, err := arrayLiteral(val)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(inner)
+		default:
+			elem, err := CoerceParam(val)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(quoteArrayElement(fmt.Sprint(elem)))
+		}
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+func quoteArrayElement(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindParams_Nil(t *testing.T) {
+	query, args, err := BindParams("SELECT 1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", query)
+	assert.Nil(t, args)
+}
+
+func TestBindParams_Positional(t *testing.T) {
+	query, args, err := BindParams("SELECT * FROM users WHERE id = $1 AND name = $2 OR id = $1",
+		[]any{float64(7), "Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1 AND name = $2 OR id = $1", query)
+	assert.Equal(t, []any{int64(7), "Alice"}, args)
+}
+
+func TestBindParams_PositionalCountMismatch(t *testing.T) {
+	_, _, err := BindParams("SELECT $1, $2", []any{1})
+	assert.ErrorIs(t, err, ErrInvalidParams)
+
+	_, _, err = BindParams("SELECT '$1'", []any{1})
+	assert.ErrorIs(t, err, ErrInvalidParams, "placeholders inside literals do not count")
+}
+
+func TestBindParams_Named(t *testing.T) {
+	query, args, err := BindParams(
+		"SELECT id::text FROM users WHERE name = :name AND id > :min_id OR name = :name",
+		map[string]any{"name": "Bob", "min_id": float64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id::text FROM users WHERE name = $1 AND id > $2 OR name = $1", query)
+	assert.Equal(t, []any{"Bob", int64(3)}, args)
+}
+
+func TestBindParams_NamedIgnoresLiteralsAndComments(t *testing.T) {
+	in := "SELECT ':skip', \":ident\", $tag$ :dollar $tag$, $$ :also $$ -- :line\n" +
+		"/* :block */ FROM t WHERE a = :a AND b = arr[1:2]"
+	query, args, err := BindParams(in, map[string]any{"a": true})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT ':skip', \":ident\", $tag$ :dollar $tag$, $$ :also $$ -- :line\n"+
+		"/* :block */ FROM t WHERE a = $1 AND b = arr[1:2]", query)
+	assert.Equal(t, []any{true}, args)
+}
+
+func TestBindParams_NamedIgnoresSlices(t *testing.T) {
+	in := "SELECT arr[1:n], arr[lo:hi], arr[:n], arr[ 2 : n ], m[1][i:j], f(x)[2:n], arr[x::int:n] FROM t " +
+		"WHERE arr[1: :n] = ARRAY[:a, :b] AND arr[(:i)] = :v"
+	query, args, err := BindParams(in, map[string]any{"n": 1, "a": 2, "b": 3, "i": 4, "v": 5})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT arr[1:n], arr[lo:hi], arr[:n], arr[ 2 : n ], m[1][i:j], f(x)[2:n], arr[x::int:n] FROM t "+
+		"WHERE arr[1: $1] = ARRAY[$2, $3] AND arr[($4)] = $5", query)
+	assert.Equal(t, []any{1, 2, 3, 4, 5}, args)
+}
+
+func TestBindParams_NamedIgnoresEscapeStrings(t *testing.T) {
+	in := `SELECT E'it\'s :x', e'\\', :a, 'plain\', :b, WHERE'z' = :c`
+	query, args, err := BindParams(in, map[string]any{"a": 1, "b": 2, "c": 3})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT E'it\'s :x', e'\\', $1, 'plain\', $2, WHERE'z' = $3`, query)
+	assert.Equal(t, []any{1, 2, 3}, args)
+}
+
+func TestBindParams_NamedErrors(t *testing.T) {
+	_, _, err := BindParams("SELECT :a, :b", map[string]any{"a": 1})
+	require.ErrorIs(t, err, ErrInvalidParams)
+	assert.Contains(t, err.Error(), ":b")
+
+	_, _, err = BindParams("SELECT :a", map[string]any{"a": 1, "typo": 2})
+	require.ErrorIs(t, err, ErrInvalidParams)
+	assert.Contains(t, err.Error(), ":typo")
+
+	_, _, err = BindParams("SELECT :a", map[string]any{"a": struct{}{}})
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestBindParams_RejectsScalars(t *testing.T) {
+	_, _, err := BindParams("SELECT $1", "nope")
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestCoerceParam(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want any
+	}{
+		{"null", nil, nil},
+		{"bool", false, false},
+		{"string", "x", "x"},
+		{"integral float", float64(42), int64(42)},
+		{"fractional float", 1.25, 1.25},
+		{"array", []any{float64(1), nil, "a\"b", true}, `{"1",NULL,"a\"b","true"}`},
+		{"nested array", []any{[]any{float64(1), float64(2)}, []any{float64(3), float64(4)}}, `{{"1","2"},{"3","4"}}`},
+		{"object", map[string]any{"k": []any{float64(1)}}, `{"k":[1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceParam(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
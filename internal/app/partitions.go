@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Partition strategies reported in PartitionTree.Strategy and
+// PartitionInfo.Strategy.
+const (
+	PartitionStrategyRange = "range"
+	PartitionStrategyList  = "list"
+	PartitionStrategyHash  = "hash"
+)
+
+// PartitionTree describes a partitioned table and every partition below it.
+// Key is the partition key as written in PARTITION BY (e.g. "created_at" or
+// "date_trunc('day', ts)"). RowEstimate and Size are totals over the leaf
+// partitions; DefaultPartition names the table's DEFAULT partition, if any.
+type PartitionTree struct {
+	Schema           string           `json:"schema"`
+	Name             string           `json:"name"`
+	Strategy         string           `json:"strategy"`
+	Key              string           `json:"key"`
+	DefaultPartition string           `json:"default_partition,omitempty"`
+	RowEstimate      int64            `json:"row_estimate"`
+	Size             string           `json:"size"`
+	Partitions       []*PartitionInfo `json:"partitions"`
+}
+
+// PartitionInfo describes one partition in a PartitionTree. Name and Parent
+// are schema-qualified and quoted where needed; Level is 1 for direct
+// partitions of the root. Bound is the FOR VALUES clause (or "DEFAULT").
+// Strategy and Key are set only for sub-partitioned partitions. RowEstimate
+// comes from pg_class.reltuples, summed over leaves for sub-partitioned
+// partitions, and is 0 until the partition has been analyzed.
+type PartitionInfo struct {
+	Name        string `json:"name"`
+	Parent      string `json:"parent"`
+	Level       int    `json:"level"`
+	IsLeaf      bool   `json:"is_leaf"`
+	IsDefault   bool   `json:"is_default"`
+	Bound       string `json:"bound"`
+	Strategy    string `json:"strategy,omitempty"`
+	Key         string `json:"key,omitempty"`
+	RowEstimate int64  `json:"row_estimate"`
+	Size        string `json:"size"`
+}
+
+// ResultSections renders the partitions as a table for the non-JSON result
+// formats; each row carries its parent, so the hierarchy is preserved.
+func (t *PartitionTree) ResultSections() []ResultSection {
+	return []ResultSection{{Name: "partitions", Records: t.Partitions}}
+}
+
+// partitionStrategies maps pg_partitioned_table.partstrat to a strategy name.
+var partitionStrategies = map[string]string{
+	"r": PartitionStrategyRange,
+	"l": PartitionStrategyList,
+	"h": PartitionStrategyHash,
+}
+
+// partitionTreeQuery lists the root (level 0) and every partition below it.
+// Row estimates and sizes are summed over each node's own leaves so that
+// intermediate, sub-partitioned levels report their totals too.
+// pg_get_partkeydef returns "RANGE (col)"; the strategy word and outer
+// parentheses are stripped since the strategy is reported separately.
+const partitionTreeQuery = `
+	SELECT
+		format('%I.%I', n.nspname, c.relname) AS name,
+		CASE WHEN pc.oid IS NULL THEN '' ELSE format('%I.%I', pn.nspname, pc.relname) END AS parent,
+		pt.level,
+		pt.isleaf,
+		COALESCE(pg_get_expr(c.relpartbound, c.oid), '') AS bound,
+		COALESCE(p.partstrat::text, '') AS strategy,
+		CASE WHEN c.relkind = 'p'
+			THEN regexp_replace(pg_get_partkeydef(c.oid), '^\w+ \((.*)\)$', '\1')
+			ELSE ''
+		END AS key,
+		(
+			SELECT COALESCE(SUM(GREATEST(lc.reltuples::bigint, 0)), 0)::bigint
+			FROM pg_partition_tree(c.oid) lt
+			JOIN pg_class lc ON lc.oid = lt.relid
+			WHERE lt.isleaf
+		) AS row_estimate,
+		pg_size_pretty((
+			SELECT COALESCE(SUM(pg_total_relation_size(lt.relid)), 0)::bigint
+			FROM pg_partition_tree(c.oid) lt
+			WHERE lt.isleaf
+		)) AS size
+	FROM pg_partition_tree($1::oid::regclass) pt
+	JOIN pg_class c ON c.oid = pt.relid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_class pc ON pc.oid = pt.parentrelid
+	LEFT JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+	LEFT JOIN pg_partitioned_table p ON p.partrelid = c.oid
+	ORDER BY pt.level, c.relname`
+
+// DescribePartitions returns the partition hierarchy of a partitioned table.
+func (c *PostgreSQLClientImpl) DescribePartitions(ctx context.Context, schema, table string) (*PartitionTree, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	var relkind string
+	var oid uint32
+	err := db.QueryRowContext(ctx, `
+		SELECT c.oid, c.relkind::text
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`, schema, table).Scan(&oid, &relkind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe partitions: %w", err)
+	}
+	if relkind != "p" {
+		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrNotPartitioned)
+	}
+
+	rows, err := db.QueryContext(ctx, partitionTreeQuery, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe partitions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tree := &PartitionTree{Schema: schema, Name: table, Partitions: []*PartitionInfo{}}
+	for rows.Next() {
+		var partition PartitionInfo
+		var strategy string
+		if err := rows.Scan(
+			&partition.Name,
+			&partition.Parent,
+			&partition.Level,
+			&partition.IsLeaf,
+			&partition.Bound,
+			&strategy,
+			&partition.Key,
+			&partition.RowEstimate,
+			&partition.Size,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan partition row: %w", err)
+		}
+		partition.Strategy = partitionStrategies[strategy]
+		partition.IsDefault = partition.Bound == "DEFAULT"
+
+		if partition.Level == 0 {
+			tree.Strategy = partition.Strategy
+			tree.Key = partition.Key
+			tree.RowEstimate = partition.RowEstimate
+			tree.Size = partition.Size
+			continue
+		}
+		if partition.IsDefault && partition.Level == 1 {
+			tree.DefaultPartition = partition.Name
+		}
+		tree.Partitions = append(tree.Partitions, &partition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate partition rows: %w", err)
+	}
+	return tree, nil
+}
+
+// DescribePartitions returns the partition tree of a partitioned table:
+// strategy and key, and each partition's bound, row estimate, and size.
+func (a *App) DescribePartitions(ctx context.Context, schema, table string) (*PartitionTree, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to describe partitions: %w", err)
+	}
+
+	if table == "" {
+		return nil, ErrTableRequired
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Describing partitions", "schema", schema, "table", table)
+
+	tree, err := a.client.DescribePartitions(ctx, schema, table)
+	if err != nil {
+		a.logger.Error("Failed to describe partitions", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to describe partitions: %w", err)
+	}
+
+	a.logger.Debug("Successfully described partitions", "partition_count", len(tree.Partitions),
+		"schema", schema, "table", table)
+	return tree, nil
+}
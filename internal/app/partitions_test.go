@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_DescribePartitionsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	tree, err := client.DescribePartitions(context.Background(), "public", "events")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, tree)
+}
+
+func TestApp_DescribePartitions(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := &PartitionTree{
+		Schema:   DefaultSchema,
+		Name:     "events",
+		Strategy: PartitionStrategyRange,
+		Key:      "happened",
+		Partitions: []*PartitionInfo{
+			{Name: "public.events_2024", Parent: "public.events", Level: 1, IsLeaf: true},
+		},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("DescribePartitions", mock.Anything, DefaultSchema, "events").Return(expected, nil)
+
+	tree, err := app.DescribePartitions(context.Background(), "", "events")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, tree)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_DescribePartitionsErrors(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("DescribePartitions", mock.Anything, "public", "users").
+		Return(nil, errors.Join(errors.New("table public.users"), ErrNotPartitioned))
+
+	_, err := app.DescribePartitions(context.Background(), "public", "")
+	assert.ErrorIs(t, err, ErrTableRequired)
+
+	tree, err := app.DescribePartitions(context.Background(), "public", "users")
+	assert.ErrorIs(t, err, ErrNotPartitioned)
+	assert.Nil(t, tree)
+	mockClient.AssertExpectations(t)
+}
+
+func TestPartitionTree_ResultSections(t *testing.T) {
+	tree := &PartitionTree{Partitions: []*PartitionInfo{
+		{Name: "public.events_2024", Parent: "public.events", Level: 1, IsLeaf: true, Bound: "DEFAULT", IsDefault: true},
+	}}
+	got, err := EncodeSections(FormatCSV, tree.ResultSections())
+	assert.NoError(t, err)
+	assert.Equal(t, "## partitions\n\n"+
+		"name,parent,level,is_leaf,is_default,bound,strategy,key,row_estimate,size\n"+
+		"public.events_2024,public.events,1,true,true,DEFAULT,,,0,\n", got)
+}
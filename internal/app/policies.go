@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TableSecurity reports the row-level security state of a table. With
+// RLSEnabled and no Policies, the table denies every row to roles other
+// than its owner (and roles with BYPASSRLS); RLSForced applies the policies
+// to the owner too.
+type TableSecurity struct {
+	Schema     string        `json:"schema"`
+	Table      string        `json:"table"`
+	RLSEnabled bool          `json:"rls_enabled"`
+	RLSForced  bool          `json:"rls_forced"`
+	Policies   []*PolicyInfo `json:"policies"`
+}
+
+// PolicyInfo describes a row-level security policy. Command is "ALL",
+// "SELECT", "INSERT", "UPDATE", or "DELETE"; Roles is {"public"} for
+// policies that apply to every role. Permissive policies are combined with
+// OR, restrictive ones with AND. Using filters the rows a command sees and
+// WithCheck the rows it may write.
+type PolicyInfo struct {
+	Name       string   `json:"name"`
+	Command    string   `json:"command"`
+	Permissive bool     `json:"permissive"`
+	Roles      []string `json:"roles"`
+	Using      string   `json:"using,omitempty"`
+	WithCheck  string   `json:"with_check,omitempty"`
+}
+
+// listPoliciesQuery lists the tables of a schema (or one of them) that
+// have row-level security enabled or at least one policy, with their
+// policies from pg_policies; tables without policies yield one row with a
+// NULL policy name.
+const listPoliciesQuery = `
+	SELECT
+		c.relname,
+		c.relrowsecurity,
+		c.relforcerowsecurity,
+		p.policyname,
+		COALESCE(p.cmd, ''),
+		COALESCE(p.permissive = 'PERMISSIVE', false),
+		COALESCE(p.roles::text[], '{}'),
+		COALESCE(p.qual, ''),
+		COALESCE(p.with_check, '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_policies p ON p.schemaname = n.nspname AND p.tablename = c.relname
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2) AND c.relkind IN ('r', 'p')
+		AND (c.relrowsecurity OR p.policyname IS NOT NULL)
+	ORDER BY c.relname, p.policyname`
+
+// ListPolicies returns the row-level security state and policies of the
+// tables of a schema, or of one table when table is not empty. Tables
+// without row-level security or policies are omitted.
+func (c *PostgreSQLClientImpl) ListPolicies(ctx context.Context, schema, table string) ([]*TableSecurity, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, listPoliciesQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	tables := []*TableSecurity{}
+	var current *TableSecurity
+	for rows.Next() {
+		var security TableSecurity
+		var name sql.NullString
+		var policy PolicyInfo
+		if err := rows.Scan(
+			&security.Table,
+			&security.RLSEnabled,
+			&security.RLSForced,
+			&name,
+			&policy.Command,
+			&policy.Permissive,
+			typeMap.SQLScanner(&policy.Roles),
+			&policy.Using,
+			&policy.WithCheck,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan policy row: %w", err)
+		}
+
+		if current == nil || current.Table != security.Table {
+			security.Schema = schema
+			security.Policies = []*PolicyInfo{}
+			current = &security
+			tables = append(tables, current)
+		}
+		if name.Valid {
+			policy.Name = name.String
+			current.Policies = append(current.Policies, &policy)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate policy rows: %w", err)
+	}
+	return tables, nil
+}
+
+// ListPolicies returns whether row-level security is enabled and forced on
+// the tables of a schema (or one table), and their policies.
+func (a *App) ListPolicies(ctx context.Context, schema, table string) ([]*TableSecurity, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Listing policies", "schema", schema, "table", table)
+
+	tables, err := a.client.ListPolicies(ctx, schema, table)
+	if err != nil {
+		a.logger.Error("Failed to list policies", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	a.logger.Debug("Successfully listed policies", "table_count", len(tables), "schema", schema, "table", table)
+	return tables, nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_ListPoliciesWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	tables, err := client.ListPolicies(context.Background(), "public", "")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, tables)
+}
+
+func TestApp_ListPolicies(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*TableSecurity{
+		{
+			Schema:     DefaultSchema,
+			Table:      "documents",
+			RLSEnabled: true,
+			Policies: []*PolicyInfo{{
+				Name:       "owner_only",
+				Command:    "ALL",
+				Permissive: true,
+				Roles:      []string{"public"},
+				Using:      "(owner = CURRENT_USER)",
+			}},
+		},
+		{Schema: DefaultSchema, Table: "secrets", RLSEnabled: true, RLSForced: true, Policies: []*PolicyInfo{}},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListPolicies", mock.Anything, DefaultSchema, "").Return(expected, nil)
+
+	tables, err := app.ListPolicies(context.Background(), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, tables)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListPoliciesError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListPolicies", mock.Anything, "public", "documents").Return(nil, errors.New("connection reset"))
+
+	tables, err := app.ListPolicies(context.Background(), "public", "documents")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list policies")
+	assert.Nil(t, tables)
+	mockClient.AssertExpectations(t)
+}
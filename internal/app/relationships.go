@@ -0,0 +1,335 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Depth limits of GetRelationships around a table.
+const (
+	DefaultRelationshipDepth = 1
+	MaxRelationshipDepth     = 10
+)
+
+// RelationshipGraph is the foreign-key graph of a schema, or the part of it
+// within Depth hops of Table. Tables are the nodes, with the columns that
+// take part in a primary or foreign key; Relationships are the edges, from
+// the referencing table to the referenced one. A schema's graph also holds
+// the tables of other schemas that its foreign keys reach or come from.
+type RelationshipGraph struct {
+	Schema        string          `json:"schema"`
+	Table         string          `json:"table,omitempty"`
+	Depth         int             `json:"depth,omitempty"`
+	Tables        []*GraphTable   `json:"tables"`
+	Relationships []*Relationship `json:"relationships"`
+}
+
+// GraphTable is a table of a RelationshipGraph.
+type GraphTable struct {
+	Schema     string       `json:"schema"`
+	Name       string       `json:"name"`
+	KeyColumns []*KeyColumn `json:"key_columns"`
+}
+
+// KeyColumn is a column of a primary key, a foreign key, or both.
+type KeyColumn struct {
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	PrimaryKey bool   `json:"primary_key"`
+	ForeignKey bool   `json:"foreign_key"`
+}
+
+// Relationship is a foreign key. Columns pair up with ReferencedColumns.
+// Optional is true when a referencing column is nullable, so a row may have
+// no referenced row; OneToOne when a unique index covers the referencing
+// columns, so each referenced row has at most one referencing row.
+// Inferred relationships are not declared foreign keys but guessed from
+// column names; they have no Name or actions.
+type Relationship struct {
+	Name              string   `json:"name"`
+	Schema            string   `json:"schema"`
+	Table             string   `json:"table"`
+	Columns           []string `json:"columns"`
+	ReferencedSchema  string   `json:"referenced_schema"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+	OnUpdate          string   `json:"on_update"`
+	OnDelete          string   `json:"on_delete"`
+	Optional          bool     `json:"optional"`
+	OneToOne          bool     `json:"one_to_one"`
+	Inferred          bool     `json:"inferred,omitempty"`
+}
+
+// ResultSections renders the tables and relationships as two tables for
+// the non-JSON result formats.
+func (g *RelationshipGraph) ResultSections() []ResultSection {
+	return []ResultSection{
+		{Name: "tables", Records: g.Tables},
+		{Name: "relationships", Records: g.Relationships},
+	}
+}
+
+// tableRef identifies a table of the graph.
+type tableRef struct {
+	schema string
+	name   string
+}
+
+// relationshipTablesQuery lists the tables of every user schema with their
+// primary and foreign key columns, one row per column (or a single row with
+// an empty column for tables without keys). Partitions are left out: their
+// keys are those of the partitioned table.
+const relationshipTablesQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		COALESCE(a.attname::text, ''),
+		COALESCE(format_type(a.atttypid, a.atttypmod), ''),
+		COALESCE(bool_or(con.contype = 'p'), false),
+		COALESCE(bool_or(con.contype = 'f'), false)
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_constraint con ON con.conrelid = c.oid AND con.contype IN ('p', 'f')
+	LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(con.conkey)
+	WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition
+		AND n.nspname NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
+		AND n.nspname NOT LIKE 'pg_temp%' AND n.nspname NOT LIKE 'pg_toast_temp%'
+	GROUP BY n.nspname, c.relname, a.attnum, a.attname, a.atttypid, a.atttypmod
+	ORDER BY n.nspname, c.relname, a.attnum`
+
+// relationshipsQuery lists the foreign keys of every user schema, skipping
+// those cloned into partitions. A foreign key is one-to-one when a unique,
+// non-partial index has all its key columns among the referencing columns.
+const relationshipsQuery = `
+	SELECT
+		con.conname,
+		n.nspname,
+		c.relname,
+		ARRAY(
+			SELECT a.attname::text
+			FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			ORDER BY k.ord
+		),
+		fn.nspname,
+		fc.relname,
+		ARRAY(
+			SELECT a.attname::text
+			FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+			ORDER BY k.ord
+		),
+		con.confupdtype::text,
+		con.confdeltype::text,
+		EXISTS (
+			SELECT 1 FROM pg_attribute a
+			WHERE a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey) AND NOT a.attnotnull
+		),
+		EXISTS (
+			SELECT 1 FROM pg_index i
+			WHERE i.indrelid = con.conrelid AND i.indisunique AND i.indpred IS NULL
+				AND con.conkey @> ARRAY(
+					SELECT u.attnum FROM unnest(i.indkey::int2[]) WITH ORDINALITY AS u(attnum, ord)
+					WHERE u.ord <= i.indnkeyatts
+				)
+		)
+	FROM pg_constraint con
+	JOIN pg_class c ON c.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_class fc ON fc.oid = con.confrelid
+	JOIN pg_namespace fn ON fn.oid = fc.relnamespace
+	WHERE con.contype = 'f' AND con.conparentid = 0
+		AND n.nspname NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
+		AND n.nspname NOT LIKE 'pg_temp%' AND n.nspname NOT LIKE 'pg_toast_temp%'
+	ORDER BY n.nspname, c.relname, con.conname`
+
+// ListRelationships returns the foreign-key graph of the whole database:
+// every table of the user schemas and every foreign key between them.
+func (c *PostgreSQLClientImpl) ListRelationships(ctx context.Context) (*RelationshipGraph, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	graph := &RelationshipGraph{Tables: []*GraphTable{}, Relationships: []*Relationship{}}
+
+	rows, err := db.QueryContext(ctx, relationshipTablesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationship tables: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var current *GraphTable
+	for rows.Next() {
+		var table GraphTable
+		var column KeyColumn
+		if err := rows.Scan(
+			&table.Schema,
+			&table.Name,
+			&column.Name,
+			&column.DataType,
+			&column.PrimaryKey,
+			&column.ForeignKey,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan relationship table row: %w", err)
+		}
+
+		if current == nil || current.Schema != table.Schema || current.Name != table.Name {
+			table.KeyColumns = []*KeyColumn{}
+			current = &table
+			graph.Tables = append(graph.Tables, current)
+		}
+		if column.Name != "" {
+			current.KeyColumns = append(current.KeyColumns, &column)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate relationship table rows: %w", err)
+	}
+
+	fkRows, err := db.QueryContext(ctx, relationshipsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", err)
+	}
+	defer func() { _ = fkRows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	for fkRows.Next() {
+		var relationship Relationship
+		var onUpdate, onDelete string
+		if err := fkRows.Scan(
+			&relationship.Name,
+			&relationship.Schema,
+			&relationship.Table,
+			typeMap.SQLScanner(&relationship.Columns),
+			&relationship.ReferencedSchema,
+			&relationship.ReferencedTable,
+			typeMap.SQLScanner(&relationship.ReferencedColumns),
+			&onUpdate,
+			&onDelete,
+			&relationship.Optional,
+			&relationship.OneToOne,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan relationship row: %w", err)
+		}
+		relationship.OnUpdate = foreignKeyActions[onUpdate]
+		relationship.OnDelete = foreignKeyActions[onDelete]
+		graph.Relationships = append(graph.Relationships, &relationship)
+	}
+
+	if err := fkRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate relationship rows: %w", err)
+	}
+	return graph, nil
+}
+
+// subgraph returns the part of a database-wide graph that concerns a
+// schema: its tables, the foreign keys from or to them, and the tables at
+// the other end. With a table, it returns instead the tables within depth
+// foreign-key hops of it, in either direction, and the foreign keys between
+// them.
+func (g *RelationshipGraph) subgraph(schema, table string, depth int) (*RelationshipGraph, error) {
+	result := &RelationshipGraph{Schema: schema, Table: table, Tables: []*GraphTable{}, Relationships: []*Relationship{}}
+	included := make(map[tableRef]bool)
+
+	if table == "" {
+		for _, t := range g.Tables {
+			if t.Schema == schema {
+				included[tableRef{t.Schema, t.Name}] = true
+			}
+		}
+		for _, r := range g.Relationships {
+			if r.Schema == schema || r.ReferencedSchema == schema {
+				included[tableRef{r.Schema, r.Table}] = true
+				included[tableRef{r.ReferencedSchema, r.ReferencedTable}] = true
+				result.Relationships = append(result.Relationships, r)
+			}
+		}
+	} else {
+		start := tableRef{schema, table}
+		found := false
+		for _, t := range g.Tables {
+			if t.Schema == schema && t.Name == table {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+		}
+
+		neighbours := make(map[tableRef][]tableRef)
+		for _, r := range g.Relationships {
+			from, to := tableRef{r.Schema, r.Table}, tableRef{r.ReferencedSchema, r.ReferencedTable}
+			neighbours[from] = append(neighbours[from], to)
+			neighbours[to] = append(neighbours[to], from)
+		}
+
+		included[start] = true
+		frontier := []tableRef{start}
+		for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+			var next []tableRef
+			for _, ref := range frontier {
+				for _, neighbour := range neighbours[ref] {
+					if !included[neighbour] {
+						included[neighbour] = true
+						next = append(next, neighbour)
+					}
+				}
+			}
+			frontier = next
+		}
+
+		for _, r := range g.Relationships {
+			if included[tableRef{r.Schema, r.Table}] && included[tableRef{r.ReferencedSchema, r.ReferencedTable}] {
+				result.Relationships = append(result.Relationships, r)
+			}
+		}
+		result.Depth = depth
+	}
+
+	for _, t := range g.Tables {
+		if included[tableRef{t.Schema, t.Name}] {
+			result.Tables = append(result.Tables, t)
+		}
+	}
+	return result, nil
+}
+
+// GetRelationships returns the foreign-key graph of a schema, or of the
+// tables within depth hops of table when table is not empty. depth defaults
+// to DefaultRelationshipDepth and is capped at MaxRelationshipDepth.
+func (a *App) GetRelationships(ctx context.Context, schema, table string, depth int) (*RelationshipGraph, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+	if depth <= 0 {
+		depth = DefaultRelationshipDepth
+	}
+	depth = min(depth, MaxRelationshipDepth)
+
+	a.logger.Debug("Getting relationships", "schema", schema, "table", table, "depth", depth)
+
+	all, err := a.client.ListRelationships(ctx)
+	if err != nil {
+		a.logger.Error("Failed to get relationships", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+
+	graph, err := all.subgraph(schema, table, depth)
+	if err != nil {
+		a.logger.Error("Failed to get relationships", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+
+	a.logger.Debug("Successfully retrieved relationships",
+		"table_count", len(graph.Tables), "relationship_count", len(graph.Relationships), "schema", schema, "table", table)
+	return graph, nil
+}
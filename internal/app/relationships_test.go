@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testRelationshipGraph is a small database graph:
+//
+//	regions <- customers <- orders -> billing.invoices
+//	products (no foreign keys)
+func testRelationshipGraph() *RelationshipGraph {
+	return &RelationshipGraph{
+		Tables: []*GraphTable{
+			{Schema: "billing", Name: "invoices", KeyColumns: []*KeyColumn{
+				{Name: "id", DataType: "integer", PrimaryKey: true},
+			}},
+			{Schema: "public", Name: "customers", KeyColumns: []*KeyColumn{
+				{Name: "id", DataType: "integer", PrimaryKey: true},
+				{Name: "region_id", DataType: "integer", ForeignKey: true},
+			}},
+			{Schema: "public", Name: "orders", KeyColumns: []*KeyColumn{
+				{Name: "id", DataType: "integer", PrimaryKey: true},
+				{Name: "customer_id", DataType: "integer", ForeignKey: true},
+				{Name: "invoice_id", DataType: "integer", ForeignKey: true},
+			}},
+			{Schema: "public", Name: "products", KeyColumns: []*KeyColumn{}},
+			{Schema: "public", Name: "regions", KeyColumns: []*KeyColumn{
+				{Name: "id", DataType: "integer", PrimaryKey: true},
+			}},
+		},
+		Relationships: []*Relationship{
+			{
+				Name: "customers_region_id_fkey", Schema: "public", Table: "customers", Columns: []string{"region_id"},
+				ReferencedSchema: "public", ReferencedTable: "regions", ReferencedColumns: []string{"id"},
+				OnUpdate: "no action", OnDelete: "no action", Optional: true,
+			},
+			{
+				Name: "orders_customer_id_fkey", Schema: "public", Table: "orders", Columns: []string{"customer_id"},
+				ReferencedSchema: "public", ReferencedTable: "customers", ReferencedColumns: []string{"id"},
+				OnUpdate: "no action", OnDelete: "cascade",
+			},
+			{
+				Name: "orders_invoice_id_fkey", Schema: "public", Table: "orders", Columns: []string{"invoice_id"},
+				ReferencedSchema: "billing", ReferencedTable: "invoices", ReferencedColumns: []string{"id"},
+				OnUpdate: "no action", OnDelete: "no action", OneToOne: true,
+			},
+		},
+	}
+}
+
+func tableNames(g *RelationshipGraph) []string {
+	names := make([]string, len(g.Tables))
+	for i, t := range g.Tables {
+		names[i] = t.Schema + "." + t.Name
+	}
+	return names
+}
+
+func relationshipNames(g *RelationshipGraph) []string {
+	names := make([]string, len(g.Relationships))
+	for i, r := range g.Relationships {
+		names[i] = r.Name
+	}
+	return names
+}
+
+func TestPostgreSQLClient_ListRelationshipsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	graph, err := client.ListRelationships(context.Background())
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, graph)
+}
+
+func TestRelationshipGraph_Subgraph(t *testing.T) {
+	graph := testRelationshipGraph()
+
+	t.Run("schema", func(t *testing.T) {
+		sub, err := graph.subgraph("billing", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"billing.invoices", "public.orders"}, tableNames(sub))
+		assert.Equal(t, []string{"orders_invoice_id_fkey"}, relationshipNames(sub))
+		assert.Zero(t, sub.Depth)
+	})
+
+	t.Run("schema includes tables without relationships", func(t *testing.T) {
+		sub, err := graph.subgraph("public", "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"billing.invoices", "public.customers", "public.orders", "public.products", "public.regions",
+		}, tableNames(sub))
+		assert.Len(t, sub.Relationships, 3)
+	})
+
+	t.Run("one hop follows both directions", func(t *testing.T) {
+		sub, err := graph.subgraph("public", "customers", 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"public.customers", "public.orders", "public.regions"}, tableNames(sub))
+		assert.Equal(t, []string{"customers_region_id_fkey", "orders_customer_id_fkey"}, relationshipNames(sub))
+		assert.Equal(t, 1, sub.Depth)
+	})
+
+	t.Run("two hops", func(t *testing.T) {
+		sub, err := graph.subgraph("public", "regions", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"public.customers", "public.orders", "public.regions"}, tableNames(sub))
+	})
+
+	t.Run("isolated table", func(t *testing.T) {
+		sub, err := graph.subgraph("public", "products", 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"public.products"}, tableNames(sub))
+		assert.Empty(t, sub.Relationships)
+	})
+
+	t.Run("unknown table", func(t *testing.T) {
+		sub, err := graph.subgraph("public", "missing", 1)
+		assert.ErrorIs(t, err, ErrTableNotFound)
+		assert.Nil(t, sub)
+	})
+}
+
+func TestApp_GetRelationships(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListRelationships", mock.Anything).Return(testRelationshipGraph(), nil)
+
+	graph, err := app.GetRelationships(context.Background(), "", "orders", 0)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSchema, graph.Schema)
+	assert.Equal(t, "orders", graph.Table)
+	assert.Equal(t, DefaultRelationshipDepth, graph.Depth)
+	assert.Equal(t, []string{"billing.invoices", "public.customers", "public.orders"}, tableNames(graph))
+
+	graph, err = app.GetRelationships(context.Background(), "public", "orders", 100)
+	require.NoError(t, err)
+	assert.Equal(t, MaxRelationshipDepth, graph.Depth)
+	assert.Len(t, graph.Tables, 4)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetRelationshipsError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListRelationships", mock.Anything).Return(nil, errors.New("connection reset")).Once()
+
+	graph, err := app.GetRelationships(context.Background(), "public", "", 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get relationships")
+	assert.Nil(t, graph)
+
+	mockClient.On("ListRelationships", mock.Anything).Return(testRelationshipGraph(), nil).Once()
+	graph, err = app.GetRelationships(context.Background(), "public", "missing", 1)
+	assert.ErrorIs(t, err, ErrTableNotFound)
+	assert.Nil(t, graph)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,373 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RoleInfo describes a role. MemberOf lists the roles it is a member of
+// directly; privileges granted to those roles are inherited when Inherit is
+// true. ConnectionLimit is -1 when unlimited and ValidUntil is nil when the
+// password never expires, including VALID UNTIL 'infinity'.
+type RoleInfo struct {
+	Name            string     `json:"name"`
+	CanLogin        bool       `json:"can_login"`
+	Superuser       bool       `json:"superuser"`
+	Inherit         bool       `json:"inherit"`
+	CreateRole      bool       `json:"create_role"`
+	CreateDB        bool       `json:"create_db"`
+	Replication     bool       `json:"replication"`
+	BypassRLS       bool       `json:"bypass_rls"`
+	ConnectionLimit int        `json:"connection_limit"`
+	ValidUntil      *time.Time `json:"valid_until,omitempty"`
+	MemberOf        []string   `json:"member_of"`
+	Description     string     `json:"description,omitempty"`
+}
+
+// TablePrivileges reports what Role may do with a relation. Privileges are
+// the table-level privileges the role holds, whether granted directly,
+// through a role it inherits from, through PUBLIC, or by ownership;
+// ColumnPrivileges adds those it holds only on some columns. Grants are the
+// ACL entries of the relation for every grantee, which explain where the
+// privileges come from.
+type TablePrivileges struct {
+	Schema           string             `json:"schema"`
+	Table            string             `json:"table"`
+	Role             string             `json:"role"`
+	Privileges       []string           `json:"privileges"`
+	ColumnPrivileges []*ColumnPrivilege `json:"column_privileges,omitempty"`
+	Grants           []*Grant           `json:"grants"`
+}
+
+// ColumnPrivilege lists privileges a role holds on one column without
+// holding them on the whole table.
+type ColumnPrivilege struct {
+	Column     string   `json:"column"`
+	Privileges []string `json:"privileges"`
+}
+
+// Grant is one entry of an access control list. Grantee is "PUBLIC" for
+// privileges granted to everyone; Grantable reports WITH GRANT OPTION.
+type Grant struct {
+	Grantee   string `json:"grantee"`
+	Grantor   string `json:"grantor"`
+	Privilege string `json:"privilege"`
+	Grantable bool   `json:"grantable"`
+}
+
+// listRolesQuery lists the roles of the cluster, leaving out the
+// predefined pg_* roles (which still appear in member_of).
+const listRolesQuery = `
+	SELECT
+		r.rolname,
+		r.rolcanlogin,
+		r.rolsuper,
+		r.rolinherit,
+		r.rolcreaterole,
+		r.rolcreatedb,
+		r.rolreplication,
+		r.rolbypassrls,
+		r.rolconnlimit,
+		r.rolvaliduntil,
+		ARRAY(
+			SELECT g.rolname::text
+			FROM pg_auth_members m
+			JOIN pg_roles g ON g.oid = m.roleid
+			WHERE m.member = r.oid
+			ORDER BY g.rolname
+		),
+		COALESCE(shobj_description(r.oid, 'pg_authid'), '')
+	FROM pg_roles r
+	WHERE r.rolname !~ '^pg_'
+	ORDER BY r.rolname`
+
+// tablePrivilegesQuery lists the relations of a schema (or one of them)
+// with the table-level privileges $3 holds on each.
+const tablePrivilegesQuery = `
+	SELECT
+		c.relname,
+		ARRAY(
+			SELECT p FROM unnest(ARRAY['SELECT', 'INSERT', 'UPDATE', 'DELETE', 'TRUNCATE', 'REFERENCES', 'TRIGGER'])
+				WITH ORDINALITY AS u(p, ord)
+			WHERE has_table_privilege($3::name, c.oid, p)
+			ORDER BY ord
+		)
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2) AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+	ORDER BY c.relname`
+
+// columnPrivilegesQuery lists, for columns that carry their own ACL, the
+// privileges $3 holds on the column but not on its table.
+const columnPrivilegesQuery = `
+	SELECT c.relname, a.attname, p
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped AND a.attacl IS NOT NULL
+	CROSS JOIN unnest(ARRAY['SELECT', 'INSERT', 'UPDATE', 'REFERENCES']) WITH ORDINALITY AS u(p, ord)
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2) AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+		AND has_column_privilege($3::name, c.oid, a.attnum, p)
+		AND NOT has_table_privilege($3::name, c.oid, p)
+	ORDER BY c.relname, a.attnum, u.ord`
+
+// tableGrantsQuery explodes the ACL of each relation. A NULL relacl means
+// the relation still has its default privileges, which acldefault spells
+// out (everything for the owner).
+const tableGrantsQuery = `
+	SELECT
+		c.relname,
+		CASE WHEN g.grantee = 0 THEN 'PUBLIC' ELSE pg_get_userbyid(g.grantee) END,
+		pg_get_userbyid(g.grantor),
+		g.privilege_type,
+		g.is_grantable
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	CROSS JOIN aclexplode(COALESCE(c.relacl, acldefault('r', c.relowner))) g
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2) AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+	ORDER BY c.relname, 2, g.privilege_type`
+
+// ListRoles returns the roles of the cluster other than the predefined
+// pg_* roles.
+func (c *PostgreSQLClientImpl) ListRoles(ctx context.Context) ([]*RoleInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	rows, err := db.QueryContext(ctx, listRolesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	roles := []*RoleInfo{}
+	for rows.Next() {
+		var role RoleInfo
+		var validUntil pgtype.Timestamptz
+		if err := rows.Scan(
+			&role.Name,
+			&role.CanLogin,
+			&role.Superuser,
+			&role.Inherit,
+			&role.CreateRole,
+			&role.CreateDB,
+			&role.Replication,
+			&role.BypassRLS,
+			&role.ConnectionLimit,
+			&validUntil,
+			typeMap.SQLScanner(&role.MemberOf),
+			&role.Description,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan role row: %w", err)
+		}
+		role.ValidUntil = roleValidUntil(validUntil)
+		roles = append(roles, &role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate role rows: %w", err)
+	}
+	return roles, nil
+}
+
+// roleValidUntil converts rolvaliduntil, which may be infinite: 'infinity'
+// never expires, like NULL, and '-infinity' has always expired, which is
+// reported as the zero time.
+func roleValidUntil(validUntil pgtype.Timestamptz) *time.Time {
+	if !validUntil.Valid || validUntil.InfinityModifier == pgtype.Infinity {
+		return nil
+	}
+	if validUntil.InfinityModifier == pgtype.NegativeInfinity {
+		return &time.Time{}
+	}
+	return &validUntil.Time
+}
+
+// GetTablePrivileges returns the privileges role holds on the relations of
+// a schema, or on one relation when table is not empty, together with the
+// grants on them. An empty role means the current user.
+func (c *PostgreSQLClientImpl) GetTablePrivileges(
+	ctx context.Context,
+	role, schema, table string,
+) ([]*TablePrivileges, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	// has_table_privilege raises an error for unknown roles; check first so
+	// the caller gets ErrRoleNotFound instead.
+	err := db.QueryRowContext(ctx, `
+		SELECT rolname FROM pg_roles WHERE rolname = COALESCE(NULLIF($1, ''), current_user)`, role).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("role %s: %w", role, ErrRoleNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table privileges: %w", err)
+	}
+
+	privileges, byTable, err := queryTablePrivileges(ctx, db, role, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if table != "" && len(privileges) == 0 {
+		return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+	}
+
+	if err := queryColumnPrivileges(ctx, db, role, schema, table, byTable); err != nil {
+		return nil, err
+	}
+	if err := queryTableGrants(ctx, db, schema, table, byTable); err != nil {
+		return nil, err
+	}
+	return privileges, nil
+}
+
+// queryTablePrivileges runs tablePrivilegesQuery and indexes the result by
+// table name for the column and grant queries.
+func queryTablePrivileges(
+	ctx context.Context,
+	db *sql.DB,
+	role, schema, table string,
+) ([]*TablePrivileges, map[string]*TablePrivileges, error) {
+	rows, err := db.QueryContext(ctx, tablePrivilegesQuery, schema, table, role)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get table privileges: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	privileges := []*TablePrivileges{}
+	byTable := map[string]*TablePrivileges{}
+	for rows.Next() {
+		entry := &TablePrivileges{Schema: schema, Role: role, Grants: []*Grant{}}
+		if err := rows.Scan(&entry.Table, typeMap.SQLScanner(&entry.Privileges)); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan table privilege row: %w", err)
+		}
+		if entry.Privileges == nil {
+			entry.Privileges = []string{}
+		}
+		privileges = append(privileges, entry)
+		byTable[entry.Table] = entry
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate table privilege rows: %w", err)
+	}
+	return privileges, byTable, nil
+}
+
+// queryColumnPrivileges adds the column-only privileges of role to byTable.
+func queryColumnPrivileges(
+	ctx context.Context,
+	db *sql.DB,
+	role, schema, table string,
+	byTable map[string]*TablePrivileges,
+) error {
+	rows, err := db.QueryContext(ctx, columnPrivilegesQuery, schema, table, role)
+	if err != nil {
+		return fmt.Errorf("failed to get column privileges: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tableName, column, privilege string
+		if err := rows.Scan(&tableName, &column, &privilege); err != nil {
+			return fmt.Errorf("failed to scan column privilege row: %w", err)
+		}
+		entry, ok := byTable[tableName]
+		if !ok {
+			continue
+		}
+		last := len(entry.ColumnPrivileges) - 1
+		if last < 0 || entry.ColumnPrivileges[last].Column != column {
+			entry.ColumnPrivileges = append(entry.ColumnPrivileges, &ColumnPrivilege{Column: column})
+			last++
+		}
+		entry.ColumnPrivileges[last].Privileges = append(entry.ColumnPrivileges[last].Privileges, privilege)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate column privilege rows: %w", err)
+	}
+	return nil
+}
+
+// queryTableGrants adds the exploded ACL entries of each table to byTable.
+func queryTableGrants(ctx context.Context, db *sql.DB, schema, table string, byTable map[string]*TablePrivileges) error {
+	rows, err := db.QueryContext(ctx, tableGrantsQuery, schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to list grants: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tableName string
+		var grant Grant
+		if err := rows.Scan(&tableName, &grant.Grantee, &grant.Grantor, &grant.Privilege, &grant.Grantable); err != nil {
+			return fmt.Errorf("failed to scan grant row: %w", err)
+		}
+		if entry, ok := byTable[tableName]; ok {
+			entry.Grants = append(entry.Grants, &grant)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate grant rows: %w", err)
+	}
+	return nil
+}
+
+// ListRoles returns the roles of the cluster with their attributes and
+// memberships.
+func (a *App) ListRoles(ctx context.Context) ([]*RoleInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	a.logger.Debug("Listing roles")
+
+	roles, err := a.client.ListRoles(ctx)
+	if err != nil {
+		a.logger.Error("Failed to list roles", "error", err)
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	a.logger.Debug("Successfully listed roles", "count", len(roles))
+	return roles, nil
+}
+
+// GetTablePrivileges returns what role (the current user when empty) may do
+// with the relations of a schema, or with one relation when table is set,
+// and the grants behind it.
+func (a *App) GetTablePrivileges(ctx context.Context, role, schema, table string) ([]*TablePrivileges, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get table privileges: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Getting table privileges", "role", role, "schema", schema, "table", table)
+
+	privileges, err := a.client.GetTablePrivileges(ctx, role, schema, table)
+	if err != nil {
+		a.logger.Error("Failed to get table privileges", "error", err, "role", role, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to get table privileges: %w", err)
+	}
+
+	a.logger.Debug("Successfully retrieved table privileges", "count", len(privileges),
+		"role", role, "schema", schema, "table", table)
+	return privileges, nil
+}
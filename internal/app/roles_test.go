@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLClient_RolesWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+
+	roles, err := client.ListRoles(context.Background())
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, roles)
+
+	privileges, err := client.GetTablePrivileges(context.Background(), "", "public", "users")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, privileges)
+}
+
+func TestRoleValidUntil(t *testing.T) {
+	expiry := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The stdlib driver hands infinite timestamps over as strings.
+	tests := []struct {
+		src  any
+		want *time.Time
+	}{
+		{src: nil, want: nil},
+		{src: "infinity", want: nil},
+		{src: "-infinity", want: &time.Time{}},
+		{src: expiry, want: &expiry},
+	}
+	for _, tt := range tests {
+		var validUntil pgtype.Timestamptz
+		require.NoError(t, validUntil.Scan(tt.src), "%v", tt.src)
+		assert.Equal(t, tt.want, roleValidUntil(validUntil), "%v", tt.src)
+	}
+}
+
+func TestApp_ListRoles(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*RoleInfo{
+		{Name: "app", CanLogin: true, Inherit: true, ConnectionLimit: -1, MemberOf: []string{"readers"}},
+		{Name: "readers", Inherit: true, ConnectionLimit: -1, MemberOf: []string{"pg_read_all_data"}},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListRoles", mock.Anything).Return(expected, nil)
+
+	roles, err := app.ListRoles(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, roles)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetTablePrivileges(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*TablePrivileges{{
+		Schema:     DefaultSchema,
+		Table:      "users",
+		Role:       "app",
+		Privileges: []string{},
+		ColumnPrivileges: []*ColumnPrivilege{
+			{Column: "id", Privileges: []string{"SELECT"}},
+			{Column: "name", Privileges: []string{"SELECT", "UPDATE"}},
+		},
+		Grants: []*Grant{
+			{Grantee: "app", Grantor: "postgres", Privilege: "SELECT"},
+			{Grantee: "postgres", Grantor: "postgres", Privilege: "SELECT"},
+		},
+	}}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetTablePrivileges", mock.Anything, "app", DefaultSchema, "users").Return(expected, nil)
+
+	privileges, err := app.GetTablePrivileges(context.Background(), "app", "", "users")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, privileges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetTablePrivilegesErrors(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetTablePrivileges", mock.Anything, "ghost", DefaultSchema, "").
+		Return(nil, fmt.Errorf("role ghost: %w", ErrRoleNotFound))
+	mockClient.On("GetTablePrivileges", mock.Anything, "", DefaultSchema, "missing").
+		Return(nil, fmt.Errorf("table public.missing: %w", ErrTableNotFound))
+
+	privileges, err := app.GetTablePrivileges(context.Background(), "ghost", "", "")
+	assert.ErrorIs(t, err, ErrRoleNotFound)
+	assert.Nil(t, privileges)
+
+	_, err = app.GetTablePrivileges(context.Background(), "", "", "missing")
+	assert.ErrorIs(t, err, ErrTableNotFound)
+	mockClient.AssertExpectations(t)
+}
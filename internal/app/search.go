@@ -0,0 +1,320 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Search modes accepted in SearchOptions.Mode.
+const (
+	SearchModeILike      = "ilike"
+	SearchModeRegex      = "regex"
+	SearchModeSimilarity = "similarity"
+)
+
+// Object types returned by SearchSchema besides the TableType* kinds.
+const (
+	SearchObjectColumn    = "column"
+	SearchObjectFunction  = "function"
+	SearchObjectProcedure = "procedure"
+)
+
+// Result limits of SearchSchema.
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 500
+)
+
+// Trigram thresholds of the similarity mode, the pg_trgm defaults for the
+// % and <% operators.
+const (
+	similarityThreshold     = 0.3
+	wordSimilarityThreshold = 0.6
+)
+
+// searchModes lists the values accepted in SearchOptions.Mode.
+var searchModes = []string{SearchModeILike, SearchModeRegex, SearchModeSimilarity}
+
+// searchObjectTypes lists the values accepted in SearchOptions.Types.
+var searchObjectTypes = append(slices.Clone(tableTypes), SearchObjectColumn, SearchObjectFunction, SearchObjectProcedure)
+
+// SearchModes returns the modes accepted in SearchOptions.Mode.
+func SearchModes() []string {
+	return slices.Clone(searchModes)
+}
+
+// SearchObjectTypes returns the object types accepted in SearchOptions.Types.
+func SearchObjectTypes() []string {
+	return slices.Clone(searchObjectTypes)
+}
+
+// SearchOptions selects what SearchSchema looks for. Pattern is matched
+// according to Mode: an ILIKE pattern (a substring when it has no "%"), a
+// case-insensitive POSIX regular expression, or text compared by pg_trgm
+// trigram similarity. Types, when set, keeps only objects of those kinds.
+type SearchOptions struct {
+	Pattern string
+	Mode    string
+	Types   []string
+	Limit   int
+}
+
+// SearchResult is a catalog object matching a search. Table is set for
+// columns, DataType for columns and Arguments for functions and procedures.
+// MatchedOn is "name" or "description", and Score ranks the results from
+// 1 (the name equals the pattern) down.
+type SearchResult struct {
+	Type        string  `json:"type"`
+	Schema      string  `json:"schema"`
+	Table       string  `json:"table,omitempty"`
+	Name        string  `json:"name"`
+	DataType    string  `json:"data_type,omitempty"`
+	Arguments   string  `json:"arguments,omitempty"`
+	Description string  `json:"description,omitempty"`
+	MatchedOn   string  `json:"matched_on"`
+	Score       float64 `json:"score"`
+}
+
+// searchSchemaQuery gathers the relations, columns, functions and
+// procedures of every schema the user may use, skipping system schemas,
+// partitions and objects that belong to extensions, and ranks them with the
+// score and name-match expressions of the search mode. $4 is a
+// comma-separated list of object types (empty for all) and $5 the limit.
+const searchSchemaQuery = `
+	WITH objects AS (
+		SELECT
+			CASE c.relkind
+				WHEN 'p' THEN 'partitioned table'
+				WHEN 'v' THEN 'view'
+				WHEN 'm' THEN 'materialized view'
+				WHEN 'f' THEN 'foreign table'
+				ELSE 'table'
+			END AS type,
+			n.nspname::text AS schema,
+			'' AS table_name,
+			c.relname::text AS name,
+			'' AS data_type,
+			'' AS arguments,
+			COALESCE(obj_description(c.oid, 'pg_class'), '') AS description
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f') AND NOT c.relispartition
+			AND n.nspname NOT IN ('information_schema', 'pg_catalog') AND n.nspname !~ '^pg_(toast|temp_)'
+			AND has_schema_privilege(n.oid, 'USAGE')
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_depend d
+				WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+			)
+		UNION ALL
+		SELECT
+			'column',
+			n.nspname::text,
+			c.relname::text,
+			a.attname::text,
+			format_type(a.atttypid, a.atttypmod),
+			'',
+			COALESCE(col_description(c.oid, a.attnum), '')
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f') AND NOT c.relispartition
+			AND a.attnum > 0 AND NOT a.attisdropped
+			AND n.nspname NOT IN ('information_schema', 'pg_catalog') AND n.nspname !~ '^pg_(toast|temp_)'
+			AND has_schema_privilege(n.oid, 'USAGE')
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_depend d
+				WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+			)
+		UNION ALL
+		SELECT
+			CASE p.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END,
+			n.nspname::text,
+			'',
+			p.proname::text,
+			'',
+			pg_get_function_identity_arguments(p.oid),
+			COALESCE(obj_description(p.oid, 'pg_proc'), '')
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname NOT IN ('information_schema', 'pg_catalog') AND n.nspname !~ '^pg_(toast|temp_)'
+			AND has_schema_privilege(n.oid, 'USAGE')
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_depend d
+				WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+			)
+	),
+	scored AS (
+		SELECT o.*, %s AS score, %s AS name_match
+		FROM objects o
+		WHERE $4 = '' OR o.type = ANY(string_to_array($4, ','))
+	)
+	SELECT type, schema, table_name, name, data_type, arguments, description, name_match, round(score::numeric, 3)::float8
+	FROM scored
+	WHERE score > 0
+	ORDER BY score DESC, length(name), schema, table_name, name, type
+	LIMIT $5`
+
+// Score and name-match expressions of the ilike and regex modes. $1 matches
+// anywhere in the name or description, $2 at the start of the name, and $3
+// the whole name.
+const (
+	ilikeScore = `CASE
+			WHEN lower(o.name) = lower($3) THEN 1.0
+			WHEN o.name ILIKE $2 THEN 0.8
+			WHEN o.name ILIKE $1 THEN 0.6
+			WHEN o.description ILIKE $1 THEN 0.4
+			ELSE 0
+		END`
+	ilikeNameMatch = `o.name ILIKE $1`
+	regexScore     = `CASE
+			WHEN o.name ~* $3 THEN 1.0
+			WHEN o.name ~* $2 THEN 0.8
+			WHEN o.name ~* $1 THEN 0.6
+			WHEN o.description ~* $1 THEN 0.4
+			ELSE 0
+		END`
+	regexNameMatch = `o.name ~* $1`
+)
+
+// trigramSchemaQuery finds the schema pg_trgm is installed in, so its
+// functions can be called whatever the search_path.
+const trigramSchemaQuery = `
+	SELECT n.nspname
+	FROM pg_extension e
+	JOIN pg_namespace n ON n.oid = e.extnamespace
+	WHERE e.extname = 'pg_trgm'`
+
+// searchPatterns returns the three pattern arguments of the ilike and regex
+// modes: anywhere, prefix and whole-name matches. An ILIKE pattern without
+// "%" is searched as a substring; a pattern with "%" is used as given.
+func searchPatterns(mode, pattern string) (match, prefix, exact string) {
+	if mode == SearchModeRegex {
+		return pattern, "^(?:" + pattern + ")", "^(?:" + pattern + ")$"
+	}
+	if strings.Contains(pattern, "%") {
+		return pattern, pattern, pattern
+	}
+	return "%" + pattern + "%", pattern + "%", pattern
+}
+
+// SearchSchema returns the catalog objects matching opts, best first. opts
+// must have a valid Mode and a positive Limit.
+func (c *PostgreSQLClientImpl) SearchSchema(ctx context.Context, opts SearchOptions) ([]*SearchResult, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	var query string
+	var args []any
+	switch opts.Mode {
+	case SearchModeSimilarity:
+		var trgmSchema string
+		if err := db.QueryRowContext(ctx, trigramSchemaQuery).Scan(&trgmSchema); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrTrigramUnavailable
+			}
+			return nil, fmt.Errorf("failed to find pg_trgm: %w", err)
+		}
+		similarity := pgx.Identifier{trgmSchema, "similarity"}.Sanitize()
+		wordSimilarity := pgx.Identifier{trgmSchema, "word_similarity"}.Sanitize()
+		score := fmt.Sprintf(`CASE
+			WHEN %[1]s(o.name, $1) >= $2 THEN %[1]s(o.name, $1)
+			WHEN %[2]s($1, o.description) >= $3 THEN 0.5 * %[2]s($1, o.description)
+			ELSE 0
+		END`, similarity, wordSimilarity)
+		nameMatch := fmt.Sprintf(`%s(o.name, $1) >= $2`, similarity)
+		query = fmt.Sprintf(searchSchemaQuery, score, nameMatch)
+		args = []any{opts.Pattern, similarityThreshold, wordSimilarityThreshold}
+	case SearchModeRegex:
+		query = fmt.Sprintf(searchSchemaQuery, regexScore, regexNameMatch)
+	default:
+		query = fmt.Sprintf(searchSchemaQuery, ilikeScore, ilikeNameMatch)
+	}
+	if args == nil {
+		match, prefix, exact := searchPatterns(opts.Mode, opts.Pattern)
+		args = []any{match, prefix, exact}
+	}
+	args = append(args, strings.Join(opts.Types, ","), opts.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search schema: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results := []*SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		var nameMatch bool
+		if err := rows.Scan(
+			&result.Type,
+			&result.Schema,
+			&result.Table,
+			&result.Name,
+			&result.DataType,
+			&result.Arguments,
+			&result.Description,
+			&nameMatch,
+			&result.Score,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result row: %w", err)
+		}
+		result.MatchedOn = "description"
+		if nameMatch {
+			result.MatchedOn = "name"
+		}
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search result rows: %w", err)
+	}
+	return results, nil
+}
+
+// SearchSchema finds the tables, views, columns, functions and procedures
+// whose name or comment matches opts.Pattern, across every schema the user
+// may use, ranked by relevance. Mode defaults to SearchModeILike and Limit
+// to DefaultSearchLimit, capped at MaxSearchLimit.
+func (a *App) SearchSchema(ctx context.Context, opts SearchOptions) ([]*SearchResult, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to search schema: %w", err)
+	}
+
+	if strings.TrimSpace(opts.Pattern) == "" {
+		return nil, ErrSearchPatternRequired
+	}
+	if opts.Mode == "" {
+		opts.Mode = SearchModeILike
+	}
+	if !slices.Contains(searchModes, opts.Mode) {
+		return nil, fmt.Errorf("%w: %q (supported: %s)", ErrInvalidSearchMode, opts.Mode, strings.Join(searchModes, ", "))
+	}
+	for _, t := range opts.Types {
+		if !slices.Contains(searchObjectTypes, t) {
+			return nil, fmt.Errorf("%w: %q (supported: %s)", ErrInvalidObjectType, t, strings.Join(searchObjectTypes, ", "))
+		}
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultSearchLimit
+	}
+	opts.Limit = min(opts.Limit, MaxSearchLimit)
+
+	a.logger.Debug("Searching schema", "pattern", opts.Pattern, "mode", opts.Mode, "types", opts.Types, "limit", opts.Limit)
+
+	results, err := a.client.SearchSchema(ctx, opts)
+	if err != nil {
+		a.logger.Error("Failed to search schema", "error", err, "pattern", opts.Pattern, "mode", opts.Mode)
+		return nil, fmt.Errorf("failed to search schema: %w", err)
+	}
+
+	a.logger.Debug("Successfully searched schema", "count", len(results))
+	return results, nil
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_SearchSchemaWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	results, err := client.SearchSchema(context.Background(), SearchOptions{Pattern: "user", Mode: SearchModeILike, Limit: 10})
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, results)
+}
+
+func TestSearchPatterns(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mode                 string
+		pattern              string
+		match, prefix, exact string
+	}{
+		{"ilike substring", SearchModeILike, "cust", "%cust%", "cust%", "cust"},
+		{"ilike with wildcard", SearchModeILike, "cust%_id", "cust%_id", "cust%_id", "cust%_id"},
+		{"regex", SearchModeRegex, "user|account", "user|account", "^(?:user|account)", "^(?:user|account)$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, prefix, exact := searchPatterns(tt.mode, tt.pattern)
+			assert.Equal(t, tt.match, match)
+			assert.Equal(t, tt.prefix, prefix)
+			assert.Equal(t, tt.exact, exact)
+		})
+	}
+}
+
+func TestSearchObjectTypes(t *testing.T) {
+	assert.Equal(t, []string{
+		TableTypeTable, TableTypePartitionedTable, TableTypeView, TableTypeMaterializedView, TableTypeForeignTable,
+		SearchObjectColumn, SearchObjectFunction, SearchObjectProcedure,
+	}, SearchObjectTypes())
+}
+
+func TestApp_SearchSchema(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*SearchResult{
+		{Type: TableTypeTable, Schema: "public", Name: "customers", MatchedOn: "name", Score: 0.8},
+		{Type: SearchObjectColumn, Schema: "sales", Table: "orders", Name: "customer_id", DataType: "integer", MatchedOn: "name", Score: 0.8},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SearchSchema", mock.Anything, SearchOptions{
+		Pattern: "customer", Mode: SearchModeILike, Limit: DefaultSearchLimit,
+	}).Return(expected, nil)
+	mockClient.On("SearchSchema", mock.Anything, SearchOptions{
+		Pattern: "cust", Mode: SearchModeSimilarity, Types: []string{SearchObjectColumn}, Limit: MaxSearchLimit,
+	}).Return([]*SearchResult{}, nil)
+
+	results, err := app.SearchSchema(context.Background(), SearchOptions{Pattern: "customer"})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, results)
+
+	results, err = app.SearchSchema(context.Background(), SearchOptions{
+		Pattern: "cust", Mode: SearchModeSimilarity, Types: []string{SearchObjectColumn}, Limit: 10000,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_SearchSchemaInvalidOptions(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+	mockClient.On("Ping", mock.Anything).Return(nil)
+
+	_, err := app.SearchSchema(context.Background(), SearchOptions{Pattern: "  "})
+	assert.ErrorIs(t, err, ErrSearchPatternRequired)
+
+	_, err = app.SearchSchema(context.Background(), SearchOptions{Pattern: "user", Mode: "fuzzy"})
+	assert.ErrorIs(t, err, ErrInvalidSearchMode)
+
+	_, err = app.SearchSchema(context.Background(), SearchOptions{Pattern: "user", Types: []string{"index"}})
+	assert.ErrorIs(t, err, ErrInvalidObjectType)
+	assert.Contains(t, err.Error(), "column, function, procedure")
+
+	mockClient.AssertNotCalled(t, "SearchSchema", mock.Anything, mock.Anything)
+}
+
+func TestApp_SearchSchemaError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SearchSchema", mock.Anything, mock.Anything).Return(nil, ErrTrigramUnavailable).Once()
+	mockClient.On("SearchSchema", mock.Anything, mock.Anything).Return(nil, errors.New("connection reset")).Once()
+
+	results, err := app.SearchSchema(context.Background(), SearchOptions{Pattern: "user", Mode: SearchModeSimilarity})
+	assert.ErrorIs(t, err, ErrTrigramUnavailable)
+	assert.Nil(t, results)
+
+	results, err = app.SearchSchema(context.Background(), SearchOptions{Pattern: "user"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to search schema")
+	assert.Nil(t, results)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SequenceInfo describes a sequence. LastValue is the value most recently
+// returned by nextval in any session; it is nil until the sequence is first
+// used and when the current role lacks USAGE or SELECT on it. OwnedBy is the
+// quoted "schema.table.column" a serial or identity column (or OWNED BY)
+// ties the sequence to, and Identity reports whether that column is an
+// identity column.
+type SequenceInfo struct {
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	StartValue int64  `json:"start_value"`
+	MinValue   int64  `json:"min_value"`
+	MaxValue   int64  `json:"max_value"`
+	Increment  int64  `json:"increment"`
+	Cycle      bool   `json:"cycle"`
+	CacheSize  int64  `json:"cache_size"`
+	LastValue  *int64 `json:"last_value"`
+	OwnedBy    string `json:"owned_by,omitempty"`
+	Identity   bool   `json:"identity"`
+	Owner      string `json:"owner"`
+}
+
+// listSequencesQuery reads a schema's sequences from pg_sequences, which
+// hides last_value from roles that may not read it, and finds the owning
+// column through the auto ('a', serial and OWNED BY) or internal ('i',
+// identity) dependency of the sequence on it.
+const listSequencesQuery = `
+	SELECT
+		s.sequencename,
+		format_type(s.data_type, NULL),
+		s.start_value,
+		s.min_value,
+		s.max_value,
+		s.increment_by,
+		s.cycle,
+		s.cache_size,
+		s.last_value,
+		CASE WHEN a.attname IS NULL THEN '' ELSE format('%I.%I.%I', tn.nspname, tc.relname, a.attname) END,
+		COALESCE(d.deptype = 'i', false),
+		s.sequenceowner
+	FROM pg_sequences s
+	JOIN pg_namespace n ON n.nspname = s.schemaname
+	JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = s.sequencename
+	LEFT JOIN pg_depend d
+		ON d.classid = 'pg_class'::regclass
+		AND d.objid = c.oid
+		AND d.refclassid = 'pg_class'::regclass
+		AND d.refobjsubid > 0
+		AND d.deptype IN ('a', 'i')
+	LEFT JOIN pg_class tc ON tc.oid = d.refobjid
+	LEFT JOIN pg_namespace tn ON tn.oid = tc.relnamespace
+	LEFT JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+	WHERE s.schemaname = $1
+	ORDER BY s.sequencename`
+
+// ListSequences returns the sequences of a schema.
+func (c *PostgreSQLClientImpl) ListSequences(ctx context.Context, schema string) ([]*SequenceInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, listSequencesQuery, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sequences: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	sequences := []*SequenceInfo{}
+	for rows.Next() {
+		sequence := SequenceInfo{Schema: schema}
+		var lastValue sql.NullInt64
+		if err := rows.Scan(
+			&sequence.Name,
+			&sequence.DataType,
+			&sequence.StartValue,
+			&sequence.MinValue,
+			&sequence.MaxValue,
+			&sequence.Increment,
+			&sequence.Cycle,
+			&sequence.CacheSize,
+			&lastValue,
+			&sequence.OwnedBy,
+			&sequence.Identity,
+			&sequence.Owner,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sequence row: %w", err)
+		}
+		if lastValue.Valid {
+			sequence.LastValue = &lastValue.Int64
+		}
+		sequences = append(sequences, &sequence)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sequence rows: %w", err)
+	}
+	return sequences, nil
+}
+
+// ListSequences returns the sequences of a schema with their bounds,
+// increment, current value, and owning column.
+func (a *App) ListSequences(ctx context.Context, schema string) ([]*SequenceInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list sequences: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Listing sequences", "schema", schema)
+
+	sequences, err := a.client.ListSequences(ctx, schema)
+	if err != nil {
+		a.logger.Error("Failed to list sequences", "error", err, "schema", schema)
+		return nil, fmt.Errorf("failed to list sequences: %w", err)
+	}
+
+	a.logger.Debug("Successfully listed sequences", "count", len(sequences), "schema", schema)
+	return sequences, nil
+}
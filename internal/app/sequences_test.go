@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_ListSequencesWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	sequences, err := client.ListSequences(context.Background(), "public")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, sequences)
+}
+
+func TestApp_ListSequences(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	lastValue := int64(42)
+	expected := []*SequenceInfo{{
+		Schema:     DefaultSchema,
+		Name:       "orders_id_seq",
+		DataType:   "integer",
+		StartValue: 1,
+		MinValue:   1,
+		MaxValue:   2147483647,
+		Increment:  1,
+		CacheSize:  1,
+		LastValue:  &lastValue,
+		OwnedBy:    "public.orders.id",
+		Identity:   true,
+		Owner:      "postgres",
+	}}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListSequences", mock.Anything, DefaultSchema).Return(expected, nil)
+
+	sequences, err := app.ListSequences(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, sequences)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListSequencesError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListSequences", mock.Anything, "billing").Return(nil, errors.New("permission denied"))
+
+	sequences, err := app.ListSequences(context.Background(), "billing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list sequences")
+	assert.Nil(t, sequences)
+	mockClient.AssertExpectations(t)
+}
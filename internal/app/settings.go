@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"fmt"
+)
+
+// SettingInfo describes a server configuration parameter as seen by the
+// current session. Setting is the raw value in Unit (e.g. "4096" and "kB"
+// for work_mem) and Value the same value as SHOW prints it ("4MB"). Context
+// says when a change takes effect (e.g. "user", "sighup", "postmaster");
+// Source says where the current value comes from ("default",
+// "configuration file", "database", "session", ...). NonDefault is true when
+// the value was set explicitly rather than left at the built-in default,
+// and PendingRestart when a changed configuration file value waits for a
+// server restart.
+type SettingInfo struct {
+	Name           string `json:"name"`
+	Value          string `json:"value"`
+	Setting        string `json:"setting"`
+	Unit           string `json:"unit,omitempty"`
+	Category       string `json:"category"`
+	Description    string `json:"description"`
+	Context        string `json:"context"`
+	Source         string `json:"source"`
+	NonDefault     bool   `json:"non_default"`
+	PendingRestart bool   `json:"pending_restart"`
+}
+
+// SettingsOptions filters GetSettings. Name and Category match any setting
+// whose name or category contains them, ignoring case; NonDefault keeps
+// only the settings with a non-default source.
+type SettingsOptions struct {
+	Name       string
+	Category   string
+	NonDefault bool
+}
+
+// getSettingsQuery reads pg_settings. Settings whose source is "default" or
+// "override" (values the server derives itself, such as data_checksums)
+// count as default.
+const getSettingsQuery = `
+	SELECT
+		name,
+		COALESCE(current_setting(name, true), setting),
+		COALESCE(setting, ''),
+		COALESCE(unit, ''),
+		category,
+		COALESCE(short_desc, ''),
+		context,
+		source,
+		source NOT IN ('default', 'override'),
+		pending_restart
+	FROM pg_settings
+	WHERE ($1 = '' OR strpos(lower(name), lower($1)) > 0)
+		AND ($2 = '' OR strpos(lower(category), lower($2)) > 0)
+		AND (NOT $3 OR source NOT IN ('default', 'override'))
+	ORDER BY category, name`
+
+// GetSettings returns the server configuration parameters matching opts.
+func (c *PostgreSQLClientImpl) GetSettings(ctx context.Context, opts SettingsOptions) ([]*SettingInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	rows, err := db.QueryContext(ctx, getSettingsQuery, opts.Name, opts.Category, opts.NonDefault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	settings := []*SettingInfo{}
+	for rows.Next() {
+		var setting SettingInfo
+		if err := rows.Scan(
+			&setting.Name,
+			&setting.Value,
+			&setting.Setting,
+			&setting.Unit,
+			&setting.Category,
+			&setting.Description,
+			&setting.Context,
+			&setting.Source,
+			&setting.NonDefault,
+			&setting.PendingRestart,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan setting row: %w", err)
+		}
+		settings = append(settings, &setting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate setting rows: %w", err)
+	}
+	return settings, nil
+}
+
+// GetSettings returns the server configuration parameters matching opts,
+// with their current value, source, and whether they differ from the
+// default.
+func (a *App) GetSettings(ctx context.Context, opts SettingsOptions) ([]*SettingInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	a.logger.Debug("Getting settings", "name", opts.Name, "category", opts.Category, "non_default", opts.NonDefault)
+
+	settings, err := a.client.GetSettings(ctx, opts)
+	if err != nil {
+		a.logger.Error("Failed to get settings", "error", err)
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	a.logger.Debug("Successfully retrieved settings", "count", len(settings))
+	return settings, nil
+}
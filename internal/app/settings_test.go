@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_GetSettingsWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	settings, err := client.GetSettings(context.Background(), SettingsOptions{})
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, settings)
+}
+
+func TestApp_GetSettings(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	opts := SettingsOptions{Name: "work_mem", NonDefault: true}
+	expected := []*SettingInfo{{
+		Name:        "work_mem",
+		Value:       "16MB",
+		Setting:     "16384",
+		Unit:        "kB",
+		Category:    "Resource Usage / Memory",
+		Description: "Sets the maximum memory to be used for query workspaces.",
+		Context:     "user",
+		Source:      "configuration file",
+		NonDefault:  true,
+	}}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetSettings", mock.Anything, opts).Return(expected, nil)
+
+	settings, err := app.GetSettings(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, settings)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetSettingsError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetSettings", mock.Anything, SettingsOptions{}).Return(nil, errors.New("connection reset"))
+
+	settings, err := app.GetSettings(context.Background(), SettingsOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get settings")
+	assert.Nil(t, settings)
+	mockClient.AssertExpectations(t)
+}
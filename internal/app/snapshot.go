@@ -0,0 +1,472 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SnapshotVersion is the version of the SchemaSnapshot JSON format. It is
+// bumped whenever a change to the format would make DiffSchema misreport a
+// snapshot written by an earlier version.
+const SnapshotVersion = 1
+
+// SchemaSnapshot is the structure of a schema as compared by DiffSchema:
+// its tables with their columns, constraints, and indexes, its views and
+// materialized views, and its functions and procedures. Definitions are
+// rendered with the schema on the search path, so objects of the schema
+// itself are not qualified and snapshots of two schemas compare equal when
+// their objects are the same. Its JSON form, tagged with Version, can be
+// stored and compared with the live schema later.
+type SchemaSnapshot struct {
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"created_at"`
+	Database  string              `json:"database"`
+	Schema    string              `json:"schema"`
+	Tables    []*SnapshotTable    `json:"tables"`
+	Views     []*SnapshotView     `json:"views"`
+	Functions []*SnapshotFunction `json:"functions"`
+}
+
+// SnapshotTable is a table or partitioned table of a snapshot, with the
+// partition key of the latter in PartitionBy. Partitions are left out:
+// their structure comes from the partitioned table.
+type SnapshotTable struct {
+	Name        string                `json:"name"`
+	PartitionBy string                `json:"partition_by,omitempty"`
+	Columns     []*SnapshotColumn     `json:"columns"`
+	Constraints []*SnapshotConstraint `json:"constraints"`
+	Indexes     []*SnapshotIndex      `json:"indexes"`
+}
+
+// SnapshotColumn is a column of a snapshot table. Identity is "always" or
+// "by default" for identity columns; Generated holds the GENERATED ALWAYS
+// AS clause of generated columns, which have no Default. Serial is
+// "smallserial", "serial", or "bigserial" when Default draws from a
+// sequence the column owns, which is how a migration declares the column
+// so the sequence is created with it.
+type SnapshotColumn struct {
+	Name      string `json:"name"`
+	DataType  string `json:"data_type"`
+	Nullable  bool   `json:"nullable"`
+	Default   string `json:"default,omitempty"`
+	Identity  string `json:"identity,omitempty"`
+	Generated string `json:"generated,omitempty"`
+	Serial    string `json:"serial,omitempty"`
+}
+
+// SnapshotConstraint is a primary key, unique, foreign key, check, or
+// exclusion constraint declared on a snapshot table. Type is one of the
+// Constraint* constants.
+type SnapshotConstraint struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Definition string `json:"definition"`
+}
+
+// SnapshotIndex is an index not created by a constraint. Definition is its
+// CREATE INDEX statement with the table name unqualified.
+type SnapshotIndex struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// SnapshotView is a view or, when Materialized is set, a materialized view.
+// Definition is its SELECT statement.
+type SnapshotView struct {
+	Name         string `json:"name"`
+	Materialized bool   `json:"materialized"`
+	Definition   string `json:"definition"`
+}
+
+// SnapshotFunction is a function or procedure. Name includes the identity
+// arguments, e.g. add(integer, integer), so overloads are told apart;
+// Definition is its CREATE OR REPLACE statement with the name unqualified.
+// Arguments (the full argument list, with OUT parameters and defaults) and
+// Result are the parts of the signature CREATE OR REPLACE cannot change.
+type SnapshotFunction struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Arguments  string `json:"arguments,omitempty"`
+	Result     string `json:"result,omitempty"`
+	Definition string `json:"definition"`
+}
+
+// snapshotSchemaQuery reads the database name and whether the schema exists.
+const snapshotSchemaQuery = `
+	SELECT current_database(), EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)`
+
+// snapshotColumnsQuery reads the columns of the tables and partitioned
+// tables of a schema, skipping partitions and members of extensions, with
+// the column expressions of describeColumnsQuery. A table without columns
+// yields one row with an empty column name. The serial type is given for
+// integer columns whose default is nextval of a sequence they own.
+const snapshotColumnsQuery = `
+	SELECT
+		c.relname,
+		CASE WHEN c.relkind = 'p' THEN pg_get_partkeydef(c.oid) ELSE '' END,
+		COALESCE(a.attname::text, ''),
+		COALESCE(` + columnDataType + `, ''),
+		COALESCE(NOT a.attnotnull, true),
+		COALESCE(` + columnDefault + `, ''),
+		COALESCE(` + columnIdentity + `, ''),
+		COALESCE(` + columnGenerated + `, ''),
+		COALESCE(` + columnGenerationExpression + `, ''),
+		COALESCE((
+			SELECT CASE a.atttypid
+				WHEN 'int2'::regtype THEN 'smallserial'
+				WHEN 'int4'::regtype THEN 'serial'
+				WHEN 'int8'::regtype THEN 'bigserial'
+			END
+			FROM pg_depend ds
+			JOIN pg_depend os
+				ON os.classid = 'pg_class'::regclass AND os.objid = ds.refobjid AND os.deptype = 'a'
+				AND os.refclassid = 'pg_class'::regclass AND os.refobjid = a.attrelid AND os.refobjsubid = a.attnum
+			WHERE ds.classid = 'pg_attrdef'::regclass AND ds.objid = d.oid AND ds.refclassid = 'pg_class'::regclass
+				AND ` + columnDefault + ` LIKE 'nextval(%'
+			LIMIT 1
+		), '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE n.nspname = $1
+		AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend e
+			WHERE e.classid = 'pg_class'::regclass AND e.objid = c.oid AND e.deptype = 'e'
+		)
+	ORDER BY c.relname, a.attnum`
+
+// snapshotConstraintsQuery reads the constraints declared on the tables of
+// a schema, leaving out those inherited from a parent.
+const snapshotConstraintsQuery = `
+	SELECT c.relname, con.conname, con.contype::text, pg_get_constraintdef(con.oid)
+	FROM pg_constraint con
+	JOIN pg_class c ON c.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1
+		AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		AND con.contype IN ('p', 'u', 'f', 'c', 'x')
+		AND con.conislocal AND con.conparentid = 0
+	ORDER BY c.relname, con.conname`
+
+// snapshotIndexesQuery reads the indexes of the tables of a schema that are
+// not created by a constraint, as indexDefinition renders them.
+// pg_get_indexdef always qualifies the table, so the qualification is
+// removed.
+const snapshotIndexesQuery = `
+	SELECT
+		c.relname,
+		ic.relname,
+		replace(
+			` + indexDefinition + `,
+			format(' ON %I.%I ', n.nspname, c.relname),
+			format(' ON %I ', c.relname)
+		)
+	FROM pg_index i
+	JOIN pg_class ic ON ic.oid = i.indexrelid
+	JOIN pg_class c ON c.oid = i.indrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1
+		AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		AND ` + indexNotFromConstraint + `
+	ORDER BY c.relname, ic.relname`
+
+// snapshotViewsQuery reads the views and materialized views of a schema,
+// skipping members of extensions.
+const snapshotViewsQuery = `
+	SELECT c.relname, c.relkind = 'm', pg_get_viewdef(c.oid, true)
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND c.relkind IN ('v', 'm')
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend e
+			WHERE e.classid = 'pg_class'::regclass AND e.objid = c.oid AND e.deptype = 'e'
+		)
+	ORDER BY c.relname`
+
+// snapshotFunctionsQuery reads the functions and procedures of a schema,
+// skipping aggregates and members of extensions like ddlFunctionsQuery. The
+// last two columns are the qualified and unqualified name as they appear
+// in the definition.
+const snapshotFunctionsQuery = `
+	SELECT
+		format('%I(%s)', p.proname, pg_get_function_identity_arguments(p.oid)),
+		CASE WHEN p.prokind = 'p' THEN 'procedure' ELSE 'function' END,
+		pg_get_function_arguments(p.oid),
+		COALESCE(pg_get_function_result(p.oid), ''),
+		pg_get_functiondef(p.oid),
+		format(' %I.%I(', n.nspname, p.proname),
+		format(' %I(', p.proname)
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE n.nspname = $1
+		AND p.prokind <> 'a'
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend e
+			WHERE e.classid = 'pg_proc'::regclass AND e.objid = p.oid AND e.deptype = 'e'
+		)
+	ORDER BY 1`
+
+// SnapshotSchema captures the structure of a schema. The catalog is read in
+// one read-only, repeatable-read transaction with the search path set to
+// the schema, so the snapshot is consistent and its definitions leave the
+// schema implicit.
+func (c *PostgreSQLClientImpl) SnapshotSchema(ctx context.Context, schema string) (*SchemaSnapshot, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	snapshot := &SchemaSnapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Schema:    schema,
+		Tables:    []*SnapshotTable{},
+		Views:     []*SnapshotView{},
+		Functions: []*SnapshotFunction{},
+	}
+	var exists bool
+	if err := tx.QueryRowContext(ctx, snapshotSchemaQuery, schema).Scan(&snapshot.Database, &exists); err != nil {
+		return nil, fmt.Errorf("failed to get snapshot schema: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("schema %s: %w", schema, ErrSchemaNotFound)
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('search_path', $1, true)",
+		pgx.Identifier{schema}.Sanitize()); err != nil {
+		return nil, fmt.Errorf("failed to set snapshot search path: %w", err)
+	}
+
+	tables, err := snapshot.loadColumns(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := snapshot.loadConstraints(ctx, tx, tables); err != nil {
+		return nil, err
+	}
+	if err := snapshot.loadIndexes(ctx, tx, tables); err != nil {
+		return nil, err
+	}
+	if err := snapshot.loadViews(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := snapshot.loadFunctions(ctx, tx); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ParseSchemaSnapshot reads a snapshot in the JSON form SnapshotSchema
+// returns. Snapshots of another format version are rejected rather than
+// compared field by field with missing or renamed attributes.
+func ParseSchemaSnapshot(data []byte) (*SchemaSnapshot, error) {
+	var snapshot SchemaSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: version %d, expected %d", ErrUnsupportedSnapshot, snapshot.Version, SnapshotVersion)
+	}
+	if snapshot.Schema == "" {
+		return nil, fmt.Errorf("%w: schema is missing", ErrInvalidSnapshot)
+	}
+	return &snapshot, nil
+}
+
+// loadColumns adds the tables of the schema with their columns and returns
+// them by name.
+func (s *SchemaSnapshot) loadColumns(ctx context.Context, tx *sql.Tx) (map[string]*SnapshotTable, error) {
+	rows, err := tx.QueryContext(ctx, snapshotColumnsQuery, s.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tables := make(map[string]*SnapshotTable)
+	for rows.Next() {
+		var tableName, partitionBy, generated, generationExpression string
+		var column SnapshotColumn
+		if err := rows.Scan(
+			&tableName,
+			&partitionBy,
+			&column.Name,
+			&column.DataType,
+			&column.Nullable,
+			&column.Default,
+			&column.Identity,
+			&generated,
+			&generationExpression,
+			&column.Serial,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot column row: %w", err)
+		}
+		column.Generated = generationClause(generated, generationExpression)
+		table := tables[tableName]
+		if table == nil {
+			table = &SnapshotTable{
+				Name:        tableName,
+				PartitionBy: partitionBy,
+				Columns:     []*SnapshotColumn{},
+				Constraints: []*SnapshotConstraint{},
+				Indexes:     []*SnapshotIndex{},
+			}
+			tables[tableName] = table
+			s.Tables = append(s.Tables, table)
+		}
+		if column.Name != "" {
+			table.Columns = append(table.Columns, &column)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate snapshot column rows: %w", err)
+	}
+	return tables, nil
+}
+
+func (s *SchemaSnapshot) loadConstraints(ctx context.Context, tx *sql.Tx, tables map[string]*SnapshotTable) error {
+	rows, err := tx.QueryContext(ctx, snapshotConstraintsQuery, s.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshot constraints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tableName, contype string
+		var constraint SnapshotConstraint
+		if err := rows.Scan(&tableName, &constraint.Name, &contype, &constraint.Definition); err != nil {
+			return fmt.Errorf("failed to scan snapshot constraint row: %w", err)
+		}
+		constraint.Type = constraintTypes[contype]
+		if table := tables[tableName]; table != nil {
+			table.Constraints = append(table.Constraints, &constraint)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate snapshot constraint rows: %w", err)
+	}
+	return nil
+}
+
+// generationClause renders the GENERATED ALWAYS AS clause of a column from
+// its ColumnInfo.Generated kind and expression, or "" when it is not
+// generated.
+func generationClause(kind, expression string) string {
+	if kind == "" {
+		return ""
+	}
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", expression, strings.ToUpper(kind))
+}
+
+func (s *SchemaSnapshot) loadIndexes(ctx context.Context, tx *sql.Tx, tables map[string]*SnapshotTable) error {
+	rows, err := tx.QueryContext(ctx, snapshotIndexesQuery, s.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshot indexes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tableName string
+		var index SnapshotIndex
+		if err := rows.Scan(&tableName, &index.Name, &index.Definition); err != nil {
+			return fmt.Errorf("failed to scan snapshot index row: %w", err)
+		}
+		if table := tables[tableName]; table != nil {
+			table.Indexes = append(table.Indexes, &index)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate snapshot index rows: %w", err)
+	}
+	return nil
+}
+
+func (s *SchemaSnapshot) loadViews(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, snapshotViewsQuery, s.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshot views: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var view SnapshotView
+		if err := rows.Scan(&view.Name, &view.Materialized, &view.Definition); err != nil {
+			return fmt.Errorf("failed to scan snapshot view row: %w", err)
+		}
+		view.Definition = strings.TrimSuffix(strings.TrimSpace(view.Definition), ";")
+		s.Views = append(s.Views, &view)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate snapshot view rows: %w", err)
+	}
+	return nil
+}
+
+func (s *SchemaSnapshot) loadFunctions(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, snapshotFunctionsQuery, s.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshot functions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var function SnapshotFunction
+		var qualified, unqualified string
+		if err := rows.Scan(
+			&function.Name, &function.Kind, &function.Arguments, &function.Result, &function.Definition, &qualified, &unqualified,
+		); err != nil {
+			return fmt.Errorf("failed to scan snapshot function row: %w", err)
+		}
+		function.Definition = strings.Replace(strings.TrimSpace(function.Definition), qualified, unqualified, 1)
+		s.Functions = append(s.Functions, &function)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate snapshot function rows: %w", err)
+	}
+	return nil
+}
+
+// SnapshotSchema captures the structure of a schema, public when empty, to
+// be stored and compared with the live schema later by DiffSchema.
+func (a *App) SnapshotSchema(ctx context.Context, schema string) (*SchemaSnapshot, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to snapshot schema: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Capturing schema snapshot", "schema", schema)
+
+	snapshot, err := a.client.SnapshotSchema(ctx, schema)
+	if err != nil {
+		a.logger.Error("Failed to snapshot schema", "error", err, "schema", schema)
+		return nil, fmt.Errorf("failed to snapshot schema: %w", err)
+	}
+
+	a.logger.Debug("Successfully captured schema snapshot", "schema", schema, "table_count", len(snapshot.Tables),
+		"view_count", len(snapshot.Views), "function_count", len(snapshot.Functions))
+	return snapshot, nil
+}
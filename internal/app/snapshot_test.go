@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLClient_SnapshotSchemaWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	snapshot, err := client.SnapshotSchema(context.Background(), "public")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, snapshot)
+}
+
+func TestParseSchemaSnapshot(t *testing.T) {
+	snapshot, _ := testSnapshots()
+	snapshot.Version = SnapshotVersion
+	snapshot.CreatedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	parsed, err := ParseSchemaSnapshot(data)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, parsed)
+
+	tests := []struct {
+		name     string
+		data     string
+		expected error
+		message  string
+	}{
+		{"not JSON", `{"version":`, ErrInvalidSnapshot, ""},
+		{"other version", `{"version":2,"schema":"public"}`, ErrUnsupportedSnapshot, "version 2, expected 1"},
+		{"no version", `{"schema":"public"}`, ErrUnsupportedSnapshot, "version 0"},
+		{"no schema", `{"version":1,"tables":[]}`, ErrInvalidSnapshot, "schema is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := ParseSchemaSnapshot([]byte(tt.data))
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.Nil(t, snapshot)
+		})
+	}
+}
+
+func TestGenerationClause(t *testing.T) {
+	assert.Empty(t, generationClause("", ""))
+	assert.Equal(t, "GENERATED ALWAYS AS ((price * 2)) STORED", generationClause("stored", "(price * 2)"))
+	assert.Equal(t, "GENERATED ALWAYS AS (lower(email)) VIRTUAL", generationClause("virtual", "lower(email)"))
+}
+
+func TestSnapshotQueriesShareIntrospection(t *testing.T) {
+	for _, fragment := range []string{columnDataType, columnDefault, columnIdentity, columnGenerated, columnGenerationExpression} {
+		assert.Contains(t, describeColumnsQuery, fragment)
+		assert.Contains(t, snapshotColumnsQuery, fragment)
+	}
+	for _, query := range []string{listIndexesQuery, ddlIndexesQuery, snapshotIndexesQuery} {
+		assert.Contains(t, query, indexDefinition)
+	}
+}
+
+func TestApp_SnapshotSchema(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+	snapshot, _ := testSnapshots()
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("SnapshotSchema", mock.Anything, DefaultSchema).Return(snapshot, nil)
+	mockClient.On("SnapshotSchema", mock.Anything, "missing").Return(nil, ErrSchemaNotFound)
+	mockClient.On("SnapshotSchema", mock.Anything, "sales").Return(nil, errors.New("connection reset"))
+
+	result, err := app.SnapshotSchema(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, result)
+
+	result, err = app.SnapshotSchema(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	assert.Nil(t, result)
+
+	result, err = app.SnapshotSchema(context.Background(), "sales")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to snapshot schema")
+	assert.Nil(t, result)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Trigger timings reported in TriggerInfo.Timing.
+const (
+	TriggerTimingBefore    = "before"
+	TriggerTimingAfter     = "after"
+	TriggerTimingInsteadOf = "instead of"
+)
+
+// Trigger events reported in TriggerInfo.Events.
+const (
+	TriggerEventInsert   = "insert"
+	TriggerEventUpdate   = "update"
+	TriggerEventDelete   = "delete"
+	TriggerEventTruncate = "truncate"
+)
+
+// TriggerInfo describes a user-defined trigger on a table or view.
+// Triggers that PostgreSQL creates internally to enforce foreign keys are
+// not listed. Level is "row" or "statement"; UpdateColumns lists the
+// columns of an UPDATE OF trigger and Condition its WHEN clause. Function
+// is the quoted, schema-qualified trigger function, and Definition the
+// CREATE TRIGGER statement as rendered by pg_get_triggerdef. Enabled is
+// false for triggers disabled with ALTER TABLE ... DISABLE TRIGGER.
+type TriggerInfo struct {
+	Name          string   `json:"name"`
+	Timing        string   `json:"timing"`
+	Events        []string `json:"events"`
+	Level         string   `json:"level"`
+	UpdateColumns []string `json:"update_columns,omitempty"`
+	Condition     string   `json:"condition,omitempty"`
+	Function      string   `json:"function"`
+	Enabled       bool     `json:"enabled"`
+	Definition    string   `json:"definition"`
+}
+
+// pg_trigger.tgtype bits, from the server's catalog/pg_trigger.h.
+const (
+	triggerTypeRow      = 1 << 0
+	triggerTypeBefore   = 1 << 1
+	triggerTypeInsert   = 1 << 2
+	triggerTypeDelete   = 1 << 3
+	triggerTypeUpdate   = 1 << 4
+	triggerTypeTruncate = 1 << 5
+	triggerTypeInstead  = 1 << 6
+)
+
+// decodeTriggerType fills the timing, events, and level of a trigger from
+// its pg_trigger.tgtype bitmask.
+func decodeTriggerType(trigger *TriggerInfo, tgtype int) {
+	switch {
+	case tgtype&triggerTypeInstead != 0:
+		trigger.Timing = TriggerTimingInsteadOf
+	case tgtype&triggerTypeBefore != 0:
+		trigger.Timing = TriggerTimingBefore
+	default:
+		trigger.Timing = TriggerTimingAfter
+	}
+
+	trigger.Events = []string{}
+	for _, event := range []struct {
+		bit  int
+		name string
+	}{
+		{triggerTypeInsert, TriggerEventInsert},
+		{triggerTypeUpdate, TriggerEventUpdate},
+		{triggerTypeDelete, TriggerEventDelete},
+		{triggerTypeTruncate, TriggerEventTruncate},
+	} {
+		if tgtype&event.bit != 0 {
+			trigger.Events = append(trigger.Events, event.name)
+		}
+	}
+
+	trigger.Level = "statement"
+	if tgtype&triggerTypeRow != 0 {
+		trigger.Level = "row"
+	}
+}
+
+// listTriggersQuery lists the non-internal triggers of a relation in firing
+// order (PostgreSQL fires triggers of the same kind by name). The WHEN
+// clause is cut out of pg_get_triggerdef, the only function that renders it.
+const listTriggersQuery = `
+	SELECT
+		t.tgname,
+		t.tgtype::int,
+		ARRAY(
+			SELECT a.attname
+			FROM unnest(t.tgattr::int2[]) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = t.tgrelid AND a.attnum = k.attnum
+			ORDER BY k.ord
+		),
+		COALESCE(substring(pg_get_triggerdef(t.oid, true) FROM '\sWHEN \((.*)\) EXECUTE '), ''),
+		format('%I.%I', pn.nspname, p.proname),
+		t.tgenabled <> 'D',
+		pg_get_triggerdef(t.oid, true)
+	FROM pg_trigger t
+	JOIN pg_class c ON c.oid = t.tgrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_proc p ON p.oid = t.tgfoid
+	JOIN pg_namespace pn ON pn.oid = p.pronamespace
+	WHERE n.nspname = $1 AND c.relname = $2 AND NOT t.tgisinternal
+	ORDER BY t.tgname`
+
+// ListTriggers returns the user-defined triggers of a table or view.
+func (c *PostgreSQLClientImpl) ListTriggers(ctx context.Context, schema, table string) ([]*TriggerInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, listTriggersQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list triggers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	triggers := []*TriggerInfo{}
+	for rows.Next() {
+		var trigger TriggerInfo
+		var tgtype int
+		if err := rows.Scan(
+			&trigger.Name,
+			&tgtype,
+			typeMap.SQLScanner(&trigger.UpdateColumns),
+			&trigger.Condition,
+			&trigger.Function,
+			&trigger.Enabled,
+			&trigger.Definition,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger row: %w", err)
+		}
+		decodeTriggerType(&trigger, tgtype)
+		if len(trigger.UpdateColumns) == 0 {
+			trigger.UpdateColumns = nil
+		}
+		triggers = append(triggers, &trigger)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trigger rows: %w", err)
+	}
+	return triggers, nil
+}
+
+// ListTriggers returns the triggers of a table with their timing, events,
+// and the function each one calls.
+func (a *App) ListTriggers(ctx context.Context, schema, table string) ([]*TriggerInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list triggers: %w", err)
+	}
+
+	if table == "" {
+		return nil, ErrTableRequired
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Listing triggers", "schema", schema, "table", table)
+
+	triggers, err := a.client.ListTriggers(ctx, schema, table)
+	if err != nil {
+		a.logger.Error("Failed to list triggers", "error", err, "schema", schema, "table", table)
+		return nil, fmt.Errorf("failed to list triggers: %w", err)
+	}
+
+	a.logger.Debug("Successfully listed triggers", "count", len(triggers), "schema", schema, "table", table)
+	return triggers, nil
+}
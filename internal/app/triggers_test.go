@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_ListTriggersWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	triggers, err := client.ListTriggers(context.Background(), "public", "orders")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, triggers)
+}
+
+func TestDecodeTriggerType(t *testing.T) {
+	tests := []struct {
+		name   string
+		tgtype int
+		timing string
+		events []string
+		level  string
+	}{
+		{
+			name:   "before insert or update for each row",
+			tgtype: triggerTypeRow | triggerTypeBefore | triggerTypeInsert | triggerTypeUpdate,
+			timing: TriggerTimingBefore,
+			events: []string{TriggerEventInsert, TriggerEventUpdate},
+			level:  "row",
+		},
+		{
+			name:   "after delete for each statement",
+			tgtype: triggerTypeDelete,
+			timing: TriggerTimingAfter,
+			events: []string{TriggerEventDelete},
+			level:  "statement",
+		},
+		{
+			name:   "after truncate",
+			tgtype: triggerTypeTruncate,
+			timing: TriggerTimingAfter,
+			events: []string{TriggerEventTruncate},
+			level:  "statement",
+		},
+		{
+			name:   "instead of insert on a view",
+			tgtype: triggerTypeRow | triggerTypeInstead | triggerTypeInsert,
+			timing: TriggerTimingInsteadOf,
+			events: []string{TriggerEventInsert},
+			level:  "row",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trigger TriggerInfo
+			decodeTriggerType(&trigger, tt.tgtype)
+			assert.Equal(t, tt.timing, trigger.Timing)
+			assert.Equal(t, tt.events, trigger.Events)
+			assert.Equal(t, tt.level, trigger.Level)
+		})
+	}
+}
+
+func TestApp_ListTriggers(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*TriggerInfo{{
+		Name:          "orders_touch",
+		Timing:        TriggerTimingBefore,
+		Events:        []string{TriggerEventUpdate},
+		Level:         "row",
+		UpdateColumns: []string{"status"},
+		Function:      "public.touch_updated_at",
+		Enabled:       true,
+		Definition: "CREATE TRIGGER orders_touch BEFORE UPDATE OF status ON public.orders " +
+			"FOR EACH ROW EXECUTE FUNCTION touch_updated_at()",
+	}}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListTriggers", mock.Anything, DefaultSchema, "orders").Return(expected, nil)
+
+	triggers, err := app.ListTriggers(context.Background(), "", "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, triggers)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListTriggersErrors(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListTriggers", mock.Anything, "public", "orders").Return(nil, errors.New("permission denied"))
+
+	_, err := app.ListTriggers(context.Background(), "public", "")
+	assert.ErrorIs(t, err, ErrTableRequired)
+
+	triggers, err := app.ListTriggers(context.Background(), "public", "orders")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list triggers")
+	assert.Nil(t, triggers)
+	mockClient.AssertExpectations(t)
+}
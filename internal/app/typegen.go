@@ -0,0 +1,450 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GenerateTypesOptions selects what GenerateTypes generates. Table, when
+// set, restricts it to one table; Template, when set, replaces the layout of
+// the language's template set while keeping its type mapping and naming.
+type GenerateTypesOptions struct {
+	Schema   string
+	Table    string
+	Language string
+	Template string
+}
+
+// CodegenTable is a table, view, materialized view, or foreign table with
+// the columns type generation maps. Type is one of the TableType constants.
+type CodegenTable struct {
+	Schema      string           `json:"schema"`
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Description string           `json:"description,omitempty"`
+	Columns     []*CodegenColumn `json:"columns"`
+}
+
+// CodegenColumn is a column with its type broken down for type generation.
+// DataType is the declared type as format_type renders it; BaseType is the
+// name of the underlying type once domains and arrays are unwrapped, in
+// schema TypeSchema, with its pg_type.typcategory in Category. Dimensions
+// counts the array dimensions. Precision and Scale are set for numeric
+// columns and Length for character columns declared with a modifier.
+// EnumValues lists the labels of an enum BaseType in sort order and is nil
+// for other types. Nullable also accounts for NOT NULL domains.
+type CodegenColumn struct {
+	Name        string   `json:"name"`
+	DataType    string   `json:"data_type"`
+	BaseType    string   `json:"base_type"`
+	TypeSchema  string   `json:"type_schema"`
+	Category    string   `json:"category"`
+	Dimensions  int      `json:"dimensions,omitempty"`
+	Nullable    bool     `json:"nullable"`
+	HasDefault  bool     `json:"has_default,omitempty"`
+	Precision   int      `json:"precision,omitempty"`
+	Scale       int      `json:"scale,omitempty"`
+	Length      int      `json:"length,omitempty"`
+	EnumValues  []string `json:"enum_values,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// TypeModel is the data a type template is executed with: the enums the
+// tables use and one type per table, named and typed for Language. Imports
+// lists what the generated code needs, in the language's own terms (package
+// paths for Go, import statements for Python).
+type TypeModel struct {
+	Language string
+	Schema   string
+	Imports  []string
+	Enums    []*TypeEnum
+	Types    []*TypeStruct
+}
+
+// TypeEnum is an enum type used by the tables. Schema and Type name the
+// PostgreSQL type, Name the generated one.
+type TypeEnum struct {
+	Schema  string
+	Type    string
+	Name    string
+	Values  []string
+	Members []*TypeEnumMember
+}
+
+// TypeEnumMember is an enum label with the identifier generated for it.
+type TypeEnumMember struct {
+	Name  string
+	Value string
+}
+
+// TypeStruct is the type generated for a table.
+type TypeStruct struct {
+	Table  *CodegenTable
+	Name   string
+	Fields []*TypeField
+}
+
+// TypeField is the field generated for a column, with its name and type in
+// the target language.
+type TypeField struct {
+	Column *CodegenColumn
+	Name   string
+	Type   string
+}
+
+// GeneratedTypes is the code GenerateTypes rendered, with the model it was
+// rendered from.
+type GeneratedTypes struct {
+	*TypeModel
+	Code string
+}
+
+// codegenTablesQuery lists the relations of a schema types are generated
+// for, skipping partitions, which share their parent's columns.
+const codegenTablesQuery = `
+	SELECT
+		c.relname,
+		CASE c.relkind
+			WHEN 'r' THEN 'table'
+			WHEN 'p' THEN 'partitioned table'
+			WHEN 'v' THEN 'view'
+			WHEN 'm' THEN 'materialized view'
+			WHEN 'f' THEN 'foreign table'
+		END,
+		COALESCE(obj_description(c.oid, 'pg_class'), '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2)
+		AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND NOT c.relispartition
+	ORDER BY c.relname`
+
+// codegenColumnsQuery reads the columns of the relations codegenTablesQuery
+// lists. A domain is replaced by its base type and modifier, and an array by
+// its element type; attndims is not kept for views or CREATE TABLE AS, so an
+// array counts at least one dimension.
+const codegenColumnsQuery = `
+	SELECT
+		c.relname,
+		a.attname,
+		format_type(a.atttypid, a.atttypmod),
+		et.typname,
+		en.nspname,
+		et.typcategory::text,
+		et.typtype::text,
+		CASE WHEN bt.typcategory = 'A' AND bt.typelem <> 0 THEN GREATEST(a.attndims, 1) ELSE 0 END,
+		NOT a.attnotnull AND NOT (t.typtype = 'd' AND t.typnotnull),
+		a.atthasdef OR a.attidentity <> '' OR a.attgenerated <> '',
+		CASE WHEN t.typtype = 'd' THEN t.typtypmod ELSE a.atttypmod END,
+		ARRAY(SELECT e.enumlabel::text FROM pg_enum e WHERE e.enumtypid = et.oid ORDER BY e.enumsortorder),
+		COALESCE(col_description(c.oid, a.attnum), '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+	JOIN pg_type t ON t.oid = a.atttypid
+	JOIN pg_type bt ON bt.oid = CASE WHEN t.typtype = 'd' THEN t.typbasetype ELSE t.oid END
+	JOIN pg_type et ON et.oid = CASE WHEN bt.typcategory = 'A' AND bt.typelem <> 0 THEN bt.typelem ELSE bt.oid END
+	JOIN pg_namespace en ON en.oid = et.typnamespace
+	WHERE n.nspname = $1 AND ($2 = '' OR c.relname = $2)
+		AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND NOT c.relispartition
+	ORDER BY c.relname, a.attnum`
+
+// ListCodegenTables returns the tables, views, materialized views, and
+// foreign tables of a schema, or the one named table, with their columns
+// broken down for type generation.
+func (c *PostgreSQLClientImpl) ListCodegenTables(ctx context.Context, schema, table string) ([]*CodegenTable, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, codegenTablesQuery, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list codegen tables: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tables := []*CodegenTable{}
+	byName := make(map[string]*CodegenTable)
+	for rows.Next() {
+		t := &CodegenTable{Schema: schema, Columns: []*CodegenColumn{}}
+		if err := rows.Scan(&t.Name, &t.Type, &t.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan codegen table row: %w", err)
+		}
+		tables = append(tables, t)
+		byName[t.Name] = t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate codegen table rows: %w", err)
+	}
+
+	if len(tables) == 0 {
+		if table != "" {
+			return nil, fmt.Errorf("table %s.%s: %w", schema, table, ErrTableNotFound)
+		}
+		var exists bool
+		if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)",
+			schema).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check schema: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("schema %s: %w", schema, ErrSchemaNotFound)
+		}
+		return tables, nil
+	}
+
+	if err := loadCodegenColumns(ctx, db, schema, table, byName); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// loadCodegenColumns adds the columns of codegenColumnsQuery to the tables
+// they belong to.
+func loadCodegenColumns(ctx context.Context, db *sql.DB, schema, table string, tables map[string]*CodegenTable) error {
+	rows, err := db.QueryContext(ctx, codegenColumnsQuery, schema, table)
+	if err != nil {
+		return fmt.Errorf("failed to list codegen columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	for rows.Next() {
+		var tableName, typtype string
+		var typmod int
+		column := &CodegenColumn{}
+		if err := rows.Scan(
+			&tableName,
+			&column.Name,
+			&column.DataType,
+			&column.BaseType,
+			&column.TypeSchema,
+			&column.Category,
+			&typtype,
+			&column.Dimensions,
+			&column.Nullable,
+			&column.HasDefault,
+			&typmod,
+			typeMap.SQLScanner(&column.EnumValues),
+			&column.Description,
+		); err != nil {
+			return fmt.Errorf("failed to scan codegen column row: %w", err)
+		}
+		if typtype == "e" {
+			if column.EnumValues == nil {
+				column.EnumValues = []string{}
+			}
+		} else {
+			column.EnumValues = nil
+		}
+		column.applyTypmod(typmod)
+		if t := tables[tableName]; t != nil {
+			t.Columns = append(t.Columns, column)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate codegen column rows: %w", err)
+	}
+	return nil
+}
+
+// applyTypmod decodes the type modifier of numeric and character types
+// into Precision, Scale, and Length. Both carry a 4-byte header; numeric
+// packs the precision in the high 16 bits and the scale, which may be
+// negative since PostgreSQL 15, in the low 11.
+func (c *CodegenColumn) applyTypmod(typmod int) {
+	if typmod < 4 {
+		return
+	}
+	switch c.BaseType {
+	case "numeric":
+		c.Precision = ((typmod - 4) >> 16) & 0xffff
+		c.Scale = (((typmod - 4) & 0x7ff) ^ 1024) - 1024
+	case "varchar", "bpchar":
+		c.Length = typmod - 4
+	}
+}
+
+// enumKey identifies the enum type of a column across schemas.
+func (c *CodegenColumn) enumKey() string {
+	return c.TypeSchema + "." + c.BaseType
+}
+
+// typeMapper collects what mapping the columns of a model needs: the enums
+// it refers to, keyed by enumKey, and the imports, as module to imported
+// names (nil for languages that import whole modules).
+type typeMapper struct {
+	enums   map[string]*TypeEnum
+	imports map[string]map[string]bool
+}
+
+// use records an import of names from module.
+func (m *typeMapper) use(module string, names ...string) {
+	if m.imports[module] == nil {
+		m.imports[module] = make(map[string]bool)
+	}
+	for _, name := range names {
+		m.imports[module][name] = true
+	}
+}
+
+// enum returns the enum a column's base type is, or nil.
+func (m *typeMapper) enum(column *CodegenColumn) *TypeEnum {
+	if column.EnumValues == nil {
+		return nil
+	}
+	return m.enums[column.enumKey()]
+}
+
+// buildTypeModel names and types the tables and the enums they use for a
+// language. Enums come first, sorted by schema and name, then the tables in
+// order; names that collide once converted get a numeric suffix.
+func buildTypeModel(lang *typeLanguage, language, schema string, tables []*CodegenTable) *TypeModel {
+	model := &TypeModel{Language: language, Schema: schema, Imports: []string{}, Enums: []*TypeEnum{}, Types: []*TypeStruct{}}
+	m := &typeMapper{enums: make(map[string]*TypeEnum), imports: make(map[string]map[string]bool)}
+
+	for _, t := range tables {
+		for _, column := range t.Columns {
+			if column.EnumValues != nil && m.enums[column.enumKey()] == nil {
+				e := &TypeEnum{Schema: column.TypeSchema, Type: column.BaseType, Values: column.EnumValues}
+				m.enums[column.enumKey()] = e
+				model.Enums = append(model.Enums, e)
+			}
+		}
+	}
+	slices.SortFunc(model.Enums, func(a, b *TypeEnum) int {
+		return strings.Compare(a.Schema+"."+a.Type, b.Schema+"."+b.Type)
+	})
+
+	used := make(map[string]bool)
+	for _, e := range model.Enums {
+		name := e.Type
+		if e.Schema != schema {
+			name = e.Schema + "_" + e.Type
+		}
+		e.Name = uniqueName(used, lang.typeName(name))
+		members := make(map[string]bool)
+		for _, value := range e.Values {
+			member := value
+			if lang.memberName != nil {
+				member = uniqueName(members, lang.memberName(e, value))
+			}
+			e.Members = append(e.Members, &TypeEnumMember{Name: member, Value: value})
+		}
+	}
+
+	for _, t := range tables {
+		s := &TypeStruct{Table: t, Name: uniqueName(used, lang.typeName(t.Name)), Fields: []*TypeField{}}
+		fields := make(map[string]bool)
+		for _, column := range t.Columns {
+			field := &TypeField{Column: column, Name: uniqueName(fields, lang.fieldName(column.Name))}
+			field.Type = lang.fieldType(m, field)
+			s.Fields = append(s.Fields, field)
+		}
+		model.Types = append(model.Types, s)
+	}
+
+	if lang.imports != nil {
+		model.Imports = lang.imports(model, m.imports)
+	}
+	return model
+}
+
+// uniqueName returns name, or name followed by the first number that makes
+// it unique among used, and marks the result as used.
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// parseTypeTemplate parses a type template with the helper functions every
+// template can call.
+func parseTypeTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("types").Funcs(typeTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// renderTypes executes a type template with a model and formats the result
+// the way the language's tools would, keeping the unformatted output when
+// formatting fails, e.g. on the output of a custom template.
+func renderTypes(tmpl *template.Template, lang *typeLanguage, model *TypeModel) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, model); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	code := buf.Bytes()
+	if lang.format != nil {
+		if formatted, err := lang.format(code); err == nil {
+			code = formatted
+		}
+	}
+	return strings.TrimRight(string(code), "\n") + "\n", nil
+}
+
+// GenerateTypes generates type definitions for the tables of a schema, or
+// for one table, in one of TypeLanguages: Go structs, TypeScript
+// interfaces, a JSON Schema, or Pydantic models. The language's template is
+// replaced by opts.Template when it is set.
+func (a *App) GenerateTypes(ctx context.Context, opts GenerateTypesOptions) (*GeneratedTypes, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to generate types: %w", err)
+	}
+
+	if opts.Language == "" {
+		return nil, ErrLanguageRequired
+	}
+	if opts.Schema == "" {
+		opts.Schema = DefaultSchema
+	}
+
+	lang, ok := typeLanguages[opts.Language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (supported: %s)", ErrUnsupportedLanguage, opts.Language, strings.Join(TypeLanguages(), ", "))
+	}
+	text := lang.template
+	if opts.Template != "" {
+		text = opts.Template
+	}
+	tmpl, err := parseTypeTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("Generating types", "schema", opts.Schema, "table", opts.Table, "language", opts.Language,
+		"custom_template", opts.Template != "")
+
+	tables, err := a.client.ListCodegenTables(ctx, opts.Schema, opts.Table)
+	if err != nil {
+		a.logger.Error("Failed to list tables for type generation", "error", err, "schema", opts.Schema, "table", opts.Table)
+		return nil, fmt.Errorf("failed to generate types: %w", err)
+	}
+
+	model := buildTypeModel(lang, opts.Language, opts.Schema, tables)
+	code, err := renderTypes(tmpl, lang, model)
+	if err != nil {
+		a.logger.Error("Failed to render types", "error", err, "language", opts.Language)
+		return nil, err
+	}
+
+	a.logger.Debug("Successfully generated types", "type_count", len(model.Types), "enum_count", len(model.Enums),
+		"schema", opts.Schema, "language", opts.Language)
+	return &GeneratedTypes{TypeModel: model, Code: code}, nil
+}
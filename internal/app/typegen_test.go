@@ -0,0 +1,315 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testCodegenTables returns an orders table covering the type mappings and
+// a view whose names need converting.
+func testCodegenTables() []*CodegenTable {
+	status := []string{"new", "paid", "in transit"}
+	return []*CodegenTable{
+		{Schema: "public", Name: "orders", Type: TableTypeTable, Description: "Customer orders", Columns: []*CodegenColumn{
+			{Name: "id", DataType: "bigint", BaseType: "int8", TypeSchema: "pg_catalog", Category: "N", HasDefault: true},
+			{Name: "status", DataType: "order_status", BaseType: "order_status", TypeSchema: "public", Category: "E", EnumValues: status},
+			{Name: "total", DataType: "numeric(10,2)", BaseType: "numeric", TypeSchema: "pg_catalog", Category: "N", Precision: 10, Scale: 2},
+			{Name: "quantity", DataType: "numeric(5,0)", BaseType: "numeric", TypeSchema: "pg_catalog", Category: "N", Nullable: true, Precision: 5},
+			{Name: "note", DataType: "character varying(200)", BaseType: "varchar", TypeSchema: "pg_catalog", Category: "S", Nullable: true, Length: 200, Description: "Free text"},
+			{Name: "tags", DataType: "text[]", BaseType: "text", TypeSchema: "pg_catalog", Category: "S", Dimensions: 1},
+			{Name: "placed_at", DataType: "timestamp with time zone", BaseType: "timestamptz", TypeSchema: "pg_catalog", Category: "D"},
+			{Name: "metadata", DataType: "jsonb", BaseType: "jsonb", TypeSchema: "pg_catalog", Category: "U", Nullable: true},
+			{Name: "customer_id", DataType: "uuid", BaseType: "uuid", TypeSchema: "pg_catalog", Category: "U", Nullable: true},
+		}},
+		{Schema: "public", Name: "order audit", Type: TableTypeView, Columns: []*CodegenColumn{
+			{Name: "orderId", DataType: "integer", BaseType: "int4", TypeSchema: "pg_catalog", Category: "N", Nullable: true},
+			{Name: "class", DataType: "order_status[]", BaseType: "order_status", TypeSchema: "public", Category: "E", Nullable: true, Dimensions: 1, EnumValues: status},
+		}},
+	}
+}
+
+func TestPostgreSQLClient_ListCodegenTablesWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	tables, err := client.ListCodegenTables(context.Background(), "public", "")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, tables)
+}
+
+func TestCodegenColumn_ApplyTypmod(t *testing.T) {
+	tests := []struct {
+		baseType string
+		typmod   int
+		expected CodegenColumn
+	}{
+		{"numeric", 10<<16 | 2 + 4, CodegenColumn{Precision: 10, Scale: 2}},
+		{"numeric", 5<<16 + 4, CodegenColumn{Precision: 5}},
+		{"numeric", 3<<16 | 0x7fe + 4, CodegenColumn{Precision: 3, Scale: -2}},
+		{"numeric", -1, CodegenColumn{}},
+		{"varchar", 204, CodegenColumn{Length: 200}},
+		{"bpchar", 5, CodegenColumn{Length: 1}},
+		{"timestamptz", 3, CodegenColumn{}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.baseType, tt.typmod), func(t *testing.T) {
+			column := CodegenColumn{BaseType: tt.baseType}
+			column.applyTypmod(tt.typmod)
+			tt.expected.BaseType = tt.baseType
+			assert.Equal(t, tt.expected, column)
+		})
+	}
+}
+
+func TestBuildTypeModel(t *testing.T) {
+	tables := testCodegenTables()
+	tables = append(tables, &CodegenTable{Schema: "public", Name: "OrderStatus", Type: TableTypeTable, Columns: []*CodegenColumn{
+		{Name: "state", DataType: "audit.state", BaseType: "state", TypeSchema: "audit", Category: "E", EnumValues: []string{"", "1st"}},
+	}})
+
+	model := buildTypeModel(typeLanguages[TypeLanguageGo], TypeLanguageGo, "public", tables)
+	require.Len(t, model.Enums, 2)
+	assert.Equal(t, "AuditState", model.Enums[0].Name)
+	assert.Equal(t, []*TypeEnumMember{
+		{Name: "AuditStateEmpty", Value: ""},
+		{Name: "AuditState1st", Value: "1st"},
+	}, model.Enums[0].Members)
+	assert.Equal(t, "OrderStatus", model.Enums[1].Name)
+
+	require.Len(t, model.Types, 3)
+	assert.Equal(t, "OrderStatus2", model.Types[2].Name, "names are unique across enums and tables")
+	assert.Equal(t, "AuditState", model.Types[2].Fields[0].Type)
+	assert.Equal(t, []string{"encoding/json", "time"}, model.Imports)
+}
+
+func TestGoFieldTypeDateTime(t *testing.T) {
+	tests := []struct {
+		baseType string
+		expected string
+	}{
+		{"date", "time.Time"},
+		{"timestamptz", "time.Time"},
+		{"time", "string"},
+		{"timetz", "string"},
+		{"interval", "string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseType, func(t *testing.T) {
+			column := &CodegenColumn{Name: "at", BaseType: tt.baseType, TypeSchema: "pg_catalog", Category: "D"}
+			if tt.baseType == "interval" {
+				column.Category = "T"
+			}
+			m := &typeMapper{imports: map[string]map[string]bool{}}
+			assert.Equal(t, tt.expected, goFieldType(m, &TypeField{Column: column}))
+		})
+	}
+}
+
+func TestApp_GenerateTypesGo(t *testing.T) {
+	expected := "// Code generated by generate_types from schema public. DO NOT EDIT.\n" +
+		"\n" +
+		"package models\n" +
+		"\n" +
+		"import (\n" +
+		"\t\"encoding/json\"\n" +
+		"\t\"time\"\n" +
+		")\n" +
+		"\n" +
+		"// OrderStatus is the public.order_status enum.\n" +
+		"type OrderStatus string\n" +
+		"\n" +
+		"const (\n" +
+		"\tOrderStatusNew       OrderStatus = \"new\"\n" +
+		"\tOrderStatusPaid      OrderStatus = \"paid\"\n" +
+		"\tOrderStatusInTransit OrderStatus = \"in transit\"\n" +
+		")\n" +
+		"\n" +
+		"// Orders is a row of the public.orders table.\n" +
+		"//\n" +
+		"// Customer orders\n" +
+		"type Orders struct {\n" +
+		"\tID       int64       `json:\"id\" db:\"id\"`\n" +
+		"\tStatus   OrderStatus `json:\"status\" db:\"status\"`\n" +
+		"\tTotal    string      `json:\"total\" db:\"total\"`\n" +
+		"\tQuantity *int32      `json:\"quantity\" db:\"quantity\"`\n" +
+		"\t// Free text\n" +
+		"\tNote       *string         `json:\"note\" db:\"note\"`\n" +
+		"\tTags       []string        `json:\"tags\" db:\"tags\"`\n" +
+		"\tPlacedAt   time.Time       `json:\"placed_at\" db:\"placed_at\"`\n" +
+		"\tMetadata   json.RawMessage `json:\"metadata\" db:\"metadata\"`\n" +
+		"\tCustomerID *string         `json:\"customer_id\" db:\"customer_id\"`\n" +
+		"}\n" +
+		"\n" +
+		"// OrderAudit is a row of the public.order audit view.\n" +
+		"type OrderAudit struct {\n" +
+		"\tOrderID *int32        `json:\"orderId\" db:\"orderId\"`\n" +
+		"\tClass   []OrderStatus `json:\"class\" db:\"class\"`\n" +
+		"}\n"
+
+	assert.Equal(t, expected, generateTestTypes(t, GenerateTypesOptions{Language: TypeLanguageGo}))
+}
+
+func TestApp_GenerateTypesTypeScript(t *testing.T) {
+	expected := `// Generated by generate_types from schema public.
+
+// The public.order_status enum.
+export type OrderStatus = "new" | "paid" | "in transit";
+
+// A row of the public.orders table.
+// Customer orders
+export interface Orders {
+  id: string;
+  status: OrderStatus;
+  total: string;
+  quantity: number | null;
+  // Free text
+  note: string | null;
+  tags: string[];
+  placed_at: string;
+  metadata: unknown;
+  customer_id: string | null;
+}
+
+// A row of the public.order audit view.
+export interface OrderAudit {
+  orderId: number | null;
+  class: OrderStatus[] | null;
+}
+`
+
+	assert.Equal(t, expected, generateTestTypes(t, GenerateTypesOptions{Language: TypeLanguageTypeScript}))
+}
+
+func TestApp_GenerateTypesJSONSchema(t *testing.T) {
+	code := generateTestTypes(t, GenerateTypesOptions{Language: TypeLanguageJSONSchema})
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal([]byte(code), &document))
+	defs := document["$defs"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"new", "paid", "in transit"}}, defs["order_status"])
+
+	orders := defs["orders"].(map[string]any)
+	assert.Equal(t, "Customer orders", orders["description"])
+	assert.Len(t, orders["required"], 9)
+	properties := orders["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/$defs/order_status"}, properties["status"])
+	assert.Equal(t, map[string]any{"type": "number", "exclusiveMinimum": -1e8, "exclusiveMaximum": 1e8}, properties["total"])
+	assert.Equal(t, map[string]any{"type": []any{"integer", "null"}, "exclusiveMinimum": -1e5, "exclusiveMaximum": 1e5}, properties["quantity"])
+	assert.Equal(t, map[string]any{"type": []any{"string", "null"}, "maxLength": 200.0, "description": "Free text"}, properties["note"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, properties["tags"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, properties["placed_at"])
+	assert.Equal(t, map[string]any{}, properties["metadata"])
+
+	audit := defs["order audit"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": []any{"array", "null"}, "items": map[string]any{"$ref": "#/$defs/order_status"}}, audit["class"])
+}
+
+func TestApp_GenerateTypesPydantic(t *testing.T) {
+	expected := `# Generated by generate_types from schema public.
+
+from datetime import datetime
+from decimal import Decimal
+from enum import Enum
+from typing import Annotated, Any
+from uuid import UUID
+
+from pydantic import BaseModel, Field
+
+
+class OrderStatus(str, Enum):
+    """The public.order_status enum."""
+
+    NEW = "new"
+    PAID = "paid"
+    IN_TRANSIT = "in transit"
+
+
+class Orders(BaseModel):
+    """A row of the public.orders table."""
+
+    # Customer orders
+
+    id: int
+    status: OrderStatus
+    total: Annotated[Decimal, Field(max_digits=10, decimal_places=2)]
+    quantity: Annotated[Decimal, Field(max_digits=5, decimal_places=0)] | None = None
+    # Free text
+    note: Annotated[str, Field(max_length=200)] | None = None
+    tags: list[str]
+    placed_at: datetime
+    metadata: Any | None = None
+    customer_id: UUID | None = None
+
+
+class OrderAudit(BaseModel):
+    """A row of the public.order audit view."""
+
+    order_id: int | None = Field(default=None, alias="orderId")
+    class_: list[OrderStatus] | None = Field(default=None, alias="class")
+`
+
+	assert.Equal(t, expected, generateTestTypes(t, GenerateTypesOptions{Language: TypeLanguagePydantic}))
+}
+
+func TestApp_GenerateTypesTemplate(t *testing.T) {
+	template := `{{range .Types}}{{.Table.Name}}:{{range .Fields}} {{.Name}} {{.Type}};{{end}}
+{{end}}`
+
+	code := generateTestTypes(t, GenerateTypesOptions{Language: TypeLanguageGo, Template: template})
+	assert.Equal(t, "orders: ID int64; Status OrderStatus; Total string; Quantity *int32; Note *string; "+
+		"Tags []string; PlacedAt time.Time; Metadata json.RawMessage; CustomerID *string;\n"+
+		"order audit: OrderID *int32; Class []OrderStatus;\n", code)
+}
+
+// generateTestTypes generates types for testCodegenTables with opts.
+func generateTestTypes(t *testing.T, opts GenerateTypesOptions) string {
+	t.Helper()
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListCodegenTables", mock.Anything, DefaultSchema, "").Return(testCodegenTables(), nil)
+
+	result, err := app.GenerateTypes(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, opts.Language, result.Language)
+	assert.Len(t, result.Types, 2)
+	assert.Len(t, result.Enums, 1)
+	mockClient.AssertExpectations(t)
+	return result.Code
+}
+
+func TestApp_GenerateTypesError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListCodegenTables", mock.Anything, DefaultSchema, "missing").Return(nil, ErrTableNotFound)
+	mockClient.On("ListCodegenTables", mock.Anything, DefaultSchema, "").Return(testCodegenTables(), nil)
+
+	result, err := app.GenerateTypes(context.Background(), GenerateTypesOptions{})
+	assert.ErrorIs(t, err, ErrLanguageRequired)
+	assert.Nil(t, result)
+
+	result, err = app.GenerateTypes(context.Background(), GenerateTypesOptions{Language: "rust"})
+	assert.ErrorIs(t, err, ErrUnsupportedLanguage)
+	assert.Contains(t, err.Error(), "go, jsonschema, pydantic, typescript")
+	assert.Nil(t, result)
+
+	result, err = app.GenerateTypes(context.Background(), GenerateTypesOptions{Language: TypeLanguageGo, Template: "{{range .Types}"})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.Nil(t, result)
+
+	result, err = app.GenerateTypes(context.Background(), GenerateTypesOptions{Language: TypeLanguageGo, Template: "{{.Tables}}"})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.Nil(t, result)
+
+	result, err = app.GenerateTypes(context.Background(), GenerateTypesOptions{Language: TypeLanguageGo, Table: "missing"})
+	assert.ErrorIs(t, err, ErrTableNotFound)
+	assert.Contains(t, err.Error(), "failed to generate types")
+	assert.Nil(t, result)
+}
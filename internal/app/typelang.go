@@ -0,0 +1,728 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Languages accepted by GenerateTypes.
+const (
+	TypeLanguageGo         = "go"
+	TypeLanguageTypeScript = "typescript"
+	TypeLanguageJSONSchema = "jsonschema"
+	TypeLanguagePydantic   = "pydantic"
+)
+
+// typeLanguage is an entry of the type template set: how a language names
+// types, fields, and enum members, how it maps a column's type, what the
+// generated code imports, and the template laying the code out. memberName,
+// imports, and format are optional.
+type typeLanguage struct {
+	typeName   func(name string) string
+	fieldName  func(name string) string
+	memberName func(enum *TypeEnum, value string) string
+	fieldType  func(m *typeMapper, field *TypeField) string
+	imports    func(model *TypeModel, imports map[string]map[string]bool) []string
+	format     func(code []byte) ([]byte, error)
+	template   string
+}
+
+// typeLanguages maps each language to its entry of the template set.
+var typeLanguages = map[string]*typeLanguage{
+	TypeLanguageGo: {
+		typeName:   goName,
+		fieldName:  goName,
+		memberName: func(enum *TypeEnum, value string) string { return enum.Name + goMemberName(value) },
+		fieldType:  goFieldType,
+		imports:    goImports,
+		format:     format.Source,
+		template:   goTypeTemplate,
+	},
+	TypeLanguageTypeScript: {
+		typeName:  func(name string) string { return pascalName(name, nil, "T") },
+		fieldName: typeScriptFieldName,
+		fieldType: typeScriptFieldType,
+		template:  typeScriptTypeTemplate,
+	},
+	TypeLanguageJSONSchema: {
+		typeName:  func(name string) string { return name },
+		fieldName: func(name string) string { return name },
+		fieldType: jsonSchemaFieldType,
+		format:    jsonSchemaFormat,
+		template:  jsonSchemaTypeTemplate,
+	},
+	TypeLanguagePydantic: {
+		typeName:   pydanticTypeName,
+		fieldName:  pydanticFieldName,
+		memberName: func(_ *TypeEnum, value string) string { return pydanticMemberName(value) },
+		fieldType:  pydanticFieldType,
+		imports:    pydanticImports,
+		template:   pydanticTypeTemplate,
+	},
+}
+
+// TypeLanguages returns the languages GenerateTypes supports in sorted order.
+func TypeLanguages() []string {
+	languages := make([]string, 0, len(typeLanguages))
+	for name := range typeLanguages {
+		languages = append(languages, name)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// typeTemplateFuncs are the functions type templates can call: json encodes
+// a value as JSON, which also quotes strings for Go, TypeScript, and Python;
+// comment prefixes each line of a text, e.g. {{comment "// " .Description}}.
+var typeTemplateFuncs = template.FuncMap{
+	"json":    jsonText,
+	"comment": commentLines,
+}
+
+// jsonText encodes v as compact JSON without escaping HTML characters.
+func jsonText(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// commentLines prefixes every line of text.
+func commentLines(prefix, text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(prefix+line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nameWords splits a PostgreSQL name into words at characters identifiers
+// cannot hold and where an upper-case letter follows a lower-case letter or
+// a digit, so both order_items and orderItems give order, items.
+func nameWords(name string) []string {
+	var words []string
+	var word []rune
+	var prev rune
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = []rune{r}
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// pascalWords joins the words of a name with their first letter upper-cased,
+// upper-casing the words found in initialisms entirely.
+func pascalWords(name string, initialisms map[string]bool) string {
+	var b strings.Builder
+	for _, word := range nameWords(name) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return b.String()
+}
+
+// pascalName converts a name to PascalCase, prefixing it when it would
+// otherwise be empty or start with a digit.
+func pascalName(name string, initialisms map[string]bool, prefix string) string {
+	ident := pascalWords(name, initialisms)
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		ident = prefix + ident
+	}
+	return ident
+}
+
+// goInitialisms are the words Go spells in capitals, as golint did.
+var goInitialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true, "dns": true, "eof": true, "guid": true,
+	"html": true, "http": true, "https": true, "id": true, "ip": true, "json": true, "sql": true, "ssh": true,
+	"tcp": true, "tls": true, "ttl": true, "udp": true, "ui": true, "uid": true, "uri": true, "url": true,
+	"utf8": true, "uuid": true, "xml": true,
+}
+
+// goName converts a name to an exported Go identifier.
+func goName(name string) string {
+	return pascalName(name, goInitialisms, "X")
+}
+
+// goMemberName converts an enum label to the suffix of its constant.
+func goMemberName(value string) string {
+	if ident := pascalWords(value, goInitialisms); ident != "" {
+		return ident
+	}
+	return "Empty"
+}
+
+// goTypes maps PostgreSQL types to Go types. numeric, money, interval, and
+// time of day map to string, which keeps their exact text; numeric columns
+// declared without a fractional part and at most 18 digits map to integers.
+var goTypes = map[string]string{
+	"bool":        "bool",
+	"int2":        "int16",
+	"int4":        "int32",
+	"int8":        "int64",
+	"oid":         "uint32",
+	"float4":      "float32",
+	"float8":      "float64",
+	"numeric":     "string",
+	"money":       "string",
+	"date":        "time.Time",
+	"timestamp":   "time.Time",
+	"timestamptz": "time.Time",
+	"time":        "string",
+	"timetz":      "string",
+	"bytea":       "[]byte",
+	"json":        "json.RawMessage",
+	"jsonb":       "json.RawMessage",
+}
+
+// goCategoryTypes maps the pg_type.typcategory of types goTypes does not
+// list; other categories map to string.
+var goCategoryTypes = map[string]string{
+	"B": "bool",
+	"D": "time.Time",
+	"N": "float64",
+}
+
+// goTypeImports are the packages the Go types come from.
+var goTypeImports = map[string]string{
+	"time.Time":       "time",
+	"json.RawMessage": "encoding/json",
+}
+
+// goFieldType maps a column to a Go type: arrays to slices, and nullable
+// columns to pointers unless the type can already be nil.
+func goFieldType(m *typeMapper, field *TypeField) string {
+	column := field.Column
+	t := mappedType(column, goTypes, goCategoryTypes, "string")
+	switch {
+	case m.enum(column) != nil:
+		t = m.enum(column).Name
+	case column.BaseType == "numeric" && column.Precision > 0 && column.Scale == 0 && column.Precision <= 9:
+		t = "int32"
+	case column.BaseType == "numeric" && column.Precision > 0 && column.Scale == 0 && column.Precision <= 18:
+		t = "int64"
+	}
+	if pkg := goTypeImports[t]; pkg != "" {
+		m.use(pkg)
+	}
+
+	if column.Dimensions > 0 {
+		return strings.Repeat("[]", column.Dimensions) + t
+	}
+	if column.Nullable && t != "[]byte" && t != "json.RawMessage" {
+		return "*" + t
+	}
+	return t
+}
+
+// goImports lists the imported packages in sorted order.
+func goImports(_ *TypeModel, imports map[string]map[string]bool) []string {
+	packages := make([]string, 0, len(imports))
+	for pkg := range imports {
+		packages = append(packages, pkg)
+	}
+	slices.Sort(packages)
+	return packages
+}
+
+// mappedType looks a column's base type up in types, then its category in
+// categories, and falls back to fallback.
+func mappedType(column *CodegenColumn, types, categories map[string]string, fallback string) string {
+	if t, ok := types[column.BaseType]; ok {
+		return t
+	}
+	if t, ok := categories[column.Category]; ok {
+		return t
+	}
+	return fallback
+}
+
+// typeScriptIdentifier matches the property names TypeScript accepts
+// without quotes.
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptFieldName keeps the column name, which is what drivers return,
+// quoting it when it is not an identifier.
+func typeScriptFieldName(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	quoted, _ := jsonText(name)
+	return quoted
+}
+
+// typeScriptTypes maps PostgreSQL types to TypeScript types. int8, numeric,
+// and money map to string, as drivers return them to keep their precision,
+// except numeric columns declared without a fractional part and at most 15
+// digits, which fit in a number.
+var typeScriptTypes = map[string]string{
+	"bool":    "boolean",
+	"int2":    "number",
+	"int4":    "number",
+	"oid":     "number",
+	"float4":  "number",
+	"float8":  "number",
+	"int8":    "string",
+	"numeric": "string",
+	"money":   "string",
+	"json":    "unknown",
+	"jsonb":   "unknown",
+}
+
+// typeScriptCategoryTypes maps the pg_type.typcategory of types
+// typeScriptTypes does not list; other categories map to string.
+var typeScriptCategoryTypes = map[string]string{
+	"B": "boolean",
+	"N": "number",
+}
+
+// typeScriptFieldType maps a column to a TypeScript type, with null in the
+// union of nullable columns unless the type is unknown, which includes it.
+func typeScriptFieldType(m *typeMapper, field *TypeField) string {
+	column := field.Column
+	t := mappedType(column, typeScriptTypes, typeScriptCategoryTypes, "string")
+	switch {
+	case m.enum(column) != nil:
+		t = m.enum(column).Name
+	case column.BaseType == "numeric" && column.Precision > 0 && column.Scale == 0 && column.Precision <= 15:
+		t = "number"
+	}
+
+	t += strings.Repeat("[]", column.Dimensions)
+	if column.Nullable && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+// jsonSchemaType is the schema of a property, in the order its keywords
+// are written.
+type jsonSchemaType struct {
+	Ref              string            `json:"$ref,omitempty"`
+	Type             any               `json:"type,omitempty"`
+	Format           string            `json:"format,omitempty"`
+	MaxLength        int               `json:"maxLength,omitempty"`
+	Minimum          json.Number       `json:"minimum,omitempty"`
+	Maximum          json.Number       `json:"maximum,omitempty"`
+	ExclusiveMinimum json.Number       `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum json.Number       `json:"exclusiveMaximum,omitempty"`
+	Items            *jsonSchemaType   `json:"items,omitempty"`
+	AnyOf            []*jsonSchemaType `json:"anyOf,omitempty"`
+	Description      string            `json:"description,omitempty"`
+}
+
+// jsonSchemaTypes maps PostgreSQL types to JSON Schema types, as row_to_json
+// writes them. json and jsonb are left unconstrained.
+var jsonSchemaTypes = map[string]jsonSchemaType{
+	"bool":        {Type: "boolean"},
+	"int2":        {Type: "integer", Minimum: "-32768", Maximum: "32767"},
+	"int4":        {Type: "integer", Minimum: "-2147483648", Maximum: "2147483647"},
+	"int8":        {Type: "integer", Minimum: "-9223372036854775808", Maximum: "9223372036854775807"},
+	"oid":         {Type: "integer", Minimum: "0", Maximum: "4294967295"},
+	"float4":      {Type: "number"},
+	"float8":      {Type: "number"},
+	"numeric":     {Type: "number"},
+	"uuid":        {Type: "string", Format: "uuid"},
+	"date":        {Type: "string", Format: "date"},
+	"timestamp":   {Type: "string", Format: "date-time"},
+	"timestamptz": {Type: "string", Format: "date-time"},
+	"time":        {Type: "string", Format: "time"},
+	"timetz":      {Type: "string", Format: "time"},
+	"json":        {},
+	"jsonb":       {},
+}
+
+// jsonSchemaFieldType writes the schema of a column: enums refer to their
+// definition, numeric columns get the bounds of their precision and
+// character columns their length, arrays nest their element, and nullable
+// columns also accept null.
+func jsonSchemaFieldType(m *typeMapper, field *TypeField) string {
+	column := field.Column
+	t, ok := jsonSchemaTypes[column.BaseType]
+	switch {
+	case m.enum(column) != nil:
+		t = jsonSchemaType{Ref: "#/$defs/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(m.enum(column).Name)}
+	case !ok && column.Category == "B":
+		t = jsonSchemaType{Type: "boolean"}
+	case !ok && column.Category == "N":
+		t = jsonSchemaType{Type: "number"}
+	case !ok:
+		t = jsonSchemaType{Type: "string"}
+	}
+	if column.BaseType == "numeric" && column.Precision > 0 {
+		if column.Scale == 0 {
+			t.Type = "integer"
+		}
+		digits := column.Precision - column.Scale
+		t.ExclusiveMinimum = json.Number(fmt.Sprintf("-1e%d", digits))
+		t.ExclusiveMaximum = json.Number(fmt.Sprintf("1e%d", digits))
+	}
+	t.MaxLength = column.Length
+
+	for range column.Dimensions {
+		item := t
+		t = jsonSchemaType{Type: "array", Items: &item}
+	}
+	if column.Nullable {
+		switch {
+		case t.Type != nil:
+			t.Type = []any{t.Type, "null"}
+		case t.Ref != "":
+			t = jsonSchemaType{AnyOf: []*jsonSchemaType{&t, {Type: "null"}}}
+		}
+	}
+	t.Description = column.Description
+
+	text, _ := jsonText(t)
+	return text
+}
+
+// jsonSchemaFormat indents the generated schema.
+func jsonSchemaFormat(code []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, code, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pythonReserved are the names a Pydantic field cannot take: the Python
+// keywords and the BaseModel attributes a field would shadow.
+var pythonReserved = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true,
+	"await": true, "break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true,
+	"else": true, "except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+	"construct": true, "copy": true, "dict": true, "json": true, "schema": true, "validate": true,
+}
+
+// pydanticTypeName converts a name to a Python class name.
+func pydanticTypeName(name string) string {
+	ident := pascalName(name, nil, "T")
+	if pythonReserved[ident] {
+		ident += "_"
+	}
+	return ident
+}
+
+// pydanticFieldName converts a name to a snake_case Python field name.
+// Fields whose name differs from the column's are given the column name as
+// alias by the template.
+func pydanticFieldName(name string) string {
+	words := nameWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	ident := strings.Join(words, "_")
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		ident = "x_" + ident
+	}
+	if pythonReserved[ident] || strings.HasPrefix(ident, "model_") {
+		ident += "_"
+	}
+	return ident
+}
+
+// pydanticMemberName converts an enum label to an UPPER_SNAKE_CASE member.
+func pydanticMemberName(value string) string {
+	words := nameWords(value)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word)
+	}
+	ident := strings.Join(words, "_")
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		ident = "V_" + ident
+	}
+	return ident
+}
+
+// pydanticTypes maps PostgreSQL types to Python types and the modules they
+// are imported from, if any.
+var pydanticTypes = map[string][2]string{
+	"bool":        {"bool"},
+	"int2":        {"int"},
+	"int4":        {"int"},
+	"int8":        {"int"},
+	"oid":         {"int"},
+	"float4":      {"float"},
+	"float8":      {"float"},
+	"numeric":     {"Decimal", "decimal"},
+	"money":       {"Decimal", "decimal"},
+	"uuid":        {"UUID", "uuid"},
+	"date":        {"date", "datetime"},
+	"timestamp":   {"datetime", "datetime"},
+	"timestamptz": {"datetime", "datetime"},
+	"time":        {"time", "datetime"},
+	"timetz":      {"time", "datetime"},
+	"interval":    {"timedelta", "datetime"},
+	"json":        {"Any", "typing"},
+	"jsonb":       {"Any", "typing"},
+	"bytea":       {"bytes"},
+}
+
+// pydanticCategoryTypes maps the pg_type.typcategory of types pydanticTypes
+// does not list; other categories map to str.
+var pydanticCategoryTypes = map[string][2]string{
+	"B": {"bool"},
+	"D": {"datetime", "datetime"},
+	"N": {"float"},
+}
+
+// pydanticFieldType maps a column to a Python type annotation. numeric
+// columns keep their precision and scale, and character columns their
+// length, as Field constraints; arrays become lists and nullable columns
+// also accept None.
+func pydanticFieldType(m *typeMapper, field *TypeField) string {
+	column := field.Column
+	mapped, ok := pydanticTypes[column.BaseType]
+	if !ok {
+		mapped, ok = pydanticCategoryTypes[column.Category]
+	}
+	if !ok {
+		mapped = [2]string{"str"}
+	}
+	t := mapped[0]
+	if mapped[1] != "" {
+		m.use(mapped[1], mapped[0])
+	}
+
+	var constraints []string
+	switch {
+	case m.enum(column) != nil:
+		t = m.enum(column).Name
+	case column.BaseType == "numeric" && column.Precision > 0:
+		constraints = append(constraints, fmt.Sprintf("max_digits=%d", column.Precision))
+		if column.Scale >= 0 {
+			constraints = append(constraints, fmt.Sprintf("decimal_places=%d", column.Scale))
+		}
+	case column.Length > 0:
+		constraints = append(constraints, fmt.Sprintf("max_length=%d", column.Length))
+	}
+	if len(constraints) > 0 {
+		m.use("typing", "Annotated")
+		m.use("pydantic", "Field")
+		t = fmt.Sprintf("Annotated[%s, Field(%s)]", t, strings.Join(constraints, ", "))
+	}
+
+	for range column.Dimensions {
+		t = "list[" + t + "]"
+	}
+	if column.Nullable {
+		t += " | None"
+	}
+	if field.Name != column.Name {
+		m.use("pydantic", "Field")
+	}
+	return t
+}
+
+// pydanticImports writes the import statements, the standard library's
+// first and then Pydantic's, each group sorted.
+func pydanticImports(model *TypeModel, imports map[string]map[string]bool) []string {
+	if len(model.Enums) > 0 {
+		imports["enum"] = map[string]bool{"Enum": true}
+	}
+	if len(model.Types) > 0 {
+		if imports["pydantic"] == nil {
+			imports["pydantic"] = make(map[string]bool)
+		}
+		imports["pydantic"]["BaseModel"] = true
+	}
+
+	modules := make([]string, 0, len(imports))
+	for module := range imports {
+		if module != "pydantic" {
+			modules = append(modules, module)
+		}
+	}
+	slices.Sort(modules)
+	if imports["pydantic"] != nil {
+		if len(modules) > 0 {
+			modules = append(modules, "")
+		}
+		modules = append(modules, "pydantic")
+	}
+
+	lines := make([]string, 0, len(modules))
+	for _, module := range modules {
+		if module == "" {
+			lines = append(lines, "")
+			continue
+		}
+		names := make([]string, 0, len(imports[module]))
+		for name := range imports[module] {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		lines = append(lines, fmt.Sprintf("from %s import %s", module, strings.Join(names, ", ")))
+	}
+	return lines
+}
+
+// goTypeTemplate writes a Go file of structs, with a string type and
+// constants for each enum. Fields carry json and db tags naming the column.
+const goTypeTemplate = `// Code generated by generate_types from schema {{.Schema}}. DO NOT EDIT.
+
+package models
+{{- if .Imports}}
+
+import (
+{{- range .Imports}}
+	{{json .}}
+{{- end}}
+)
+{{- end}}
+{{- range .Enums}}
+
+// {{.Name}} is the {{.Schema}}.{{.Type}} enum.
+type {{.Name}} string
+
+const (
+{{- $enum := .}}
+{{- range .Members}}
+	{{.Name}} {{$enum.Name}} = {{json .Value}}
+{{- end}}
+)
+{{- end}}
+{{- range .Types}}
+
+// {{.Name}} is a row of the {{.Table.Schema}}.{{.Table.Name}} {{.Table.Type}}.
+{{- with .Table.Description}}
+//
+{{comment "// " .}}
+{{- end}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- with .Column.Description}}
+{{comment "\t// " .}}
+{{- end}}
+	{{.Name}} {{.Type}} ` + "`" + `json:{{json .Column.Name}} db:{{json .Column.Name}}` + "`" + `
+{{- end}}
+}
+{{- end}}
+`
+
+// typeScriptTypeTemplate writes TypeScript interfaces, with a union of
+// string literals for each enum.
+const typeScriptTypeTemplate = `// Generated by generate_types from schema {{.Schema}}.
+{{- range .Enums}}
+
+// The {{.Schema}}.{{.Type}} enum.
+export type {{.Name}} = {{range $i, $member := .Members}}{{if $i}} | {{end}}{{json $member.Value}}{{else}}never{{end}};
+{{- end}}
+{{- range .Types}}
+
+// A row of the {{.Table.Schema}}.{{.Table.Name}} {{.Table.Type}}.
+{{- with .Table.Description}}
+{{comment "// " .}}
+{{- end}}
+export interface {{.Name}} {
+{{- range .Fields}}
+{{- with .Column.Description}}
+{{comment "  // " .}}
+{{- end}}
+  {{.Name}}: {{.Type}};
+{{- end}}
+}
+{{- end}}
+`
+
+// jsonSchemaTypeTemplate writes a JSON Schema document defining each enum
+// and table under $defs. Every column is required, since a row always holds
+// it, and nullable columns accept null.
+const jsonSchemaTypeTemplate = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$comment": {{json (printf "Generated by generate_types from schema %s." .Schema)}},
+  "$defs": {
+{{- range $i, $enum := .Enums}}{{if $i}},{{end}}
+    {{json .Name}}: {"type": "string", "enum": {{json .Values}}}
+{{- end}}
+{{- range $i, $type := .Types}}{{if or $i $.Enums}},{{end}}
+    {{json .Name}}: {
+      "type": "object",
+{{- with .Table.Description}}
+      "description": {{json .}},
+{{- end}}
+      "properties": {
+{{- range $j, $field := .Fields}}{{if $j}},{{end}}
+        {{json .Name}}: {{.Type}}
+{{- end}}
+      },
+      "required": [{{range $j, $field := .Fields}}{{if $j}}, {{end}}{{json .Name}}{{end}}],
+      "additionalProperties": false
+    }
+{{- end}}
+  }
+}
+`
+
+// pydanticTypeTemplate writes Pydantic v2 models, with a str Enum for each
+// enum. Fields named differently from their column take it as alias, and
+// nullable fields default to None.
+const pydanticTypeTemplate = `# Generated by generate_types from schema {{.Schema}}.
+{{- if .Imports}}
+{{range .Imports}}
+{{.}}
+{{- end}}
+{{- end}}
+{{- range .Enums}}
+
+
+class {{.Name}}(str, Enum):
+    """The {{.Schema}}.{{.Type}} enum."""
+{{range .Members}}
+    {{.Name}} = {{json .Value}}
+{{- end}}
+{{- end}}
+{{- range .Types}}
+
+
+class {{.Name}}(BaseModel):
+    """A row of the {{.Table.Schema}}.{{.Table.Name}} {{.Table.Type}}."""
+{{- with .Table.Description}}
+
+{{comment "    # " .}}
+{{- end}}
+{{range .Fields}}
+{{- with .Column.Description}}
+{{comment "    # " .}}
+{{- end}}
+    {{.Name}}: {{.Type}}
+{{- if ne .Name .Column.Name}} = Field({{if .Column.Nullable}}default=None, {{end}}alias={{json .Column.Name}})
+{{- else if .Column.Nullable}} = None
+{{- end}}
+{{- end}}
+{{- end}}
+`
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeLanguages(t *testing.T) {
+	assert.Equal(t, []string{"go", "jsonschema", "pydantic", "typescript"}, TypeLanguages())
+}
+
+func TestNameWords(t *testing.T) {
+	assert.Equal(t, []string{"order", "items"}, nameWords("order_items"))
+	assert.Equal(t, []string{"order", "Items"}, nameWords("orderItems"))
+	assert.Equal(t, []string{"HTTPServer", "2", "Log"}, nameWords("HTTPServer 2Log"))
+	assert.Empty(t, nameWords("__"))
+}
+
+func TestTypeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		convert  func(string) string
+		input    string
+		expected string
+	}{
+		{"go", goName, "user_id", "UserID"},
+		{"go url", goName, "api_url", "APIURL"},
+		{"go digit", goName, "2fa_codes", "X2faCodes"},
+		{"go member", goMemberName, "in transit", "InTransit"},
+		{"go empty member", goMemberName, "", "Empty"},
+		{"typescript field", typeScriptFieldName, "created_at", "created_at"},
+		{"typescript quoted field", typeScriptFieldName, "unit price", `"unit price"`},
+		{"pydantic type", pydanticTypeName, "none", "None_"},
+		{"pydantic field", pydanticFieldName, "createdAt", "created_at"},
+		{"pydantic keyword", pydanticFieldName, "from", "from_"},
+		{"pydantic model prefix", pydanticFieldName, "model_year", "model_year_"},
+		{"pydantic digit", pydanticFieldName, "1st", "x_1st"},
+		{"pydantic member", pydanticMemberName, "in-transit", "IN_TRANSIT"},
+		{"pydantic digit member", pydanticMemberName, "24h", "V_24H"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.convert(tt.input))
+		})
+	}
+}
+
+func TestCommentLines(t *testing.T) {
+	assert.Equal(t, "// first\n//\n// second", commentLines("// ", "first\n\nsecond\n"))
+}
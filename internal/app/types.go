@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// User-defined type kinds reported in TypeInfo.Kind.
+const (
+	TypeKindEnum      = "enum"
+	TypeKindDomain    = "domain"
+	TypeKindComposite = "composite"
+	TypeKindRange     = "range"
+)
+
+// TypeInfo describes a user-defined type. Which of the optional fields are
+// set depends on Kind: Labels holds the values of an enum in sort order;
+// BaseType, NotNull, Default, and Constraints describe a domain; Attributes
+// lists the fields of a composite type; Subtype is the element type of a
+// range. The row types PostgreSQL creates for tables are not listed.
+type TypeInfo struct {
+	Schema      string                `json:"schema"`
+	Name        string                `json:"name"`
+	Kind        string                `json:"kind"`
+	Labels      []string              `json:"labels,omitempty"`
+	BaseType    string                `json:"base_type,omitempty"`
+	NotNull     bool                  `json:"not_null,omitempty"`
+	Default     string                `json:"default,omitempty"`
+	Constraints []*DomainConstraint   `json:"constraints,omitempty"`
+	Attributes  []*CompositeAttribute `json:"attributes,omitempty"`
+	Subtype     string                `json:"subtype,omitempty"`
+	Owner       string                `json:"owner"`
+	Description string                `json:"description,omitempty"`
+}
+
+// DomainConstraint is a CHECK constraint of a domain, with its definition
+// as rendered by pg_get_constraintdef.
+type DomainConstraint struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// CompositeAttribute is a field of a composite type.
+type CompositeAttribute struct {
+	Name     string `json:"name"`
+	DataType string `json:"data_type"`
+}
+
+// typeKinds maps pg_type.typtype to the TypeKind* constants.
+var typeKinds = map[string]string{
+	"e": TypeKindEnum,
+	"d": TypeKindDomain,
+	"c": TypeKindComposite,
+	"r": TypeKindRange,
+}
+
+// listTypesQuery lists the enums, domains, stand-alone composite types, and
+// range types of a schema, skipping members of extensions. Domain CHECK
+// constraints (NOT NULL is reported by typnotnull) and composite attributes
+// come back as parallel name and definition arrays.
+const listTypesQuery = `
+	SELECT
+		t.typname,
+		t.typtype::text,
+		ARRAY(SELECT e.enumlabel::text FROM pg_enum e WHERE e.enumtypid = t.oid ORDER BY e.enumsortorder),
+		CASE WHEN t.typtype = 'd' THEN format_type(t.typbasetype, t.typtypmod) ELSE '' END,
+		t.typnotnull,
+		COALESCE(t.typdefault, ''),
+		ARRAY(SELECT c.conname::text FROM pg_constraint c WHERE c.contypid = t.oid AND c.contype = 'c' ORDER BY c.conname),
+		ARRAY(SELECT pg_get_constraintdef(c.oid) FROM pg_constraint c WHERE c.contypid = t.oid AND c.contype = 'c' ORDER BY c.conname),
+		ARRAY(
+			SELECT a.attname::text FROM pg_attribute a
+			WHERE t.typtype = 'c' AND a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum
+		),
+		ARRAY(
+			SELECT format_type(a.atttypid, a.atttypmod) FROM pg_attribute a
+			WHERE t.typtype = 'c' AND a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum
+		),
+		COALESCE((SELECT format_type(r.rngsubtype, NULL) FROM pg_range r WHERE r.rngtypid = t.oid), ''),
+		pg_get_userbyid(t.typowner),
+		COALESCE(obj_description(t.oid, 'pg_type'), '')
+	FROM pg_type t
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	LEFT JOIN pg_class rel ON rel.oid = t.typrelid
+	WHERE n.nspname = $1
+		AND t.typtype IN ('e', 'd', 'c', 'r')
+		AND (t.typtype <> 'c' OR rel.relkind = 'c')
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_type'::regclass AND d.objid = t.oid AND d.deptype = 'e'
+		)
+	ORDER BY t.typname`
+
+// ListTypes returns the user-defined types of a schema.
+func (c *PostgreSQLClientImpl) ListTypes(ctx context.Context, schema string) ([]*TypeInfo, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	rows, err := db.QueryContext(ctx, listTypesQuery, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list types: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+	types := []*TypeInfo{}
+	for rows.Next() {
+		info := TypeInfo{Schema: schema}
+		var kind string
+		var constraintNames, constraintDefs, attributeNames, attributeTypes []string
+		if err := rows.Scan(
+			&info.Name,
+			&kind,
+			typeMap.SQLScanner(&info.Labels),
+			&info.BaseType,
+			&info.NotNull,
+			&info.Default,
+			typeMap.SQLScanner(&constraintNames),
+			typeMap.SQLScanner(&constraintDefs),
+			typeMap.SQLScanner(&attributeNames),
+			typeMap.SQLScanner(&attributeTypes),
+			&info.Subtype,
+			&info.Owner,
+			&info.Description,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan type row: %w", err)
+		}
+		info.Kind = typeKinds[kind]
+		if len(info.Labels) == 0 {
+			info.Labels = nil
+		}
+		for i, name := range constraintNames {
+			info.Constraints = append(info.Constraints, &DomainConstraint{Name: name, Definition: constraintDefs[i]})
+		}
+		for i, name := range attributeNames {
+			info.Attributes = append(info.Attributes, &CompositeAttribute{Name: name, DataType: attributeTypes[i]})
+		}
+		types = append(types, &info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate type rows: %w", err)
+	}
+	return types, nil
+}
+
+// ListTypes returns the enums, domains, composite types, and range types of
+// a schema.
+func (a *App) ListTypes(ctx context.Context, schema string) ([]*TypeInfo, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list types: %w", err)
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Listing types", "schema", schema)
+
+	types, err := a.client.ListTypes(ctx, schema)
+	if err != nil {
+		a.logger.Error("Failed to list types", "error", err, "schema", schema)
+		return nil, fmt.Errorf("failed to list types: %w", err)
+	}
+
+	a.logger.Debug("Successfully listed types", "count", len(types), "schema", schema)
+	return types, nil
+}
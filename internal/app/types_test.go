@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_ListTypesWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	types, err := client.ListTypes(context.Background(), "public")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, types)
+}
+
+func TestApp_ListTypes(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := []*TypeInfo{
+		{
+			Schema: DefaultSchema,
+			Name:   "order_status",
+			Kind:   TypeKindEnum,
+			Labels: []string{"pending", "paid", "shipped"},
+			Owner:  "postgres",
+		},
+		{
+			Schema:   DefaultSchema,
+			Name:     "email",
+			Kind:     TypeKindDomain,
+			BaseType: "text",
+			NotNull:  true,
+			Constraints: []*DomainConstraint{
+				{Name: "email_check", Definition: "CHECK ((VALUE ~~ '%@%'::text))"},
+			},
+			Owner: "postgres",
+		},
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListTypes", mock.Anything, DefaultSchema).Return(expected, nil)
+
+	types, err := app.ListTypes(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, types)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_ListTypesError(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("ListTypes", mock.Anything, "billing").Return(nil, errors.New("permission denied"))
+
+	types, err := app.ListTypes(context.Background(), "billing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list types")
+	assert.Nil(t, types)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ViewDefinition describes a view or materialized view. Definition is the
+// SELECT as reconstructed by pg_get_viewdef. BaseRelations are the
+// relations the view reads from directly (a view over a view lists the
+// inner view, not its tables). IsUpdatable and IsInsertable report whether
+// UPDATE/DELETE and INSERT can be applied to the view (automatically or via
+// rules or INSTEAD OF triggers); they are always false for materialized
+// views. IsPopulated is set only for materialized views and is false until
+// the first REFRESH. PostgreSQL does not record when a materialized view
+// was refreshed, so LastRefreshHint is the most recent vacuum or analyze of
+// it, which autovacuum typically performs shortly after a refresh.
+type ViewDefinition struct {
+	Schema          string          `json:"schema"`
+	Name            string          `json:"name"`
+	Type            string          `json:"type"`
+	Definition      string          `json:"definition"`
+	BaseRelations   []*RelationName `json:"base_relations"`
+	IsUpdatable     bool            `json:"is_updatable"`
+	IsInsertable    bool            `json:"is_insertable"`
+	IsPopulated     *bool           `json:"is_populated,omitempty"`
+	LastRefreshHint *time.Time      `json:"last_refresh_hint,omitempty"`
+}
+
+// RelationName identifies a relation and its kind (one of the TableType*
+// constants).
+type RelationName struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+}
+
+// relationTypes maps pg_class.relkind to the TableType* constants.
+var relationTypes = map[string]string{
+	"r": TableTypeTable,
+	"p": TableTypePartitionedTable,
+	"v": TableTypeView,
+	"m": TableTypeMaterializedView,
+	"f": TableTypeForeignTable,
+}
+
+// pg_relation_is_updatable returns a bitmask of the events the relation
+// supports, using the CmdType values of the server.
+const (
+	updatableInsert = 1 << 3 // CMD_INSERT
+	updatableUpdate = 1 << 2 // CMD_UPDATE
+	updatableDelete = 1 << 4 // CMD_DELETE
+)
+
+// viewBaseRelationsQuery finds the relations referenced by a view's
+// _RETURN rule through pg_depend, excluding the view itself.
+const viewBaseRelationsQuery = `
+	SELECT DISTINCT rn.nspname, rc.relname, rc.relkind::text
+	FROM pg_rewrite r
+	JOIN pg_depend d
+		ON d.classid = 'pg_rewrite'::regclass
+		AND d.objid = r.oid
+		AND d.refclassid = 'pg_class'::regclass
+	JOIN pg_class rc ON rc.oid = d.refobjid
+	JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+	WHERE r.ev_class = $1 AND d.refobjid <> $1
+	ORDER BY rn.nspname, rc.relname`
+
+// GetViewDefinition returns the definition and dependencies of a view or
+// materialized view.
+func (c *PostgreSQLClientImpl) GetViewDefinition(ctx context.Context, schema, view string) (*ViewDefinition, error) {
+	db := c.sqlDB()
+	if db == nil {
+		return nil, ErrNoDatabaseConnection
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	var oid uint32
+	var relkind string
+	var updatable int
+	var populated bool
+	var lastMaintained sql.NullTime
+	def := &ViewDefinition{Schema: schema, Name: view}
+	err := db.QueryRowContext(ctx, `
+		SELECT
+			c.oid,
+			c.relkind::text,
+			CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) ELSE '' END,
+			CASE WHEN c.relkind = 'v' THEN pg_relation_is_updatable(c.oid::regclass, false) ELSE 0 END,
+			c.relispopulated,
+			GREATEST(s.last_vacuum, s.last_autovacuum, s.last_analyze, s.last_autoanalyze)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+		WHERE n.nspname = $1 AND c.relname = $2`, schema, view).
+		Scan(&oid, &relkind, &def.Definition, &updatable, &populated, &lastMaintained)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("view %s.%s: %w", schema, view, ErrTableNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view definition: %w", err)
+	}
+	if relkind != "v" && relkind != "m" {
+		return nil, fmt.Errorf("relation %s.%s: %w", schema, view, ErrNotAView)
+	}
+
+	def.Type = relationTypes[relkind]
+	def.IsUpdatable = updatable&(updatableUpdate|updatableDelete) == updatableUpdate|updatableDelete
+	def.IsInsertable = updatable&updatableInsert != 0
+	if relkind == "m" {
+		def.IsPopulated = &populated
+		if lastMaintained.Valid {
+			def.LastRefreshHint = &lastMaintained.Time
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, viewBaseRelationsQuery, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list view dependencies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	def.BaseRelations = []*RelationName{}
+	for rows.Next() {
+		var rel RelationName
+		var kind string
+		if err := rows.Scan(&rel.Schema, &rel.Name, &kind); err != nil {
+			return nil, fmt.Errorf("failed to scan view dependency row: %w", err)
+		}
+		rel.Type = relationTypes[kind]
+		def.BaseRelations = append(def.BaseRelations, &rel)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate view dependency rows: %w", err)
+	}
+	return def, nil
+}
+
+// GetViewDefinition returns the SQL, base relations, and updatability of a
+// view, and for a materialized view whether it is populated.
+func (a *App) GetViewDefinition(ctx context.Context, schema, view string) (*ViewDefinition, error) {
+	if err := a.ensureConnection(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get view definition: %w", err)
+	}
+
+	if view == "" {
+		return nil, ErrTableRequired
+	}
+
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	a.logger.Debug("Getting view definition", "schema", schema, "view", view)
+
+	def, err := a.client.GetViewDefinition(ctx, schema, view)
+	if err != nil {
+		a.logger.Error("Failed to get view definition", "error", err, "schema", schema, "view", view)
+		return nil, fmt.Errorf("failed to get view definition: %w", err)
+	}
+
+	a.logger.Debug("Successfully retrieved view definition", "base_relation_count", len(def.BaseRelations),
+		"schema", schema, "view", view)
+	return def, nil
+}
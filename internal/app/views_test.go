@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostgreSQLClient_GetViewDefinitionWithoutConnection(t *testing.T) {
+	client := NewPostgreSQLClient()
+	def, err := client.GetViewDefinition(context.Background(), "public", "active_users")
+	assert.ErrorIs(t, err, ErrNoDatabaseConnection)
+	assert.Nil(t, def)
+}
+
+func TestApp_GetViewDefinition(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	expected := &ViewDefinition{
+		Schema:     DefaultSchema,
+		Name:       "active_users",
+		Type:       TableTypeView,
+		Definition: " SELECT id, name\n   FROM users\n  WHERE active;",
+		BaseRelations: []*RelationName{
+			{Schema: "public", Name: "users", Type: TableTypeTable},
+		},
+		IsUpdatable:  true,
+		IsInsertable: true,
+	}
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetViewDefinition", mock.Anything, DefaultSchema, "active_users").Return(expected, nil)
+
+	def, err := app.GetViewDefinition(context.Background(), "", "active_users")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, def)
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetViewDefinitionErrors(t *testing.T) {
+	mockClient := &MockPostgreSQLClient{}
+	app := New(mockClient)
+
+	mockClient.On("Ping", mock.Anything).Return(nil)
+	mockClient.On("GetViewDefinition", mock.Anything, "public", "users").
+		Return(nil, fmt.Errorf("relation public.users: %w", ErrNotAView))
+
+	_, err := app.GetViewDefinition(context.Background(), "public", "")
+	assert.ErrorIs(t, err, ErrTableRequired)
+
+	def, err := app.GetViewDefinition(context.Background(), "public", "users")
+	assert.ErrorIs(t, err, ErrNotAView)
+	assert.Nil(t, def)
+	mockClient.AssertExpectations(t)
+}
@@ -16,7 +16,7 @@ import (
 	"strings"
 	"syscall"
 
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sylvain/postgresql-mcp/internal/app"
@@ -28,8 +28,11 @@ var version = "dev"
 
 // MCP parameter names and log attribute keys reused across tool handlers.
 const (
-	schemaKey = "schema"
-	tableKey  = "table"
+	schemaKey   = "schema"
+	tableKey    = "table"
+	functionKey = "function"
+	formatKey   = "format"
+	paramsKey   = "params"
 )
 
 // Error variables for static errors.
@@ -214,7 +217,7 @@ func handleConnectDatabaseRequest(
 	// Attempt to connect
 	if err := appInstance.Connect(qctx, connectionString); err != nil {
 		// Issue #88: pre-auth errors leak host/port (*net.OpError), username,
-		// and auth method (*pq.Error.Message). Return a fixed generic
+		// and auth method (*pgconn.PgError.Message). Return a fixed generic
 		// message regardless of error class; the full chain is logged above.
 		debugLogger.Error("Failed to connect to database", "error", err)
 		return mcp.NewToolResultError(
@@ -346,13 +349,24 @@ func setupListSchemasTool(s *server.MCPServer, appInstance *app.App, debugLogger
 // setupListTablesTool creates and registers the list_tables tool.
 func setupListTablesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
 	listTablesTool := mcp.NewTool("list_tables",
-		mcp.WithDescription("List tables in a specific schema"),
+		mcp.WithDescription("List tables, partitioned tables, views, materialized views and foreign tables in a specific schema"),
 		mcp.WithString(schemaKey,
 			mcp.Description(fmt.Sprintf("Schema name to list tables from (default: %s)", app.DefaultSchema)),
 		),
 		mcp.WithBoolean("include_size",
 			mcp.Description("Include table size and row count information (default: false)"),
 		),
+		mcp.WithArray("types",
+			mcp.Description("Only return relations of these types (default: all)"),
+			mcp.WithStringEnumItems(app.TableTypes()),
+		),
+		mcp.WithString("pattern",
+			mcp.Description("Only return relations whose name matches this ILIKE pattern, e.g. 'order%'"),
+		),
+		mcp.WithString("search",
+			mcp.Description("Only return tables whose name or comment contains this text (case-insensitive)"),
+		),
+		withFormatOption(),
 	)
 
 	s.AddTool(listTablesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -370,7 +384,27 @@ func setupListTablesTool(s *server.MCPServer, appInstance *app.App, debugLogger
 			opts.IncludeSize = includeSize
 		}
 
-		debugLogger.Debug("Processing list_tables request", schemaKey, opts.Schema, "include_size", opts.IncludeSize)
+		types, err := extractTableTypes(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts.Types = types
+
+		if pattern, ok := args["pattern"].(string); ok {
+			opts.Pattern = pattern
+		}
+
+		if search, ok := args["search"].(string); ok {
+			opts.Search = strings.TrimSpace(search)
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Debug("Processing list_tables request", schemaKey, opts.Schema, "include_size", opts.IncludeSize,
+			"types", opts.Types, "pattern", opts.Pattern, "search", opts.Search)
 
 		qctx, cancel := withQueryTimeout(ctx)
 		defer cancel()
@@ -382,15 +416,13 @@ func setupListTablesTool(s *server.MCPServer, appInstance *app.App, debugLogger
 			return mcp.NewToolResultError(publicError("Failed to list tables", err)), nil
 		}
 
-		// Convert to JSON
-		jsonData, err := json.Marshal(tables)
+		out, err := renderResult(tables, format, debugLogger, "Failed to format tables response")
 		if err != nil {
-			debugLogger.Error("Failed to marshal tables to JSON", "error", err)
-			return mcp.NewToolResultError("Failed to format tables response"), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		debugLogger.Info("Successfully listed tables", "count", len(tables), schemaKey, opts.Schema)
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return mcp.NewToolResultText(out), nil
 	})
 }
 
@@ -427,20 +459,142 @@ func marshalToJSON(data any, debugLogger *slog.Logger, errorMsg string) ([]byte,
 	return jsonData, nil
 }
 
+// withFormatOption declares the optional "format" argument shared by every
+// tool whose result can be rendered through app.EncodeResult.
+func withFormatOption() mcp.ToolOption {
+	return mcp.WithString(formatKey,
+		mcp.Description("Result format: "+strings.Join(app.ResultFormats(), ", ")+
+			" (default: json). Non-JSON formats are more compact for wide results."),
+		mcp.Enum(app.ResultFormats()...),
+	)
+}
+
+// extractFormat returns the requested result format, or "" when the caller
+// did not ask for one. Unknown formats are rejected up front so no query is
+// run only to fail at serialization time.
+func extractFormat(args map[string]any) (string, error) {
+	format, _ := args[formatKey].(string)
+	format = strings.ToLower(strings.TrimSpace(format))
+	if err := app.ValidateResultFormat(format); err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+// withParamsOption declares the optional params argument shared by
+// execute_query and explain_query.
+func withParamsOption() mcp.ToolOption {
+	return mcp.WithAny(paramsKey,
+		mcp.Description("Bind parameters. An array binds positionally to $1, $2, …; "+
+			"an object binds by name to :name placeholders (e.g. {\"id\": 1} for WHERE id = :id). "+
+			"JSON arrays bind to PostgreSQL arrays and objects to json/jsonb."),
+	)
+}
+
+// extractParams binds the optional params argument to query, returning the
+// query to run (with :name placeholders rewritten to $n) and its arguments.
+func extractParams(query string, args map[string]any) (string, []any, error) {
+	return app.BindParams(query, args[paramsKey])
+}
+
+// extractTableTypes reads the optional "types" argument of list_tables. The
+// values themselves are validated by app.ListTables.
+func extractTableTypes(args map[string]any) ([]string, error) {
+	return extractTypes(args, app.ErrInvalidTableType)
+}
+
+// extractObjectTypes reads the optional "types" argument of search_schema.
+// The values themselves are validated by app.SearchSchema.
+func extractObjectTypes(args map[string]any) ([]string, error) {
+	return extractTypes(args, app.ErrInvalidObjectType)
+}
+
+// extractTypes reads a "types" argument, which may be a single string or an
+// array of strings, reporting malformed values with errInvalid.
+func extractTypes(args map[string]any, errInvalid error) ([]string, error) {
+	switch v := args["types"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			t, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: types must be strings, got %T", errInvalid, item)
+			}
+			types = append(types, t)
+		}
+		return types, nil
+	default:
+		return nil, fmt.Errorf("%w: types must be an array of strings, got %T", errInvalid, v)
+	}
+}
+
+// renderResult serializes a tool result. The default (empty or "json")
+// format keeps each tool's historical JSON shape; any other format is
+// rendered by the matching app.ResultEncoder, converting metadata structs
+// into a tabular app.QueryResult first. Multi-part results such as
+// app.TableDescription are rendered section by section.
+func renderResult(data any, format string, debugLogger *slog.Logger, errorMsg string) (string, error) {
+	if format == "" || format == app.FormatJSON {
+		jsonData, err := marshalToJSON(data, debugLogger, errorMsg)
+		if err != nil {
+			return "", err
+		}
+		return string(jsonData), nil
+	}
+
+	if sectioned, ok := data.(app.SectionedResult); ok {
+		out, err := app.EncodeSections(format, sectioned.ResultSections())
+		if err != nil {
+			debugLogger.Error("Failed to encode result", "error", err, formatKey, format, "context", errorMsg)
+			return "", fmt.Errorf("%s: %w", errorMsg, err)
+		}
+		return out, nil
+	}
+
+	result, ok := data.(*app.QueryResult)
+	if !ok {
+		var err error
+		result, err = app.NewQueryResult(data)
+		if err != nil {
+			debugLogger.Error("Failed to tabulate result", "error", err, "context", errorMsg)
+			return "", fmt.Errorf("%s: %w", errorMsg, err)
+		}
+	}
+
+	out, err := app.EncodeResult(format, result)
+	if err != nil {
+		debugLogger.Error("Failed to encode result", "error", err, formatKey, format, "context", errorMsg)
+		return "", fmt.Errorf("%s: %w", errorMsg, err)
+	}
+	return out, nil
+}
+
 // publicError formats an error for the MCP caller while suppressing the
 // server-version, host/port, schema/table/column, and source-location fields
-// exposed by *pq.Error. Only Message and the SQLSTATE Code.Name() survive;
-// Detail, Hint, Where, Routine, File, Line, Schema, Table, Column,
-// DataTypeName, and Constraint are dropped. Non-pq errors are passed through
-// unchanged — at the call sites that use this helper they are app-level
-// sentinels (e.g. ErrConnectionRequired, ErrQueryRequired) or
-// already-curated wrapped errors. The full error chain is preserved in the
-// internal log (the caller logs err immediately before calling publicError).
-// See issue #88.
+// exposed by *pgconn.PgError. Only Message, the SQLSTATE code with its
+// condition name, and the error position within the caller's own query
+// survive; Detail, Hint, Where, Routine, File, Line, SchemaName, TableName,
+// ColumnName, DataTypeName, and ConstraintName are dropped. Non-PostgreSQL
+// errors are passed through unchanged — at the call sites that use this
+// helper they are app-level sentinels (e.g. ErrConnectionRequired,
+// ErrQueryRequired) or already-curated wrapped errors. The full error chain
+// is preserved in the internal log (the caller logs err immediately before
+// calling publicError). See issue #88.
 func publicError(prefix string, err error) string {
-	var pqErr *pq.Error
-	if errors.As(err, &pqErr) {
-		return fmt.Sprintf("%s: %s (SQLSTATE %s)", prefix, pqErr.Message, pqErr.Code.Name())
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		state := pgErr.Code
+		if name := app.SQLStateName(pgErr.Code); name != "" {
+			state += " " + name
+		}
+		if pgErr.Position > 0 {
+			return fmt.Sprintf("%s: %s (SQLSTATE %s, position %d)", prefix, pgErr.Message, state, pgErr.Position)
+		}
+		return fmt.Sprintf("%s: %s (SQLSTATE %s)", prefix, pgErr.Message, state)
 	}
 	return fmt.Sprintf("%s: %s", prefix, err.Error())
 }
@@ -466,6 +620,7 @@ func setupTableTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog
 		mcp.WithString(schemaKey,
 			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
 		),
+		withFormatOption(),
 	)
 
 	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -477,6 +632,11 @@ func setupTableTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		qctx, cancel := withQueryTimeout(ctx)
 		defer cancel()
 
@@ -486,14 +646,14 @@ func setupTableTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog
 			return mcp.NewToolResultError(publicError("Failed to "+config.ErrorMsg, err)), nil
 		}
 
-		jsonData, err := marshalToJSON(result, debugLogger, fmt.Sprintf("Failed to format %s response", config.Name))
+		out, err := renderResult(result, format, debugLogger, fmt.Sprintf("Failed to format %s response", config.Name))
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		msg, logArgs := config.SuccessMsg(result, schema, table)
 		debugLogger.Info(msg, logArgs...)
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return mcp.NewToolResultText(out), nil
 	})
 }
 
@@ -501,17 +661,21 @@ func setupTableTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog
 func setupDescribeTableTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
 	setupTableTool(s, appInstance, debugLogger, TableToolConfig{
 		Name:        "describe_table",
-		Description: "Get detailed information about a table's structure (columns, types, constraints)",
+		Description: "Get detailed information about a table's structure (columns, types, constraints and foreign keys)",
 		TableDesc:   "Table name to describe",
 		Operation: func(ctx context.Context, appInstance *app.App, schema, table string) (any, error) {
 			return appInstance.DescribeTable(ctx, schema, table)
 		},
 		SuccessMsg: func(result any, schema, table string) (string, []any) {
-			columns, ok := result.([]*app.ColumnInfo)
+			description, ok := result.(*app.TableDescription)
 			if !ok {
 				return "Error processing result", []any{"error", "type assertion failed"}
 			}
-			return "Successfully described table", []any{"column_count", len(columns), schemaKey, schema, tableKey, table}
+			return "Successfully described table", []any{
+				"column_count", len(description.Columns),
+				"constraint_count", len(description.Constraints),
+				schemaKey, schema, tableKey, table,
+			}
 		},
 		ErrorMsg: "describe table",
 	})
@@ -528,6 +692,8 @@ func setupExecuteQueryTool(s *server.MCPServer, appInstance *app.App, debugLogge
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of rows to return (default: no limit)"),
 		),
+		withParamsOption(),
+		withFormatOption(),
 	)
 
 	s.AddTool(executeQueryTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -544,16 +710,29 @@ func setupExecuteQueryTool(s *server.MCPServer, appInstance *app.App, debugLogge
 			return mcp.NewToolResultError("query must be a non-empty string"), nil
 		}
 
+		boundQuery, queryArgs, err := extractParams(query, args)
+		if err != nil {
+			debugLogger.Error("Invalid query parameters", "error", err)
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		// Extract options
 		opts := &app.ExecuteQueryOptions{
-			Query: query,
+			Query: boundQuery,
+			Args:  queryArgs,
 		}
 
 		if limitFloat, ok := args["limit"].(float64); ok && limitFloat > 0 {
 			opts.Limit = int(limitFloat)
 		}
 
-		debugLogger.Debug("Processing execute_query request", "query", app.LogSafeQuery(query), "limit", opts.Limit)
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Debug("Processing execute_query request", "query", app.LogSafeQuery(query), "limit", opts.Limit,
+			"param_count", len(queryArgs))
 
 		qctx, cancel := withQueryTimeout(ctx)
 		defer cancel()
@@ -565,15 +744,13 @@ func setupExecuteQueryTool(s *server.MCPServer, appInstance *app.App, debugLogge
 			return mcp.NewToolResultError(publicError("Failed to execute query", err)), nil
 		}
 
-		// Convert to JSON
-		jsonData, err := json.Marshal(result)
+		out, err := renderResult(result, format, debugLogger, "Failed to format query result")
 		if err != nil {
-			debugLogger.Error("Failed to marshal query result to JSON", "error", err)
-			return mcp.NewToolResultError("Failed to format query result"), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		debugLogger.Info("Successfully executed query", "row_count", result.RowCount)
-		return mcp.NewToolResultText(string(jsonData)), nil
+		return mcp.NewToolResultText(out), nil
 	})
 }
 
@@ -581,7 +758,7 @@ func setupExecuteQueryTool(s *server.MCPServer, appInstance *app.App, debugLogge
 func setupListIndexesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
 	setupTableTool(s, appInstance, debugLogger, TableToolConfig{
 		Name:        "list_indexes",
-		Description: "List indexes for a specific table",
+		Description: "List indexes for a specific table with key and INCLUDE columns, expressions, partial-index predicates, validity, size, usage counts and the full definition",
 		TableDesc:   "Table name to list indexes for",
 		Operation: func(ctx context.Context, appInstance *app.App, schema, table string) (any, error) {
 			return appInstance.ListIndexes(ctx, schema, table)
@@ -609,6 +786,7 @@ func setupExplainQueryTool(s *server.MCPServer, appInstance *app.App, debugLogge
 		mcp.WithBoolean("analyze",
 			mcp.Description("If true, run EXPLAIN (ANALYZE, BUFFERS) which executes the query. Default: false (plan only)."),
 		),
+		withParamsOption(),
 	)
 
 	s.AddTool(explainQueryTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -627,13 +805,20 @@ func setupExplainQueryTool(s *server.MCPServer, appInstance *app.App, debugLogge
 
 		analyze, _ := args["analyze"].(bool)
 
-		debugLogger.Debug("Processing explain_query request", "query", app.LogSafeQuery(query), "analyze", analyze)
+		boundQuery, queryArgs, err := extractParams(query, args)
+		if err != nil {
+			debugLogger.Error("Invalid query parameters", "error", err)
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Debug("Processing explain_query request", "query", app.LogSafeQuery(query), "analyze", analyze,
+			"param_count", len(queryArgs))
 
 		qctx, cancel := withQueryTimeout(ctx)
 		defer cancel()
 
 		// Explain query
-		result, err := appInstance.ExplainQuery(qctx, query, analyze)
+		result, err := appInstance.ExplainQuery(qctx, boundQuery, analyze, queryArgs...)
 		if err != nil {
 			debugLogger.Error("Failed to explain query", "error", err, "query", app.LogSafeQuery(query))
 			return mcp.NewToolResultError(publicError("Failed to explain query", err)), nil
@@ -667,6 +852,1064 @@ func setupGetTableStatsTool(s *server.MCPServer, appInstance *app.App, debugLogg
 	})
 }
 
+// setupDescribePartitionsTool creates and registers the describe_partitions tool.
+func setupDescribePartitionsTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	setupTableTool(s, appInstance, debugLogger, TableToolConfig{
+		Name: "describe_partitions",
+		Description: "Get the partition tree of a partitioned table: strategy, partition key, " +
+			"and each partition's bound, row estimate, size, and default partition",
+		TableDesc: "Partitioned table name",
+		Operation: func(ctx context.Context, appInstance *app.App, schema, table string) (any, error) {
+			return appInstance.DescribePartitions(ctx, schema, table)
+		},
+		SuccessMsg: func(result any, schema, table string) (string, []any) {
+			tree, ok := result.(*app.PartitionTree)
+			if !ok {
+				return "Error processing result", []any{"error", "type assertion failed"}
+			}
+			return "Successfully described partitions", []any{
+				"partition_count", len(tree.Partitions), schemaKey, schema, tableKey, table,
+			}
+		},
+		ErrorMsg: "describe partitions",
+	})
+}
+
+// setupGetViewDefinitionTool creates and registers the get_view_definition tool.
+func setupGetViewDefinitionTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	setupTableTool(s, appInstance, debugLogger, TableToolConfig{
+		Name: "get_view_definition",
+		Description: "Get the SQL definition of a view or materialized view, the relations it reads from, " +
+			"whether it is updatable, and for materialized views whether it is populated",
+		TableDesc: "View or materialized view name",
+		Operation: func(ctx context.Context, appInstance *app.App, schema, table string) (any, error) {
+			return appInstance.GetViewDefinition(ctx, schema, table)
+		},
+		SuccessMsg: func(result any, schema, table string) (string, []any) {
+			def, ok := result.(*app.ViewDefinition)
+			if !ok {
+				return "Error processing result", []any{"error", "type assertion failed"}
+			}
+			return "Successfully retrieved view definition", []any{
+				"base_relation_count", len(def.BaseRelations), schemaKey, schema, tableKey, table,
+			}
+		},
+		ErrorMsg: "get view definition",
+	})
+}
+
+// setupListTriggersTool creates and registers the list_triggers tool.
+func setupListTriggersTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	setupTableTool(s, appInstance, debugLogger, TableToolConfig{
+		Name: "list_triggers",
+		Description: "List the triggers of a table or view with their timing, events, level, " +
+			"WHEN condition, and the function each one calls",
+		TableDesc: "Table or view name to list triggers for",
+		Operation: func(ctx context.Context, appInstance *app.App, schema, table string) (any, error) {
+			return appInstance.ListTriggers(ctx, schema, table)
+		},
+		SuccessMsg: func(result any, schema, table string) (string, []any) {
+			triggers, ok := result.([]*app.TriggerInfo)
+			if !ok {
+				return "Error processing result", []any{"error", "type assertion failed"}
+			}
+			return "Successfully listed triggers", []any{"count", len(triggers), schemaKey, schema, tableKey, table}
+		},
+		ErrorMsg: "list triggers",
+	})
+}
+
+// SchemaToolConfig holds configuration for tools that list the objects of
+// one schema.
+type SchemaToolConfig struct {
+	Name        string
+	Description string
+	SchemaDesc  string
+	Operation   func(ctx context.Context, appInstance *app.App, schema string) (any, error)
+	SuccessMsg  func(result any, schema string) (string, []any)
+	ErrorMsg    string
+}
+
+// setupSchemaTool creates and registers a schema-scoped tool using the provided configuration.
+func setupSchemaTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger, config SchemaToolConfig) {
+	tool := mcp.NewTool(config.Name,
+		mcp.WithDescription(config.Description),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("%s (default: %s)", config.SchemaDesc, app.DefaultSchema)),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug(fmt.Sprintf("Received %s tool request", config.Name), "args", args)
+
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		result, err := config.Operation(qctx, appInstance, schema)
+		if err != nil {
+			debugLogger.Error("Failed to "+config.ErrorMsg, "error", err, schemaKey, schema)
+			return mcp.NewToolResultError(publicError("Failed to "+config.ErrorMsg, err)), nil
+		}
+
+		out, err := renderResult(result, format, debugLogger, fmt.Sprintf("Failed to format %s response", config.Name))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		msg, logArgs := config.SuccessMsg(result, schema)
+		debugLogger.Info(msg, logArgs...)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupListFunctionsTool creates and registers the list_functions tool.
+func setupListFunctionsTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	setupSchemaTool(s, appInstance, debugLogger, SchemaToolConfig{
+		Name: "list_functions",
+		Description: "List the functions and procedures of a schema with their arguments, return type, " +
+			"language, volatility, and whether they are SECURITY DEFINER",
+		SchemaDesc: "Schema name to list functions from",
+		Operation: func(ctx context.Context, appInstance *app.App, schema string) (any, error) {
+			return appInstance.ListFunctions(ctx, schema)
+		},
+		SuccessMsg: func(result any, schema string) (string, []any) {
+			functions, ok := result.([]*app.FunctionInfo)
+			if !ok {
+				return "Error processing result", []any{"error", "type assertion failed"}
+			}
+			return "Successfully listed functions", []any{"count", len(functions), schemaKey, schema}
+		},
+		ErrorMsg: "list functions",
+	})
+}
+
+// setupListTypesTool creates and registers the list_types tool.
+func setupListTypesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	setupSchemaTool(s, appInstance, debugLogger, SchemaToolConfig{
+		Name: "list_types",
+		Description: "List the user-defined types of a schema: enums with their labels in order, " +
+			"domains with base type and constraints, composite types with attributes, and range types",
+		SchemaDesc: "Schema name to list types from",
+		Operation: func(ctx context.Context, appInstance *app.App, schema string) (any, error) {
+			return appInstance.ListTypes(ctx, schema)
+		},
+		SuccessMsg: func(result any, schema string) (string, []any) {
+			types, ok := result.([]*app.TypeInfo)
+			if !ok {
+				return "Error processing result", []any{"error", "type assertion failed"}
+			}
+			return "Successfully listed types", []any{"count", len(types), schemaKey, schema}
+		},
+		ErrorMsg: "list types",
+	})
+}
+
+// setupListSequencesTool creates and registers the list_sequences tool.
+func setupListSequencesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	setupSchemaTool(s, appInstance, debugLogger, SchemaToolConfig{
+		Name: "list_sequences",
+		Description: "List the sequences of a schema with their current value (where permitted), increment, " +
+			"bounds, and the column that owns them",
+		SchemaDesc: "Schema name to list sequences from",
+		Operation: func(ctx context.Context, appInstance *app.App, schema string) (any, error) {
+			return appInstance.ListSequences(ctx, schema)
+		},
+		SuccessMsg: func(result any, schema string) (string, []any) {
+			sequences, ok := result.([]*app.SequenceInfo)
+			if !ok {
+				return "Error processing result", []any{"error", "type assertion failed"}
+			}
+			return "Successfully listed sequences", []any{"count", len(sequences), schemaKey, schema}
+		},
+		ErrorMsg: "list sequences",
+	})
+}
+
+// setupGetFunctionDefinitionTool creates and registers the get_function_definition tool.
+func setupGetFunctionDefinitionTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	getFunctionDefinitionTool := mcp.NewTool("get_function_definition",
+		mcp.WithDescription("Get the full CREATE OR REPLACE source of a function or procedure. "+
+			"Returns every overload unless arguments is given"),
+		mcp.WithString(functionKey,
+			mcp.Required(),
+			mcp.Description("Function or procedure name"),
+		),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString("arguments",
+			mcp.Description("Argument types selecting one overload, e.g. 'integer, text' (default: all overloads)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(getFunctionDefinitionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received get_function_definition tool request", "args", args)
+
+		name, ok := args[functionKey].(string)
+		if !ok || name == "" {
+			debugLogger.Error("function name is missing or not a string", "value", args[functionKey])
+			return mcp.NewToolResultError(app.ErrFunctionRequired.Error()), nil
+		}
+
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		argTypes, _ := args["arguments"].(string)
+		argTypes = strings.TrimSpace(argTypes)
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		definitions, err := appInstance.GetFunctionDefinition(qctx, schema, name, argTypes)
+		if err != nil {
+			debugLogger.Error("Failed to get function definition", "error", err, schemaKey, schema, functionKey, name)
+			return mcp.NewToolResultError(publicError("Failed to get function definition", err)), nil
+		}
+
+		out, err := renderResult(definitions, format, debugLogger, "Failed to format function definition response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully retrieved function definition", "overload_count", len(definitions),
+			schemaKey, schema, functionKey, name)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupListExtensionsTool creates and registers the list_extensions tool.
+func setupListExtensionsTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	listExtensionsTool := mcp.NewTool("list_extensions",
+		mcp.WithDescription("List the extensions available on the server, with installed and default versions "+
+			"and the schema of those installed in the current database"),
+		mcp.WithBoolean("installed",
+			mcp.Description("Only return extensions installed in the current database (default: false)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(listExtensionsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received list_extensions tool request", "args", args)
+
+		installedOnly, _ := args["installed"].(bool)
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		extensions, err := appInstance.ListExtensions(qctx, installedOnly)
+		if err != nil {
+			debugLogger.Error("Failed to list extensions", "error", err)
+			return mcp.NewToolResultError(publicError("Failed to list extensions", err)), nil
+		}
+
+		out, err := renderResult(extensions, format, debugLogger, "Failed to format extensions response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully listed extensions", "count", len(extensions), "installed_only", installedOnly)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupGetSettingsTool creates and registers the get_settings tool.
+func setupGetSettingsTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	getSettingsTool := mcp.NewTool("get_settings",
+		mcp.WithDescription("Get server configuration parameters (pg_settings) with their current value, unit, "+
+			"source, and whether they were changed from the default"),
+		mcp.WithString("name",
+			mcp.Description("Only return settings whose name contains this text, e.g. 'work_mem' or 'cost'"),
+		),
+		mcp.WithString("category",
+			mcp.Description("Only return settings whose category contains this text, e.g. 'Query Tuning'"),
+		),
+		mcp.WithBoolean("non_default",
+			mcp.Description("Only return settings changed from their default (default: false)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(getSettingsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received get_settings tool request", "args", args)
+
+		opts := app.SettingsOptions{}
+		if name, ok := args["name"].(string); ok {
+			opts.Name = strings.TrimSpace(name)
+		}
+		if category, ok := args["category"].(string); ok {
+			opts.Category = strings.TrimSpace(category)
+		}
+		if nonDefault, ok := args["non_default"].(bool); ok {
+			opts.NonDefault = nonDefault
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		settings, err := appInstance.GetSettings(qctx, opts)
+		if err != nil {
+			debugLogger.Error("Failed to get settings", "error", err)
+			return mcp.NewToolResultError(publicError("Failed to get settings", err)), nil
+		}
+
+		out, err := renderResult(settings, format, debugLogger, "Failed to format settings response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully retrieved settings", "count", len(settings))
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupListRolesTool creates and registers the list_roles tool.
+func setupListRolesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	listRolesTool := mcp.NewTool("list_roles",
+		mcp.WithDescription("List database roles with their attributes (login, superuser, inherit, bypass RLS, ...) "+
+			"and the roles each one is a member of"),
+		withFormatOption(),
+	)
+
+	s.AddTool(listRolesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received list_roles tool request", "args", args)
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		roles, err := appInstance.ListRoles(qctx)
+		if err != nil {
+			debugLogger.Error("Failed to list roles", "error", err)
+			return mcp.NewToolResultError(publicError("Failed to list roles", err)), nil
+		}
+
+		out, err := renderResult(roles, format, debugLogger, "Failed to format roles response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully listed roles", "count", len(roles))
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupGetTablePrivilegesTool creates and registers the get_table_privileges tool.
+func setupGetTablePrivilegesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	getTablePrivilegesTool := mcp.NewTool("get_table_privileges",
+		mcp.WithDescription("Show which table and column privileges a role holds (directly, through membership, "+
+			"PUBLIC or ownership) and the grants on each table. Use it to explain 'permission denied' errors"),
+		mcp.WithString("role",
+			mcp.Description("Role to check (default: the connected user)"),
+		),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString(tableKey,
+			mcp.Description("Only report this table (default: every table in the schema)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(getTablePrivilegesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received get_table_privileges tool request", "args", args)
+
+		role, _ := args["role"].(string)
+		table, _ := args[tableKey].(string)
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		privileges, err := appInstance.GetTablePrivileges(qctx, role, schema, table)
+		if err != nil {
+			debugLogger.Error("Failed to get table privileges", "error", err, "role", role, schemaKey, schema, tableKey, table)
+			return mcp.NewToolResultError(publicError("Failed to get table privileges", err)), nil
+		}
+
+		out, err := renderResult(privileges, format, debugLogger, "Failed to format table privileges response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully retrieved table privileges", "count", len(privileges),
+			"role", role, schemaKey, schema, tableKey, table)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupListPoliciesTool creates and registers the list_policies tool.
+func setupListPoliciesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	listPoliciesTool := mcp.NewTool("list_policies",
+		mcp.WithDescription("List row-level security policies with their USING and WITH CHECK expressions, "+
+			"and whether row-level security is enabled and forced on each table"),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString(tableKey,
+			mcp.Description("Only report this table (default: every table in the schema)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(listPoliciesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received list_policies tool request", "args", args)
+
+		table, _ := args[tableKey].(string)
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		tables, err := appInstance.ListPolicies(qctx, schema, table)
+		if err != nil {
+			debugLogger.Error("Failed to list policies", "error", err, schemaKey, schema, tableKey, table)
+			return mcp.NewToolResultError(publicError("Failed to list policies", err)), nil
+		}
+
+		out, err := renderResult(tables, format, debugLogger, "Failed to format policies response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully listed policies", "table_count", len(tables), schemaKey, schema, tableKey, table)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupGetDDLTool creates and registers the get_ddl tool.
+func setupGetDDLTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	getDDLTool := mcp.NewTool("get_ddl",
+		mcp.WithDescription("Generate the DDL of a table (CREATE TABLE with columns, defaults, identity, "+
+			"constraints and partitioning, plus indexes, triggers, owned sequences, comments, owner and grants) "+
+			"or of a whole schema, in dependency order, similar to pg_dump --schema-only"),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString(tableKey,
+			mcp.Description("Table, view or materialized view to generate (default: every object in the schema)"),
+		),
+		mcp.WithString(formatKey,
+			mcp.Description("Result format: "+strings.Join(app.ResultFormats(), ", ")+
+				" (default: a plain SQL script). The formats return one entry per object with its type, name and SQL."),
+			mcp.Enum(app.ResultFormats()...),
+		),
+	)
+
+	s.AddTool(getDDLTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received get_ddl tool request", "args", args)
+
+		table, _ := args[tableKey].(string)
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		script, err := appInstance.GetDDL(qctx, schema, table)
+		if err != nil {
+			debugLogger.Error("Failed to get DDL", "error", err, schemaKey, schema, tableKey, table)
+			return mcp.NewToolResultError(publicError("Failed to get DDL", err)), nil
+		}
+
+		out := script.Script()
+		if format != "" {
+			out, err = renderResult(script, format, debugLogger, "Failed to format DDL response")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		debugLogger.Info("Successfully generated DDL", "object_count", len(script.Objects), schemaKey, schema, tableKey, table)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupGetRelationshipsTool creates and registers the get_relationships tool.
+func setupGetRelationshipsTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	getRelationshipsTool := mcp.NewTool("get_relationships",
+		mcp.WithDescription("Get the foreign-key graph of a schema, or of the tables within N hops of a given table, "+
+			"with each table's key columns and each relationship's column pairs, cardinality and actions. "+
+			"Can render the graph as a Mermaid erDiagram, Graphviz DOT or PlantUML diagram"),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString(tableKey,
+			mcp.Description("Table to centre the graph on (default: every table in the schema)"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description(fmt.Sprintf("Number of foreign-key hops to follow from the table, in either direction (default: %d, max: %d)",
+				app.DefaultRelationshipDepth, app.MaxRelationshipDepth)),
+		),
+		mcp.WithString("diagram",
+			mcp.Description("Return the graph as diagram source instead of data: "+strings.Join(app.DiagramFormats(), ", ")),
+			mcp.Enum(app.DiagramFormats()...),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(getRelationshipsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received get_relationships tool request", "args", args)
+
+		table, _ := args[tableKey].(string)
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		depth := 0
+		if depthFloat, ok := args["depth"].(float64); ok && depthFloat > 0 {
+			depth = int(depthFloat)
+		}
+
+		diagram, _ := args["diagram"].(string)
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if diagram != "" && format != "" {
+			return mcp.NewToolResultError("diagram and format cannot be combined"), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		graph, err := appInstance.GetRelationships(qctx, schema, table, depth)
+		if err != nil {
+			debugLogger.Error("Failed to get relationships", "error", err, schemaKey, schema, tableKey, table)
+			return mcp.NewToolResultError(publicError("Failed to get relationships", err)), nil
+		}
+
+		var out string
+		if diagram != "" {
+			out, err = graph.Diagram(diagram)
+		} else {
+			out, err = renderResult(graph, format, debugLogger, "Failed to format relationships response")
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully got relationships", "table_count", len(graph.Tables),
+			"relationship_count", len(graph.Relationships), schemaKey, schema, tableKey, table)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupFindJoinPathTool creates and registers the find_join_path tool.
+func setupFindJoinPathTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	findJoinPathTool := mcp.NewTool("find_join_path",
+		mcp.WithDescription("Find the shortest chain of joins between two tables over the foreign-key graph, "+
+			"following foreign keys in either direction, and return each join with its exact column pairs "+
+			"and the FROM ... JOIN ... ON clause ready to paste into SQL"),
+		mcp.WithString("from_table",
+			mcp.Required(),
+			mcp.Description("Table to start from"),
+		),
+		mcp.WithString("to_table",
+			mcp.Required(),
+			mcp.Description("Table to reach"),
+		),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema of from_table (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString("to_schema",
+			mcp.Description("Schema of to_table (default: the value of schema)"),
+		),
+		mcp.WithBoolean("include_inferred",
+			mcp.Description("Also follow columns named <table>_id that have no foreign key but match the single-column "+
+				"primary key of a table named <table> or its plural (default: false)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(findJoinPathTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received find_join_path tool request", "args", args)
+
+		opts := &app.JoinPathOptions{FromSchema: app.DefaultSchema}
+		opts.FromTable, _ = args["from_table"].(string)
+		opts.ToTable, _ = args["to_table"].(string)
+		if opts.FromTable == "" || opts.ToTable == "" {
+			debugLogger.Error("from_table or to_table is missing")
+			return mcp.NewToolResultError("from_table and to_table must be non-empty strings"), nil
+		}
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			opts.FromSchema = schemaArg
+		}
+		opts.ToSchema = opts.FromSchema
+		if toSchemaArg, ok := args["to_schema"].(string); ok && toSchemaArg != "" {
+			opts.ToSchema = toSchemaArg
+		}
+		opts.IncludeInferred, _ = args["include_inferred"].(bool)
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		path, err := appInstance.FindJoinPath(qctx, opts)
+		if err != nil {
+			debugLogger.Error("Failed to find join path", "error", err, "from_table", opts.FromTable, "to_table", opts.ToTable)
+			return mcp.NewToolResultError(publicError("Failed to find join path", err)), nil
+		}
+
+		out, err := renderResult(path, format, debugLogger, "Failed to format join path response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully found join path", "join_count", len(path.Joins),
+			"from_table", opts.FromTable, "to_table", opts.ToTable)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupSearchSchemaTool creates and registers the search_schema tool.
+func setupSearchSchemaTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	searchSchemaTool := mcp.NewTool("search_schema",
+		mcp.WithDescription("Search every schema the user may access for tables, views, columns, functions and procedures "+
+			"whose name or comment matches a pattern, ranked by relevance"),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Text to search for, interpreted according to mode"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("How to match: ilike (an ILIKE pattern, or a substring when it has no %), "+
+				"regex (a case-insensitive regular expression) or similarity (pg_trgm trigram similarity, "+
+				"requires the pg_trgm extension) (default: ilike)"),
+			mcp.Enum(app.SearchModes()...),
+		),
+		mcp.WithArray("types",
+			mcp.Description("Only return objects of these types (default: all)"),
+			mcp.WithStringEnumItems(app.SearchObjectTypes()),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results (default: %d, max: %d)",
+				app.DefaultSearchLimit, app.MaxSearchLimit)),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(searchSchemaTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received search_schema tool request", "args", args)
+
+		opts := app.SearchOptions{}
+		opts.Pattern, _ = args["pattern"].(string)
+		if strings.TrimSpace(opts.Pattern) == "" {
+			debugLogger.Error("pattern is missing or not a string")
+			return mcp.NewToolResultError("pattern must be a non-empty string"), nil
+		}
+		opts.Mode, _ = args["mode"].(string)
+		if limitFloat, ok := args["limit"].(float64); ok && limitFloat > 0 {
+			opts.Limit = int(limitFloat)
+		}
+
+		types, err := extractObjectTypes(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts.Types = types
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		results, err := appInstance.SearchSchema(qctx, opts)
+		if err != nil {
+			debugLogger.Error("Failed to search schema", "error", err, "pattern", opts.Pattern, "mode", opts.Mode)
+			return mcp.NewToolResultError(publicError("Failed to search schema", err)), nil
+		}
+
+		out, err := renderResult(results, format, debugLogger, "Failed to format search results")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully searched schema", "count", len(results), "pattern", opts.Pattern)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupFindValueTool creates and registers the find_value tool.
+func setupFindValueTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	findValueTool := mcp.NewTool("find_value",
+		mcp.WithDescription("Find which columns of which tables hold a value (e.g. a customer id) and in how many rows, "+
+			"searching every text, integer, numeric, floating-point and uuid column the value fits. "+
+			"Each table is probed under its own timeout and no row data is returned"),
+		mcp.WithString("value",
+			mcp.Required(),
+			mcp.Description("Value to look for, compared for equality"),
+		),
+		mcp.WithString(schemaKey,
+			mcp.Description("Only search this schema (default: every schema)"),
+		),
+		mcp.WithString(tableKey,
+			mcp.Description("Only search tables with this name (default: every table)"),
+		),
+		mcp.WithNumber("max_tables",
+			mcp.Description(fmt.Sprintf("Maximum number of tables to probe (default: %d, max: %d)",
+				app.DefaultFindValueTables, app.MaxFindValueTables)),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(findValueTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		// The value may be personal data, so the raw args are not logged.
+		debugLogger.Debug("Received find_value tool request")
+
+		opts := app.FindValueOptions{}
+		switch v := args["value"].(type) {
+		case string:
+			opts.Value = v
+		case float64:
+			opts.Value = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if opts.Value == "" {
+			debugLogger.Error("value is missing or not a string")
+			return mcp.NewToolResultError("value must be a non-empty string"), nil
+		}
+		opts.Schema, _ = args[schemaKey].(string)
+		opts.Table, _ = args[tableKey].(string)
+		if maxTablesFloat, ok := args["max_tables"].(float64); ok && maxTablesFloat > 0 {
+			opts.MaxTables = int(maxTablesFloat)
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		search, err := appInstance.FindValue(qctx, opts)
+		if err != nil {
+			debugLogger.Error("Failed to find value", "error", err, schemaKey, opts.Schema, tableKey, opts.Table)
+			return mcp.NewToolResultError(publicError("Failed to find value", err)), nil
+		}
+
+		out, err := renderResult(search, format, debugLogger, "Failed to format find_value response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully searched for value", "match_count", len(search.Matches),
+			"tables_searched", search.TablesSearched, "tables_not_searched", search.TablesNotSearched)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupSnapshotSchemaTool creates and registers the snapshot_schema tool.
+func setupSnapshotSchemaTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	snapshotSchemaTool := mcp.NewTool("snapshot_schema",
+		mcp.WithDescription("Capture the tables, columns, constraints, indexes, views and functions of a schema "+
+			"as a versioned JSON snapshot, to be stored (e.g. in a file) and later compared with the live schema "+
+			"by passing it to diff_schema"),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+	)
+
+	s.AddTool(snapshotSchemaTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received snapshot_schema tool request", "args", args)
+
+		schema, _ := args[schemaKey].(string)
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		snapshot, err := appInstance.SnapshotSchema(qctx, schema)
+		if err != nil {
+			debugLogger.Error("Failed to snapshot schema", "error", err, schemaKey, schema)
+			return mcp.NewToolResultError(publicError("Failed to snapshot schema", err)), nil
+		}
+
+		out, err := renderResult(snapshot, app.FormatJSON, debugLogger, "Failed to format snapshot_schema response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully captured schema snapshot", schemaKey, snapshot.Schema,
+			"table_count", len(snapshot.Tables), "view_count", len(snapshot.Views), "function_count", len(snapshot.Functions))
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// extractSnapshot parses the snapshot argument of diff_schema, given as the
+// JSON text snapshot_schema returned or, from clients that decode it, as
+// the equivalent object.
+func extractSnapshot(arg any) (*app.SchemaSnapshot, error) {
+	data, ok := arg.(string)
+	if !ok {
+		encoded, err := json.Marshal(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", app.ErrInvalidSnapshot, err)
+		}
+		data = string(encoded)
+	}
+	return app.ParseSchemaSnapshot([]byte(data))
+}
+
+// setupDiffSchemaTool creates and registers the diff_schema tool.
+func setupDiffSchemaTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	diffSchemaTool := mcp.NewTool("diff_schema",
+		mcp.WithDescription("Compare two schemas, of the connected database or of it and another database, "+
+			"and report the tables, columns (type, nullability, default), constraints, indexes, views and functions "+
+			"added, removed or changed from the source to the target, optionally with the migration DDL that "+
+			"brings the source in line with the target"),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Source schema, on the connected database (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString("target_schema",
+			mcp.Description("Target schema (default: the source schema)"),
+		),
+		mcp.WithString("target_connection_url",
+			mcp.Description("PostgreSQL connection URL of the target database, opened read-only for the comparison "+
+				"(default: the connected database)"),
+		),
+		mcp.WithString("snapshot",
+			mcp.Description("Snapshot returned earlier by snapshot_schema, as JSON, to compare in place of the source "+
+				"schema (target_schema then defaults to the snapshot's schema)"),
+		),
+		mcp.WithBoolean("migration",
+			mcp.Description("Include the DDL script that turns the source into the target (default: false)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(diffSchemaTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		opts := app.DiffSchemaOptions{}
+		opts.Schema, _ = args[schemaKey].(string)
+		opts.TargetSchema, _ = args["target_schema"].(string)
+		opts.TargetConnection, _ = args["target_connection_url"].(string)
+		opts.Migration, _ = args["migration"].(bool)
+		// The target URL may hold a password, so it is not logged.
+		debugLogger.Debug("Received diff_schema tool request", schemaKey, opts.Schema,
+			"target_schema", opts.TargetSchema, "target_connection", opts.TargetConnection != "",
+			"snapshot", args["snapshot"] != nil)
+
+		if snapshotArg, ok := args["snapshot"]; ok && snapshotArg != nil && snapshotArg != "" {
+			if opts.Schema != "" {
+				return mcp.NewToolResultError("schema and snapshot cannot be combined"), nil
+			}
+			snapshot, err := extractSnapshot(snapshotArg)
+			if err != nil {
+				debugLogger.Error("Invalid snapshot", "error", err)
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			opts.Snapshot = snapshot
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		diff, err := appInstance.DiffSchema(qctx, opts)
+		if err != nil {
+			debugLogger.Error("Failed to diff schema", "error", err, schemaKey, opts.Schema, "target_schema", opts.TargetSchema)
+			if errors.Is(err, app.ErrTargetConnectionFailed) {
+				return mcp.NewToolResultError(
+					"Failed to connect to the target database. Verify target_connection_url and check server logs for details."), nil
+			}
+			return mcp.NewToolResultError(publicError("Failed to diff schema", err)), nil
+		}
+
+		out, err := renderResult(diff, format, debugLogger, "Failed to format diff_schema response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully diffed schema", "change_count", len(diff.Changes),
+			schemaKey, diff.SourceSchema, "target_schema", diff.TargetSchema)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
+// setupGenerateTypesTool creates and registers the generate_types tool.
+func setupGenerateTypesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	generateTypesTool := mcp.NewTool("generate_types",
+		mcp.WithDescription("Generate Go structs, TypeScript interfaces, a JSON Schema or Pydantic models for the tables "+
+			"of a schema, mapping each column's PostgreSQL type with its nullability, array dimensions, enum labels, "+
+			"numeric precision and length. A custom Go text/template can replace the language's layout"),
+		mcp.WithString("language",
+			mcp.Required(),
+			mcp.Description("Language to generate: "+strings.Join(app.TypeLanguages(), ", ")),
+			mcp.Enum(app.TypeLanguages()...),
+		),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString(tableKey,
+			mcp.Description("Table, view or materialized view to generate (default: every one in the schema)"),
+		),
+		mcp.WithString("template",
+			mcp.Description("Go text/template executed instead of the language's own, with .Schema, .Imports, "+
+				".Enums (.Name, .Members) and .Types (.Name, .Table, .Fields with .Name, .Type and .Column)"),
+		),
+	)
+
+	s.AddTool(generateTypesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received generate_types tool request", "args", args)
+
+		opts := app.GenerateTypesOptions{}
+		opts.Language, _ = args["language"].(string)
+		opts.Schema, _ = args[schemaKey].(string)
+		opts.Table, _ = args[tableKey].(string)
+		opts.Template, _ = args["template"].(string)
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		types, err := appInstance.GenerateTypes(qctx, opts)
+		if err != nil {
+			debugLogger.Error("Failed to generate types", "error", err, "language", opts.Language,
+				schemaKey, opts.Schema, tableKey, opts.Table)
+			return mcp.NewToolResultError(publicError("Failed to generate types", err)), nil
+		}
+
+		debugLogger.Info("Successfully generated types", "language", types.Language, "type_count", len(types.Types),
+			"enum_count", len(types.Enums), schemaKey, types.Schema, tableKey, opts.Table)
+		return mcp.NewToolResultText(types.Code), nil
+	})
+}
+
+// setupGetDependenciesTool creates and registers the get_dependencies tool.
+func setupGetDependenciesTool(s *server.MCPServer, appInstance *app.App, debugLogger *slog.Logger) {
+	getDependenciesTool := mcp.NewTool("get_dependencies",
+		mcp.WithDescription("List the objects that depend on a table or one of its columns before changing or dropping it: "+
+			"views and materialized views (recursively), functions, foreign keys, constraints, indexes, triggers, "+
+			"policies, rules, sequences and generated columns, with whether DROP would need CASCADE. "+
+			"Functions whose source mentions the table are included as source dependents"),
+		mcp.WithString(tableKey,
+			mcp.Required(),
+			mcp.Description("Table name"),
+		),
+		mcp.WithString(schemaKey,
+			mcp.Description(fmt.Sprintf("Schema name (default: %s)", app.DefaultSchema)),
+		),
+		mcp.WithString("column",
+			mcp.Description("Column to list the dependents of (default: the whole table)"),
+		),
+		withFormatOption(),
+	)
+
+	s.AddTool(getDependenciesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		debugLogger.Debug("Received get_dependencies tool request", "args", args)
+
+		table, _ := args[tableKey].(string)
+		column, _ := args["column"].(string)
+		schema := app.DefaultSchema
+		if schemaArg, ok := args[schemaKey].(string); ok && schemaArg != "" {
+			schema = schemaArg
+		}
+
+		format, err := extractFormat(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		qctx, cancel := withQueryTimeout(ctx)
+		defer cancel()
+
+		report, err := appInstance.GetDependencies(qctx, schema, table, column)
+		if err != nil {
+			debugLogger.Error("Failed to get dependencies", "error", err, schemaKey, schema, tableKey, table, "column", column)
+			return mcp.NewToolResultError(publicError("Failed to get dependencies", err)), nil
+		}
+
+		out, err := renderResult(report, format, debugLogger, "Failed to format dependencies response")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		debugLogger.Info("Successfully got dependencies", "dependency_count", len(report.Dependencies),
+			schemaKey, schema, tableKey, table, "column", column)
+		return mcp.NewToolResultText(out), nil
+	})
+}
+
 func printHelp() {
 	fmt.Printf(`PostgreSQL MCP Server %s
 
@@ -692,6 +1935,7 @@ ENVIRONMENT VARIABLES (OPTIONAL):
     POSTGRES_MCP_MAX_IDLE_CONNS     Maximum idle database connections (default: 5)
     POSTGRES_MCP_CONN_MAX_LIFETIME  Connection max lifetime in seconds (default: 3600)
     POSTGRES_MCP_CONN_MAX_IDLE_TIME Connection max idle time in seconds (default: 600)
+    POSTGRES_MCP_HEALTH_CHECK_PERIOD Pool health-check interval in seconds (default: 60)
     POSTGRES_MCP_MAX_RESULT_ROWS    Maximum rows returned per query (default: 10000)
     POSTGRES_MCP_LOG_LEVEL          Log level: debug, info, warn, error (default: info)
     POSTGRES_MCP_QUERY_TIMEOUT      Per-tool-call timeout. Duration ("30s", "2m") or
@@ -710,6 +1954,27 @@ DESCRIPTION:
     • list_indexes        - List indexes for a specific table
     • explain_query       - Get execution plan for SQL queries
     • get_table_stats     - Get detailed statistics for a table
+    • describe_partitions - Get the partition tree of a partitioned table
+    • get_view_definition - Get the SQL and dependencies of a view
+    • list_triggers       - List the triggers of a table
+    • list_functions      - List functions and procedures in a schema
+    • get_function_definition - Get the source of a function or procedure
+    • list_types          - List enums, domains, composite and range types
+    • list_sequences      - List sequences with their current values
+    • list_extensions     - List available and installed extensions
+    • get_settings        - Get server configuration parameters
+    • list_roles          - List roles and their memberships
+    • get_table_privileges - Show a role's privileges on tables and columns
+    • list_policies       - List row-level security policies
+    • get_ddl             - Generate the DDL of a table or schema
+    • get_relationships   - Get the foreign-key graph as data or a diagram
+    • find_join_path      - Find the shortest chain of joins between two tables
+    • search_schema       - Search tables, columns and functions by name or comment
+    • find_value          - Find the columns and tables that hold a value
+    • diff_schema         - Compare two schemas and generate migration DDL
+    • snapshot_schema     - Capture a schema as a JSON snapshot for later comparison
+    • generate_types      - Generate Go, TypeScript, JSON Schema or Pydantic types from tables
+    • get_dependencies    - List the objects depending on a table or column
 
     The server communicates via JSON-RPC 2.0 over stdin/stdout and is designed
     to be used with Claude Code's MCP architecture.
@@ -793,6 +2058,27 @@ func registerAllTools(s *server.MCPServer, appInstance *app.App, debugLogger *sl
 	setupListIndexesTool(s, appInstance, debugLogger)
 	setupExplainQueryTool(s, appInstance, debugLogger)
 	setupGetTableStatsTool(s, appInstance, debugLogger)
+	setupDescribePartitionsTool(s, appInstance, debugLogger)
+	setupGetViewDefinitionTool(s, appInstance, debugLogger)
+	setupListTriggersTool(s, appInstance, debugLogger)
+	setupListFunctionsTool(s, appInstance, debugLogger)
+	setupGetFunctionDefinitionTool(s, appInstance, debugLogger)
+	setupListTypesTool(s, appInstance, debugLogger)
+	setupListSequencesTool(s, appInstance, debugLogger)
+	setupListExtensionsTool(s, appInstance, debugLogger)
+	setupGetSettingsTool(s, appInstance, debugLogger)
+	setupListRolesTool(s, appInstance, debugLogger)
+	setupGetTablePrivilegesTool(s, appInstance, debugLogger)
+	setupListPoliciesTool(s, appInstance, debugLogger)
+	setupGetDDLTool(s, appInstance, debugLogger)
+	setupGetRelationshipsTool(s, appInstance, debugLogger)
+	setupFindJoinPathTool(s, appInstance, debugLogger)
+	setupSearchSchemaTool(s, appInstance, debugLogger)
+	setupFindValueTool(s, appInstance, debugLogger)
+	setupDiffSchemaTool(s, appInstance, debugLogger)
+	setupSnapshotSchemaTool(s, appInstance, debugLogger)
+	setupGenerateTypesTool(s, appInstance, debugLogger)
+	setupGetDependenciesTool(s, appInstance, debugLogger)
 }
 
 func main() {
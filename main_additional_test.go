@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -13,7 +14,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -445,21 +446,84 @@ func (s *stubFailingClient) GetCurrentDatabase(_ context.Context) (string, error
 func (s *stubFailingClient) ListSchemas(_ context.Context) ([]*app.SchemaInfo, error) {
 	return nil, errors.New("stub")
 }
-func (s *stubFailingClient) ListTables(_ context.Context, _ string) ([]*app.TableInfo, error) {
+func (s *stubFailingClient) ListTables(_ context.Context, _ *app.ListTablesOptions) ([]*app.TableInfo, error) {
 	return nil, errors.New("stub")
 }
-func (s *stubFailingClient) ListTablesWithStats(_ context.Context, _ string) ([]*app.TableInfo, error) {
+func (s *stubFailingClient) ListTablesWithStats(_ context.Context, _ *app.ListTablesOptions) ([]*app.TableInfo, error) {
 	return nil, errors.New("stub")
 }
 func (s *stubFailingClient) DescribeTable(_ context.Context, _, _ string) ([]*app.ColumnInfo, error) {
 	return nil, errors.New("stub")
 }
+func (s *stubFailingClient) ListConstraints(_ context.Context, _, _ string) ([]*app.ConstraintInfo, error) {
+	return nil, errors.New("stub")
+}
 func (s *stubFailingClient) GetTableStats(_ context.Context, _, _ string) (*app.TableInfo, error) {
 	return nil, errors.New("stub")
 }
 func (s *stubFailingClient) ListIndexes(_ context.Context, _, _ string) ([]*app.IndexInfo, error) {
 	return nil, errors.New("stub")
 }
+func (s *stubFailingClient) DescribePartitions(_ context.Context, _, _ string) (*app.PartitionTree, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) GetViewDefinition(_ context.Context, _, _ string) (*app.ViewDefinition, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListTriggers(_ context.Context, _, _ string) ([]*app.TriggerInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListFunctions(_ context.Context, _ string) ([]*app.FunctionInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) GetFunctionDefinition(_ context.Context, _, _, _ string) ([]*app.FunctionDefinition, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListTypes(_ context.Context, _ string) ([]*app.TypeInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListSequences(_ context.Context, _ string) ([]*app.SequenceInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListExtensions(_ context.Context) ([]*app.ExtensionInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) GetSettings(_ context.Context, _ app.SettingsOptions) ([]*app.SettingInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListRoles(_ context.Context) ([]*app.RoleInfo, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) GetTablePrivileges(_ context.Context, _, _, _ string) ([]*app.TablePrivileges, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListPolicies(_ context.Context, _, _ string) ([]*app.TableSecurity, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) GetDDL(_ context.Context, _, _ string) (*app.DDLScript, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListRelationships(_ context.Context) (*app.RelationshipGraph, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListIDColumns(_ context.Context) ([]*app.IDColumn, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) SearchSchema(_ context.Context, _ app.SearchOptions) ([]*app.SearchResult, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) FindValue(_ context.Context, _ app.FindValueOptions) (*app.ValueSearch, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) SnapshotSchema(_ context.Context, _ string) (*app.SchemaSnapshot, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) ListCodegenTables(_ context.Context, _, _ string) ([]*app.CodegenTable, error) {
+	return nil, errors.New("stub")
+}
+func (s *stubFailingClient) GetDependencies(_ context.Context, _, _, _ string) (*app.DependencyReport, error) {
+	return nil, errors.New("stub")
+}
 func (s *stubFailingClient) ExecuteQuery(_ context.Context, _ string, _ ...any) (*app.QueryResult, error) {
 	return nil, errors.New("stub")
 }
@@ -467,27 +531,29 @@ func (s *stubFailingClient) ExplainQuery(_ context.Context, _ string, _ bool, _
 	return nil, errors.New("stub")
 }
 
-// TestPublicError_StripsLibPqMetadata locks in that the helper extracts only
-// Message and the SQLSTATE Code.Name() from *pq.Error, dropping every other
-// field that could leak server internals (Detail / Hint / Where / Routine /
-// File / Line / Schema / Table / Column / Constraint / DataTypeName). Also
-// covers the wrapped-error path through errors.As (issue #88).
-func TestPublicError_StripsLibPqMetadata(t *testing.T) {
-	pqErr := &pq.Error{
-		Code:         "42P01", // undefined_table
-		Severity:     "ERROR",
-		Message:      `relation "users" does not exist`,
-		Detail:       "SECRET-DETAIL",
-		Hint:         "SECRET-HINT",
-		Where:        "SECRET-WHERE",
-		Routine:      "SECRET-ROUTINE",
-		File:         "SECRET-FILE",
-		Line:         "999",
-		Schema:       "SECRET-SCHEMA",
-		Table:        "SECRET-TABLE",
-		Column:       "SECRET-COLUMN",
-		Constraint:   "SECRET-CONSTRAINT",
-		DataTypeName: "SECRET-DATATYPE",
+// TestPublicError_StripsPgErrorMetadata locks in that the helper extracts
+// only Message, the SQLSTATE code and name, and the query position from
+// *pgconn.PgError, dropping every other field that could leak server
+// internals (Detail / Hint / Where / Routine / File / Line / Schema / Table /
+// Column / Constraint / DataTypeName). Also covers the wrapped-error path
+// through errors.As (issue #88).
+func TestPublicError_StripsPgErrorMetadata(t *testing.T) {
+	pgErr := &pgconn.PgError{
+		Code:           "42P01", // undefined_table
+		Severity:       "ERROR",
+		Message:        `relation "users" does not exist`,
+		Detail:         "SECRET-DETAIL",
+		Hint:           "SECRET-HINT",
+		Where:          "SECRET-WHERE",
+		Routine:        "SECRET-ROUTINE",
+		File:           "SECRET-FILE",
+		Line:           999,
+		Position:       15,
+		SchemaName:     "SECRET-SCHEMA",
+		TableName:      "SECRET-TABLE",
+		ColumnName:     "SECRET-COLUMN",
+		ConstraintName: "SECRET-CONSTRAINT",
+		DataTypeName:   "SECRET-DATATYPE",
 	}
 	leaks := []string{
 		"SECRET-DETAIL", "SECRET-HINT", "SECRET-WHERE",
@@ -496,18 +562,17 @@ func TestPublicError_StripsLibPqMetadata(t *testing.T) {
 		"SECRET-DATATYPE",
 	}
 
-	t.Run("direct pq.Error", func(t *testing.T) {
-		out := publicError("Failed to run query", pqErr)
-		assert.Contains(t, out, "Failed to run query")
-		assert.Contains(t, out, `relation "users" does not exist`, "Message must survive")
-		assert.Contains(t, out, "undefined_table", "SQLSTATE name must survive (42P01 -> undefined_table)")
+	t.Run("direct PgError", func(t *testing.T) {
+		out := publicError("Failed to run query", pgErr)
+		assert.Equal(t, `Failed to run query: relation "users" does not exist `+
+			`(SQLSTATE 42P01 undefined_table, position 15)`, out)
 		for _, leak := range leaks {
 			assert.NotContains(t, out, leak, "leak %q must not appear", leak)
 		}
 	})
 
-	t.Run("wrapped pq.Error (errors.As must unwrap)", func(t *testing.T) {
-		wrapped := fmt.Errorf("outer wrap: %w", pqErr)
+	t.Run("wrapped PgError (errors.As must unwrap)", func(t *testing.T) {
+		wrapped := fmt.Errorf("outer wrap: %w", pgErr)
 		out := publicError("ctx", wrapped)
 		assert.Contains(t, out, `relation "users" does not exist`)
 		assert.Contains(t, out, "undefined_table")
@@ -515,9 +580,19 @@ func TestPublicError_StripsLibPqMetadata(t *testing.T) {
 			assert.NotContains(t, out, leak)
 		}
 	})
+
+	t.Run("every listed code has its own name", func(t *testing.T) {
+		out := publicError("ctx", &pgconn.PgError{Code: "42P07", Message: `relation "users" already exists`})
+		assert.Equal(t, `ctx: relation "users" already exists (SQLSTATE 42P07 duplicate_table)`, out)
+	})
+
+	t.Run("unknown code falls back to its labelled class", func(t *testing.T) {
+		out := publicError("ctx", &pgconn.PgError{Code: "22P99", Message: "bad data"})
+		assert.Equal(t, "ctx: bad data (SQLSTATE 22P99 class 22 data_exception)", out)
+	})
 }
 
-// TestPublicError_PassesThroughSentinelErrors verifies that non-pq errors —
+// TestPublicError_PassesThroughSentinelErrors verifies that non-PostgreSQL errors —
 // app-level sentinels and other plain errors — flow through unchanged with
 // just the prefix prepended. These messages are already curated and safe.
 func TestPublicError_PassesThroughSentinelErrors(t *testing.T) {
@@ -540,12 +615,12 @@ func TestPublicError_PassesThroughSentinelErrors(t *testing.T) {
 
 // TestHandleConnectDatabaseRequest_DoesNotLeakErrorDetails exercises the
 // connect_database handler with a stub client whose Connect returns a
-// fully-loaded *pq.Error simulating an authentication failure that exposes
+// fully-loaded *pgconn.PgError simulating an authentication failure that exposes
 // host, port, username, and PostgreSQL source location. The handler must
 // return the fixed generic message regardless — none of the sensitive
 // fields may appear in the MCP response text (issue #88).
 func TestHandleConnectDatabaseRequest_DoesNotLeakErrorDetails(t *testing.T) {
-	leakyErr := &pq.Error{
+	leakyErr := &pgconn.PgError{
 		Code:    "28P01", // invalid_password
 		Message: `password authentication failed for user "admin"`,
 		Detail:  "Connection from 10.0.0.5:5432 rejected",
@@ -553,7 +628,7 @@ func TestHandleConnectDatabaseRequest_DoesNotLeakErrorDetails(t *testing.T) {
 		Where:   "auth.c line 1234",
 		Routine: "auth_failed",
 		File:    "auth.c",
-		Line:    "1234",
+		Line:    1234,
 	}
 	silent := slog.New(slog.DiscardHandler)
 
@@ -726,3 +801,132 @@ func TestResolveLogLevel(t *testing.T) {
 		})
 	}
 }
+
+// TestRenderResult covers the shared format dispatch: the default keeps the
+// tool's historical JSON shape, other formats go through app.EncodeResult
+// (tabulating metadata structs first), and unknown formats are rejected
+// before any query runs.
+func TestRenderResult(t *testing.T) {
+	silent := slog.New(slog.DiscardHandler)
+	tables := []*app.TableInfo{{Schema: "public", Name: "users", Type: "table", Owner: "postgres"}}
+
+	t.Run("default keeps native JSON", func(t *testing.T) {
+		want, err := json.Marshal(tables)
+		require.NoError(t, err)
+		for _, format := range []string{"", app.FormatJSON} {
+			got, err := renderResult(tables, format, silent, "ctx")
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+		}
+	})
+
+	t.Run("structs are tabulated", func(t *testing.T) {
+		got, err := renderResult(tables, app.FormatCSV, silent, "ctx")
+		require.NoError(t, err)
+		assert.Equal(t, "schema,name,type,row_count,size,owner,description,partition_of,partition_bound,partitions\n"+
+			"public,users,table,0,,postgres,,,,\n", got)
+	})
+
+	t.Run("query results are encoded directly", func(t *testing.T) {
+		result := &app.QueryResult{Columns: []string{"n"}, Rows: [][]any{{int64(1)}}, RowCount: 1}
+		got, err := renderResult(result, app.FormatCompact, silent, "ctx")
+		require.NoError(t, err)
+		assert.Equal(t, `[["n"],[1]]`, got)
+	})
+
+	t.Run("table descriptions are rendered per section", func(t *testing.T) {
+		description := &app.TableDescription{
+			Schema:      "public",
+			Name:        "users",
+			Columns:     []*app.ColumnInfo{{Name: "id", DataType: "integer"}},
+			Constraints: []*app.ConstraintInfo{},
+		}
+		got, err := renderResult(description, app.FormatRecords, silent, "ctx")
+		require.NoError(t, err)
+
+		var decoded map[string][]map[string]any
+		require.NoError(t, json.Unmarshal([]byte(got), &decoded))
+		require.Len(t, decoded["columns"], 1)
+		assert.Equal(t, "id", decoded["columns"][0]["name"])
+		assert.Equal(t, "integer", decoded["columns"][0]["data_type"])
+		assert.Empty(t, decoded["constraints"])
+	})
+
+	t.Run("extractFormat rejects unknown formats", func(t *testing.T) {
+		_, err := extractFormat(map[string]any{formatKey: "xml"})
+		assert.ErrorIs(t, err, app.ErrUnsupportedFormat)
+
+		format, err := extractFormat(map[string]any{formatKey: " Markdown "})
+		require.NoError(t, err)
+		assert.Equal(t, app.FormatMarkdown, format)
+
+		format, err = extractFormat(map[string]any{})
+		require.NoError(t, err)
+		assert.Empty(t, format)
+	})
+}
+
+func TestExtractTableTypes(t *testing.T) {
+	types, err := extractTableTypes(map[string]any{})
+	require.NoError(t, err)
+	assert.Nil(t, types)
+
+	types, err = extractTableTypes(map[string]any{"types": "view"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"view"}, types)
+
+	types, err = extractTableTypes(map[string]any{"types": []any{"table", "materialized view"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"table", "materialized view"}, types)
+
+	_, err = extractTableTypes(map[string]any{"types": []any{float64(1)}})
+	assert.ErrorIs(t, err, app.ErrInvalidTableType)
+
+	_, err = extractTableTypes(map[string]any{"types": true})
+	assert.ErrorIs(t, err, app.ErrInvalidTableType)
+}
+
+func TestExtractObjectTypes(t *testing.T) {
+	types, err := extractObjectTypes(map[string]any{"types": []any{"column", "function"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"column", "function"}, types)
+
+	_, err = extractObjectTypes(map[string]any{"types": []any{true}})
+	assert.ErrorIs(t, err, app.ErrInvalidObjectType)
+}
+
+func TestExtractSnapshot(t *testing.T) {
+	snapshot, err := extractSnapshot(`{"version":1,"schema":"public","tables":[{"name":"orders"}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "public", snapshot.Schema)
+	require.Len(t, snapshot.Tables, 1)
+	assert.Equal(t, "orders", snapshot.Tables[0].Name)
+
+	snapshot, err = extractSnapshot(map[string]any{"version": float64(1), "schema": "sales"})
+	require.NoError(t, err)
+	assert.Equal(t, "sales", snapshot.Schema)
+
+	_, err = extractSnapshot("not json")
+	assert.ErrorIs(t, err, app.ErrInvalidSnapshot)
+
+	_, err = extractSnapshot(map[string]any{"version": float64(99), "schema": "sales"})
+	assert.ErrorIs(t, err, app.ErrUnsupportedSnapshot)
+}
+
+func TestExtractParams(t *testing.T) {
+	query, args, err := extractParams("SELECT * FROM t WHERE id = :id", map[string]any{
+		"query":  "ignored",
+		"params": map[string]any{"id": float64(5)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = $1", query)
+	assert.Equal(t, []any{int64(5)}, args)
+
+	query, args, err = extractParams("SELECT 1", map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", query)
+	assert.Nil(t, args)
+
+	_, _, err = extractParams("SELECT $1", map[string]any{"params": []any{}})
+	assert.ErrorIs(t, err, app.ErrInvalidParams)
+}
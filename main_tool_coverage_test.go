@@ -57,6 +57,87 @@ func TestAllToolSetupFunctions(t *testing.T) {
 	assert.NotPanics(t, func() {
 		setupGetTableStatsTool(s, appInstance, logger)
 	})
+
+	assert.NotPanics(t, func() {
+		setupDescribePartitionsTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetViewDefinitionTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListTriggersTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListFunctionsTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetFunctionDefinitionTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListTypesTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListSequencesTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListExtensionsTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetSettingsTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListRolesTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetTablePrivilegesTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupListPoliciesTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetDDLTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetRelationshipsTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupFindJoinPathTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupSearchSchemaTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupFindValueTool(s, appInstance, logger)
+	})
+	assert.NotPanics(t, func() {
+		setupDiffSchemaTool(s, appInstance, logger)
+	})
+	assert.NotPanics(t, func() {
+		setupSnapshotSchemaTool(s, appInstance, logger)
+	})
+	assert.NotPanics(t, func() {
+		setupGenerateTypesTool(s, appInstance, logger)
+	})
+
+	assert.NotPanics(t, func() {
+		setupGetDependenciesTool(s, appInstance, logger)
+	})
 }
 
 // Test parameter validation error handling in tool handlers